@@ -0,0 +1,173 @@
+package osc
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net"
+	"sort"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// Common errors.
+var (
+	ErrDuplicatePeer = errors.New("peer already exists")
+	ErrEmptyPeerName = errors.New("peer name must not be empty")
+)
+
+// Peer is a known OSC peer.
+type Peer struct {
+	Name      string   `json:"name"`
+	Addr      string   `json:"addr"`
+	Addresses []string `json:"addresses"`
+}
+
+// AddressBook keeps track of known peers and the OSC addresses they support.
+// It is safe for concurrent use.
+// The zero value is an empty address book ready to use.
+type AddressBook struct {
+	mu    sync.RWMutex
+	peers map[string]Peer
+}
+
+// AddPeer adds a peer with the given name, network address (host:port),
+// and the OSC addresses it supports.
+// An error is returned if a peer with the same name already exists,
+// or if any of the addresses are invalid.
+func (ab *AddressBook) AddPeer(name, addr string, addresses []string) error {
+	p := Peer{
+		Name:      name,
+		Addr:      addr,
+		Addresses: append([]string{}, addresses...),
+	}
+	if err := p.validate(); err != nil {
+		return err
+	}
+	ab.mu.Lock()
+	defer ab.mu.Unlock()
+
+	if _, ok := ab.peers[name]; ok {
+		return errors.Wrapf(ErrDuplicatePeer, "peer %q", name)
+	}
+	if ab.peers == nil {
+		ab.peers = map[string]Peer{}
+	}
+	ab.peers[name] = p
+	return nil
+}
+
+// RemovePeer removes the peer with the given name.
+// It returns false if there was no such peer.
+func (ab *AddressBook) RemovePeer(name string) bool {
+	ab.mu.Lock()
+	defer ab.mu.Unlock()
+
+	_, ok := ab.peers[name]
+	delete(ab.peers, name)
+	return ok
+}
+
+// LookupPeer returns the peer with the given name.
+func (ab *AddressBook) LookupPeer(name string) (Peer, bool) {
+	ab.mu.RLock()
+	defer ab.mu.RUnlock()
+
+	p, ok := ab.peers[name]
+	return p, ok
+}
+
+// Peers returns all the peers sorted by name.
+func (ab *AddressBook) Peers() []Peer {
+	ab.mu.RLock()
+	defer ab.mu.RUnlock()
+
+	peers := make([]Peer, 0, len(ab.peers))
+	for _, p := range ab.peers {
+		peers = append(peers, p)
+	}
+	sortPeers(peers)
+	return peers
+}
+
+// FindPeersForAddress returns the peers, sorted by name, that support
+// an OSC address matched by the given address pattern.
+func (ab *AddressBook) FindPeersForAddress(pattern string) ([]Peer, error) {
+	var (
+		msg   = Message{Address: pattern}
+		peers = []Peer{}
+	)
+	for _, p := range ab.Peers() {
+		for _, addr := range p.Addresses {
+			matched, err := msg.Match(addr, false)
+			if err != nil {
+				return nil, err
+			}
+			if matched {
+				peers = append(peers, p)
+				break
+			}
+		}
+	}
+	return peers, nil
+}
+
+// SaveJSON writes the address book to a JSON file.
+func (ab *AddressBook) SaveJSON(path string) error {
+	data, err := json.MarshalIndent(ab.Peers(), "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "marshal peers")
+	}
+	return errors.Wrap(ioutil.WriteFile(path, data, 0644), "write address book")
+}
+
+// LoadJSON replaces the contents of the address book with the peers in a JSON file
+// written by SaveJSON.
+func (ab *AddressBook) LoadJSON(path string) error {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return errors.Wrap(err, "read address book")
+	}
+	loaded := []Peer{}
+	if err := json.Unmarshal(data, &loaded); err != nil {
+		return errors.Wrap(err, "unmarshal peers")
+	}
+	peers := make(map[string]Peer, len(loaded))
+	for _, p := range loaded {
+		if err := p.validate(); err != nil {
+			return err
+		}
+		if _, ok := peers[p.Name]; ok {
+			return errors.Wrapf(ErrDuplicatePeer, "peer %q", p.Name)
+		}
+		peers[p.Name] = p
+	}
+	ab.mu.Lock()
+	ab.peers = peers
+	ab.mu.Unlock()
+	return nil
+}
+
+// validate returns an error if the peer has an empty name,
+// a malformed network address, or an invalid OSC address.
+func (p Peer) validate() error {
+	if p.Name == "" {
+		return ErrEmptyPeerName
+	}
+	if _, _, err := net.SplitHostPort(p.Addr); err != nil {
+		return errors.Wrapf(err, "peer %q", p.Name)
+	}
+	for _, addr := range p.Addresses {
+		if err := ValidateAddress(addr); err != nil {
+			return errors.Wrapf(err, "peer %q address %q", p.Name, addr)
+		}
+	}
+	return nil
+}
+
+// sortPeers sorts peers by name.
+func sortPeers(peers []Peer) {
+	sort.Slice(peers, func(i, j int) bool {
+		return peers[i].Name < peers[j].Name
+	})
+}
@@ -0,0 +1,122 @@
+package osc
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pkg/errors"
+)
+
+func testAddressBook(t *testing.T) *AddressBook {
+	ab := &AddressBook{}
+	if err := ab.AddPeer("synth1", "192.168.1.10:9000", []string{"/freq", "/gain"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := ab.AddPeer("synth2", "192.168.1.11:9000", []string{"/freq", "/filter/cutoff"}); err != nil {
+		t.Fatal(err)
+	}
+	return ab
+}
+
+func peerNames(peers []Peer) []string {
+	names := make([]string, len(peers))
+	for i, p := range peers {
+		names[i] = p.Name
+	}
+	return names
+}
+
+func TestAddressBookAddPeer(t *testing.T) {
+	ab := testAddressBook(t)
+
+	if err := ab.AddPeer("synth1", "192.168.1.12:9000", nil); errors.Cause(err) != ErrDuplicatePeer {
+		t.Fatalf("expected ErrDuplicatePeer, got %v", err)
+	}
+	if err := ab.AddPeer("", "192.168.1.12:9000", nil); err != ErrEmptyPeerName {
+		t.Fatalf("expected ErrEmptyPeerName, got %v", err)
+	}
+	if err := ab.AddPeer("synth3", "192.168.1.12", nil); err == nil {
+		t.Fatal("expected error, got nil")
+	}
+	if err := ab.AddPeer("synth3", "192.168.1.12:9000", []string{"/f*"}); errors.Cause(err) != ErrInvalidAddress {
+		t.Fatalf("expected ErrInvalidAddress, got %v", err)
+	}
+}
+
+func TestAddressBookLookupPeer(t *testing.T) {
+	ab := testAddressBook(t)
+
+	p, ok := ab.LookupPeer("synth1")
+	if !ok {
+		t.Fatal("expected to find synth1")
+	}
+	if expected, got := "192.168.1.10:9000", p.Addr; expected != got {
+		t.Fatalf("expected %s, got %s", expected, got)
+	}
+	if _, ok := ab.LookupPeer("nope"); ok {
+		t.Fatal("expected not to find peer")
+	}
+	if !ab.RemovePeer("synth1") {
+		t.Fatal("expected to remove synth1")
+	}
+	if _, ok := ab.LookupPeer("synth1"); ok {
+		t.Fatal("expected synth1 to be removed")
+	}
+}
+
+func TestAddressBookFindPeersForAddress(t *testing.T) {
+	ab := testAddressBook(t)
+
+	for _, testcase := range []struct {
+		Pattern  string
+		Expected []string
+	}{
+		{Pattern: "/freq", Expected: []string{"synth1", "synth2"}},
+		{Pattern: "/gain", Expected: []string{"synth1"}},
+		{Pattern: "/filter/*", Expected: []string{"synth2"}},
+		{Pattern: "/nope", Expected: []string{}},
+	} {
+		peers, err := ab.FindPeersForAddress(testcase.Pattern)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got := peerNames(peers)
+		if len(testcase.Expected) != len(got) {
+			t.Fatalf("(%s) expected %v, got %v", testcase.Pattern, testcase.Expected, got)
+		}
+		for i := range got {
+			if testcase.Expected[i] != got[i] {
+				t.Fatalf("(%s) expected %v, got %v", testcase.Pattern, testcase.Expected, got)
+			}
+		}
+	}
+}
+
+func TestAddressBookJSON(t *testing.T) {
+	dir, err := ioutil.TempDir("", "osc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = os.RemoveAll(dir) }() // Best effort.
+
+	path := filepath.Join(dir, "peers.json")
+	if err := testAddressBook(t).SaveJSON(path); err != nil {
+		t.Fatal(err)
+	}
+	ab := &AddressBook{}
+	if err := ab.LoadJSON(path); err != nil {
+		t.Fatal(err)
+	}
+	p, ok := ab.LookupPeer("synth2")
+	if !ok {
+		t.Fatal("expected to find synth2")
+	}
+	if expected, got := 2, len(p.Addresses); expected != got {
+		t.Fatalf("expected %d addresses, got %d", expected, got)
+	}
+	if err := ab.LoadJSON(filepath.Join(dir, "missing.json")); err == nil {
+		t.Fatal("expected error, got nil")
+	}
+}
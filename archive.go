@@ -0,0 +1,151 @@
+package osc
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"io"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Archive file names.
+const (
+	archiveIndexName    = "index.json"
+	archiveMessagesName = "messages.bin"
+)
+
+// ArchiveIndex summarizes the messages in an archive.
+// It is stored as index.json in the archive.
+type ArchiveIndex struct {
+	Count     int       `json:"count"`
+	Start     time.Time `json:"start"`
+	End       time.Time `json:"end"`
+	Addresses []string  `json:"addresses"`
+}
+
+// SaveArchive writes the messages of a recorder to a zip file (by convention with
+// the .oscarchive extension) that contains an index.json with an ArchiveIndex, and
+// a messages.bin with the messages in the format written by Encoder.
+// Every message is stored in a bundle whose timetag is the time the message was recorded.
+func SaveArchive(recorder *Recorder, path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return errors.Wrap(err, "create archive")
+	}
+	if err := writeArchive(f, recorder.Messages()); err != nil {
+		_ = f.Close() // Best effort.
+		return err
+	}
+	return errors.Wrap(f.Close(), "close archive")
+}
+
+// writeArchive writes an archive of the recorded messages to w.
+func writeArchive(w io.Writer, messages []RecordedMessage) error {
+	var (
+		zw        = zip.NewWriter(w)
+		index     = ArchiveIndex{Count: len(messages), Addresses: []string{}}
+		addresses = map[string]struct{}{}
+	)
+	for i, rm := range messages {
+		if i == 0 || rm.Time.Before(index.Start) {
+			index.Start = rm.Time
+		}
+		if i == 0 || rm.Time.After(index.End) {
+			index.End = rm.Time
+		}
+		if _, ok := addresses[rm.Message.Address]; !ok {
+			addresses[rm.Message.Address] = struct{}{}
+			index.Addresses = append(index.Addresses, rm.Message.Address)
+		}
+	}
+	sort.Strings(index.Addresses)
+
+	iw, err := zw.Create(archiveIndexName)
+	if err != nil {
+		return errors.Wrap(err, "create index")
+	}
+	if err := json.NewEncoder(iw).Encode(index); err != nil {
+		return errors.Wrap(err, "write index")
+	}
+	mw, err := zw.Create(archiveMessagesName)
+	if err != nil {
+		return errors.Wrap(err, "create messages")
+	}
+	enc := NewEncoder(mw)
+	for _, rm := range messages {
+		if err := enc.Encode(Bundle{Timetag: FromTime(rm.Time), Packets: []Packet{rm.Message}}); err != nil {
+			return errors.Wrap(err, "write message")
+		}
+	}
+	return errors.Wrap(zw.Close(), "close zip writer")
+}
+
+// LoadArchive reads a recorder from an archive written by SaveArchive.
+// Unknown fields in the index and unknown files in the archive are ignored.
+func LoadArchive(path string) (*Recorder, error) {
+	zr, err := zip.OpenReader(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "open archive")
+	}
+	defer func() { _ = zr.Close() }() // Best effort.
+
+	var index ArchiveIndex
+	if err := readArchiveFile(&zr.Reader, archiveIndexName, func(r io.Reader) error {
+		return json.NewDecoder(r).Decode(&index)
+	}); err != nil {
+		return nil, err
+	}
+	recorder := NewRecorder()
+	if err := readArchiveFile(&zr.Reader, archiveMessagesName, func(r io.Reader) error {
+		return readArchiveMessages(r, recorder)
+	}); err != nil {
+		return nil, err
+	}
+	if index.Count != recorder.Len() {
+		return nil, errors.Wrapf(ErrParse, "index has %d messages, archive has %d", index.Count, recorder.Len())
+	}
+	return recorder, nil
+}
+
+// readArchiveFile calls fn with the contents of the named file in the archive.
+func readArchiveFile(zr *zip.Reader, name string, fn func(io.Reader) error) error {
+	for _, f := range zr.File {
+		if f.Name != name {
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return errors.Wrapf(err, "open %s", name)
+		}
+		defer func() { _ = rc.Close() }() // Best effort.
+
+		return errors.Wrapf(fn(rc), "read %s", name)
+	}
+	return errors.Wrapf(ErrParse, "missing %s", name)
+}
+
+// readArchiveMessages adds the messages in messages.bin to the recorder.
+func readArchiveMessages(r io.Reader, recorder *Recorder) error {
+	dec := NewDecoder(r)
+	for {
+		p, err := dec.Decode()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		b, ok := p.(Bundle)
+		if !ok || len(b.Packets) != 1 {
+			return errors.Wrap(ErrParse, "expected a bundle with one message")
+		}
+		msg, ok := b.Packets[0].(Message)
+		if !ok {
+			return errors.Wrap(ErrParse, "expected a bundle with one message")
+		}
+		recorder.add(RecordedMessage{Time: b.Timetag.Time(), Message: msg})
+	}
+}
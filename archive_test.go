@@ -0,0 +1,166 @@
+package osc
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+func TestArchive(t *testing.T) {
+	dir, err := ioutil.TempDir("", "osc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = os.RemoveAll(dir) }() // Best effort.
+
+	var (
+		now      = time.Date(2020, 1, 2, 3, 4, 5, 6, time.UTC)
+		start    = now
+		recorder = NewRecorder()
+		messages = []Message{
+			{Address: "/synth/freq", Arguments: []Argument{Float(440)}},
+			{Address: "/synth/gain", Arguments: []Argument{Float(0.5), String("db")}},
+			{Address: "/synth/freq", Arguments: []Argument{Float(880)}},
+		}
+	)
+	recorder.now = func() time.Time { return now }
+	for _, msg := range messages {
+		recorder.Record(msg)
+		now = now.Add(time.Millisecond)
+	}
+	path := filepath.Join(dir, "session.oscarchive")
+	if err := SaveArchive(recorder, path); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := LoadArchive(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected, got := recorder.Messages(), loaded.Messages()
+	if len(expected) != len(got) {
+		t.Fatalf("expected %d messages, got %d", len(expected), len(got))
+	}
+	for i := range expected {
+		if !expected[i].Time.Equal(got[i].Time) {
+			t.Fatalf("(message %d) expected time %s, got %s", i, expected[i].Time, got[i].Time)
+		}
+		if !expected[i].Message.Equal(got[i].Message) {
+			t.Fatalf("(message %d) expected %v, got %v", i, expected[i].Message, got[i].Message)
+		}
+	}
+
+	// Check the index.
+	zr, err := zip.OpenReader(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = zr.Close() }() // Best effort.
+
+	var index ArchiveIndex
+	if err := readArchiveFile(&zr.Reader, archiveIndexName, func(r io.Reader) error {
+		return json.NewDecoder(r).Decode(&index)
+	}); err != nil {
+		t.Fatal(err)
+	}
+	expectedIndex := ArchiveIndex{
+		Count:     3,
+		Start:     start,
+		End:       start.Add(2 * time.Millisecond),
+		Addresses: []string{"/synth/freq", "/synth/gain"},
+	}
+	if !reflect.DeepEqual(expectedIndex, index) {
+		t.Fatalf("expected %+v, got %+v", expectedIndex, index)
+	}
+}
+
+func TestLoadArchiveForwardCompatible(t *testing.T) {
+	dir, err := ioutil.TempDir("", "osc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = os.RemoveAll(dir) }() // Best effort.
+
+	path := filepath.Join(dir, "future.oscarchive")
+	writeTestArchive(t, path, map[string][]byte{
+		archiveIndexName:    []byte(`{"count": 1, "format_version": 2, "addresses": ["/foo"]}`),
+		archiveMessagesName: testArchiveMessages(t, Message{Address: "/foo"}),
+		"thumbnail.png":     {1, 2, 3},
+	})
+	recorder, err := LoadArchive(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected, got := 1, recorder.Len(); expected != got {
+		t.Fatalf("expected %d messages, got %d", expected, got)
+	}
+}
+
+func TestLoadArchiveError(t *testing.T) {
+	dir, err := ioutil.TempDir("", "osc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = os.RemoveAll(dir) }() // Best effort.
+
+	for i, files := range []map[string][]byte{
+		{archiveMessagesName: testArchiveMessages(t, Message{Address: "/foo"})},
+		{archiveIndexName: []byte(`{"count": 1}`)},
+		{archiveIndexName: []byte(`{"count": 2}`), archiveMessagesName: testArchiveMessages(t, Message{Address: "/foo"})},
+		{archiveIndexName: []byte(`{"count": 1}`), archiveMessagesName: testArchiveMessages(t, Bundle{})},
+	} {
+		path := filepath.Join(dir, "bad.oscarchive")
+		writeTestArchive(t, path, files)
+
+		if _, err := LoadArchive(path); errors.Cause(err) != ErrParse {
+			t.Fatalf("(archive %d) expected ErrParse, got %v", i, err)
+		}
+	}
+	if _, err := LoadArchive(filepath.Join(dir, "missing.oscarchive")); err == nil {
+		t.Fatal("expected error, got nil")
+	}
+}
+
+func testArchiveMessages(t *testing.T, packets ...Packet) []byte {
+	buf := &bytes.Buffer{}
+	enc := NewEncoder(buf)
+	for _, p := range packets {
+		if msg, ok := p.(Message); ok {
+			p = Bundle{Timetag: FromTime(time.Now()), Packets: []Packet{msg}}
+		}
+		if err := enc.Encode(p); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return buf.Bytes()
+}
+
+func writeTestArchive(t *testing.T, path string, files map[string][]byte) {
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = f.Close() }() // Best effort.
+
+	zw := zip.NewWriter(f)
+	for name, data := range files {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := w.Write(data); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
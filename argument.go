@@ -6,10 +6,37 @@ import (
 	"encoding/binary"
 	"fmt"
 	"io"
+	"math"
+	"net"
 
 	"github.com/pkg/errors"
 )
 
+// Common errors.
+var (
+	ErrBlobLength = errors.New("blob length is not a multiple of the sample size")
+	ErrIntNotBool = errors.New("int is neither 0 nor 1")
+)
+
+// ReadBoolLenient reads a boolean from the arg like ReadBool, but also accepts ints,
+// since many controllers send toggles as the ints 0 and 1 instead of OSC's true and false.
+// It returns false for Int(0), true for Int(1),
+// and an error wrapping ErrIntNotBool for any other int.
+func ReadBoolLenient(a Argument) (bool, error) {
+	i, ok := a.(Int)
+	if !ok {
+		return a.ReadBool()
+	}
+	switch i {
+	case 0:
+		return false, nil
+	case 1:
+		return true, nil
+	default:
+		return false, errors.Wrapf(ErrIntNotBool, "got %d", i)
+	}
+}
+
 // Argument represents an OSC argument.
 // An OSC argument can have many different types, which is why
 // we choose to represent them with an interface.
@@ -17,8 +44,13 @@ type Argument interface {
 	io.WriterTo
 
 	Bytes() []byte
+
+	// Size returns the number of bytes returned by Bytes without encoding the argument.
+	Size() int
+
 	Equal(Argument) bool
 	ReadInt32() (int32, error)
+	ReadInt64() (int64, error)
 	ReadFloat32() (float32, error)
 	ReadBool() (bool, error)
 	ReadString() (string, error)
@@ -28,23 +60,80 @@ type Argument interface {
 }
 
 // ReadArguments reads all arguments from the reader and adds it to the OSC message.
+// If the data ends before an argument, e.g. because the packet was truncated,
+// it returns a ParseError wrapping io.ErrShortBuffer that names the index of the argument.
 func ReadArguments(typetags, data []byte) ([]Argument, error) {
-	args := []Argument{}
+	return ReadArgumentsWith(typetags, data, ParseOptions{})
+}
+
+// ReadArgumentsWith reads all arguments using the given parse options.
+// Offsets passed to opts.Trace are relative to the start of data.
+func ReadArgumentsWith(typetags, data []byte, opts ParseOptions) ([]Argument, error) {
+	args, _, err := appendArguments([]Argument{}, typetags, data, opts, 0)
+	return args, err
+}
+
+// readArguments reads all arguments and returns the number of bytes consumed.
+func readArguments(typetags, data []byte) ([]Argument, int64, error) {
+	return appendArguments([]Argument{}, typetags, data, ParseOptions{}, 0)
+}
+
+// appendArguments reads all arguments, appends them to args,
+// and returns the number of bytes consumed.
+// offset is the position of data in the packet being parsed, it is used for tracing
+// and for the offsets of parse errors, which are returned as a ParseError.
+func appendArguments(args []Argument, typetags, data []byte, opts ParseOptions, offset int64) ([]Argument, int64, error) {
+	var (
+		consumed int64
+		input    = data
+	)
 
 	// Strip off the prefix.
 	if len(typetags) > 0 && typetags[0] == TypetagPrefix {
 		typetags = typetags[1:]
 	}
-
+	if err := opts.checkArgumentCount(typetags); err != nil {
+		return nil, 0, err
+	}
+	if n := opts.prealloc(len(typetags)); cap(args)-len(args) < n {
+		grown := make([]Argument, len(args), len(args)+n)
+		copy(grown, args)
+		args = grown
+	}
 	for i, tt := range typetags {
+		if err := opts.checkArgumentLen(tt, data); err != nil {
+			return nil, 0, newParseError(input, int(offset+consumed), tt, errors.Wrapf(err, "parsing argument %d (typetag '%c')", i, tt))
+		}
+		if len(data) < minArgumentLen(tt) {
+			return nil, 0, newParseError(input, int(offset+consumed), tt, errors.Wrapf(io.ErrShortBuffer, "parsing argument %d (typetag '%c')", i, tt))
+		}
 		arg, idx, err := ReadArgument(tt, data)
 		if err != nil {
-			return nil, errors.Wrapf(err, "read argument %d", i)
+			// The cause is wrapped so the typetag is only named once.
+			return nil, 0, newParseError(input, int(offset+consumed), tt, errors.Wrapf(errors.Cause(err), "parsing argument %d (typetag '%c')", i, tt))
+		}
+		if opts.Trace != nil {
+			opts.Trace(tt, int(offset+consumed), arg)
 		}
 		args = append(args, arg)
 		data = data[idx:]
+		consumed += idx
 	}
-	return args, nil
+	return args, consumed, nil
+}
+
+// minArgumentLen returns the smallest number of bytes an argument
+// with the given type tag can be encoded in.
+// It returns 0 for strings, since empty strings are encoded without any bytes,
+// and for custom types, which check the data themselves.
+func minArgumentLen(tt byte) int {
+	switch tt {
+	case TypetagInt, TypetagFloat, TypetagBlob, TypetagUInt32, TypetagColor:
+		return 4
+	case TypetagInt64, TypetagTimetag:
+		return 8
+	}
+	return 0
 }
 
 // ReadArgument parses an OSC message argument given a type tag and some data.
@@ -63,11 +152,47 @@ func ReadArgument(tt byte, data []byte) (Argument, int64, error) {
 		return String(s), idx, nil
 	case TypetagBlob:
 		return ReadBlobFrom(data)
+	case TypetagUInt32:
+		return ReadUInt32From(data)
+	case TypetagColor:
+		return ReadColorFrom(data)
+	case TypetagInt64:
+		return ReadInt64From(data)
+	case TypetagTimetag:
+		return ReadTimetagFrom(data)
 	default:
+		if decode, ok := lookupType(tt); ok {
+			return decode(data)
+		}
 		return nil, 0, errors.Wrapf(ErrInvalidTypeTag, "typetag %q", string(tt))
 	}
 }
 
+// ArgumentToInterface returns the native Go value of an argument:
+// int32 for Int, float32 for Float, bool for Bool, string for String, []byte for Blob
+// uint32 for UInt32 and int64 for Int64.
+// Arguments of other types are returned as-is.
+func ArgumentToInterface(a Argument) interface{} {
+	switch v := a.(type) {
+	case Int:
+		return int32(v)
+	case Float:
+		return float32(v)
+	case Bool:
+		return bool(v)
+	case String:
+		return string(v)
+	case Blob:
+		return []byte(v)
+	case UInt32:
+		return uint32(v)
+	case Int64:
+		return int64(v)
+	default:
+		return a
+	}
+}
+
 // Int represents a 32-bit integer.
 type Int int32
 
@@ -102,10 +227,14 @@ func (i Int) Equal(other Argument) bool {
 // ReadInt32 reads a 32-bit integer from the arg.
 func (i Int) ReadInt32() (int32, error) { return int32(i), nil }
 
+// ReadInt64 reads a 64-bit integer from the arg.
+func (i Int) ReadInt64() (int64, error) { return 0, ErrInvalidTypeTag }
+
 // ReadFloat32 reads a 32-bit float from the arg.
 func (i Int) ReadFloat32() (float32, error) { return 0, ErrInvalidTypeTag }
 
 // ReadBool bool reads a boolean from the arg.
+// See ReadBoolLenient for reading ints as booleans.
 func (i Int) ReadBool() (bool, error) { return false, ErrInvalidTypeTag }
 
 // ReadString string reads a string from the arg.
@@ -120,12 +249,50 @@ func (i Int) String() string { return fmt.Sprintf("Int(%d)", i) }
 // Typetag returns the argument's type tag.
 func (i Int) Typetag() byte { return TypetagInt }
 
+// Size returns the number of bytes of the encoded arg.
+func (i Int) Size() int { return 4 }
+
 // WriteTo writes the arg to an io.Writer.
 func (i Int) WriteTo(w io.Writer) (int64, error) {
 	written, err := fmt.Fprintf(w, "%d", i)
 	return int64(written), err
 }
 
+// Clamp returns min if the int is less than min, max if it is greater than max,
+// and the int itself otherwise.
+func (i Int) Clamp(min, max int32) Int {
+	if int32(i) < min {
+		return Int(min)
+	}
+	if int32(i) > max {
+		return Int(max)
+	}
+	return i
+}
+
+// InRange returns true if min <= i <= max.
+func (i Int) InRange(min, max int32) bool {
+	return int32(i) >= min && int32(i) <= max
+}
+
+// Abs returns the absolute value of the int.
+// Since math.MinInt32 has no positive counterpart, its absolute value is math.MaxInt32.
+func (i Int) Abs() Int {
+	if i < 0 {
+		return i.Negate().(Int)
+	}
+	return i
+}
+
+// Negate returns the additive inverse of the int as an Int.
+// Since math.MinInt32 has no positive counterpart, it is negated to math.MaxInt32.
+func (i Int) Negate() Argument {
+	if i == math.MinInt32 {
+		return Int(math.MaxInt32)
+	}
+	return -i
+}
+
 // Float represents a 32-bit float.
 type Float float32
 
@@ -159,6 +326,9 @@ func (f Float) Equal(other Argument) bool {
 // ReadInt32 reads a 32-bit integer from the arg.
 func (f Float) ReadInt32() (int32, error) { return 0, ErrInvalidTypeTag }
 
+// ReadInt64 reads a 64-bit integer from the arg.
+func (f Float) ReadInt64() (int64, error) { return 0, ErrInvalidTypeTag }
+
 // ReadFloat32 reads a 32-bit float from the arg.
 func (f Float) ReadFloat32() (float32, error) { return float32(f), nil }
 
@@ -177,12 +347,47 @@ func (f Float) String() string { return fmt.Sprintf("Float(%f)", f) }
 // Typetag returns the argument's type tag.
 func (f Float) Typetag() byte { return TypetagFloat }
 
+// Size returns the number of bytes of the encoded arg.
+func (f Float) Size() int { return 4 }
+
 // WriteTo writes the arg to an io.Writer.
 func (f Float) WriteTo(w io.Writer) (int64, error) {
 	written, err := fmt.Fprintf(w, "%f", f)
 	return int64(written), err
 }
 
+// Clamp returns min if the float is less than min, max if it is greater than max,
+// and the float itself otherwise.
+func (f Float) Clamp(min, max float32) Float {
+	if float32(f) < min {
+		return Float(min)
+	}
+	if float32(f) > max {
+		return Float(max)
+	}
+	return f
+}
+
+// InRange returns true if min <= f <= max.
+func (f Float) InRange(min, max float32) bool {
+	return float32(f) >= min && float32(f) <= max
+}
+
+// NearlyEqual returns true if |f-other| <= epsilon.
+func (f Float) NearlyEqual(other Float, epsilon float32) bool {
+	return math.Abs(float64(f)-float64(other)) <= float64(epsilon)
+}
+
+// Abs returns the absolute value of the float.
+func (f Float) Abs() Float {
+	return Float(math.Abs(float64(f)))
+}
+
+// Negate returns the additive inverse of the float as a Float.
+func (f Float) Negate() Argument {
+	return -f
+}
+
 // Bool represents a boolean value.
 type Bool bool
 
@@ -203,6 +408,9 @@ func (b Bool) Equal(other Argument) bool {
 // ReadInt32 reads a 32-bit integer from the arg.
 func (b Bool) ReadInt32() (int32, error) { return 0, ErrInvalidTypeTag }
 
+// ReadInt64 reads a 64-bit integer from the arg.
+func (b Bool) ReadInt64() (int64, error) { return 0, ErrInvalidTypeTag }
+
 // ReadFloat32 reads a 32-bit float from the arg.
 func (b Bool) ReadFloat32() (float32, error) { return 0, ErrInvalidTypeTag }
 
@@ -226,6 +434,9 @@ func (b Bool) Typetag() byte {
 	return TypetagFalse
 }
 
+// Size returns 0, bools are only encoded in the typetag.
+func (b Bool) Size() int { return 0 }
+
 // WriteTo writes the arg to an io.Writer.
 func (b Bool) WriteTo(w io.Writer) (int64, error) {
 	written, err := fmt.Fprintf(w, "%t", b)
@@ -240,6 +451,15 @@ func (s String) Bytes() []byte {
 	return ToBytes(string(s))
 }
 
+// PaddedLen returns the number of bytes the string occupies when encoded.
+// Like ToBytes, the empty string is encoded as zero bytes.
+func (s String) PaddedLen() int {
+	if len(s) == 0 {
+		return 0
+	}
+	return paddedLen(len(s) + 1)
+}
+
 // Equal returns true if the argument equals the other one, false otherwise.
 func (s String) Equal(other Argument) bool {
 	if other.Typetag() != TypetagString {
@@ -252,6 +472,9 @@ func (s String) Equal(other Argument) bool {
 // ReadInt32 reads a 32-bit integer from the arg.
 func (s String) ReadInt32() (int32, error) { return 0, ErrInvalidTypeTag }
 
+// ReadInt64 reads a 64-bit integer from the arg.
+func (s String) ReadInt64() (int64, error) { return 0, ErrInvalidTypeTag }
+
 // ReadFloat32 reads a 32-bit float from the arg.
 func (s String) ReadFloat32() (float32, error) { return 0, ErrInvalidTypeTag }
 
@@ -270,6 +493,9 @@ func (s String) String() string { return string(s) }
 // Typetag returns the argument's type tag.
 func (s String) Typetag() byte { return TypetagString }
 
+// Size returns the number of bytes of the encoded arg, see PaddedLen.
+func (s String) Size() int { return s.PaddedLen() }
+
 // WriteTo writes the arg to an io.Writer.
 func (s String) WriteTo(w io.Writer) (int64, error) {
 	written, err := fmt.Fprintf(w, "%s", s)
@@ -285,7 +511,15 @@ func ReadBlobFrom(data []byte) (Argument, int64, error) {
 	if err := binary.Read(bytes.NewReader(data), byteOrder, &length); err != nil {
 		return nil, 0, errors.Wrap(err, "read blob argument")
 	}
+	if length < 0 {
+		return nil, 0, errors.Wrapf(ErrParse, "negative blob length %d", length)
+	}
 	b, bl := ReadBlob(length, data[4:])
+
+	// Strip the padding.
+	if int(length) < len(b) {
+		b = b[:length]
+	}
 	return Blob(b), bl + 4, nil
 }
 
@@ -297,6 +531,38 @@ func (b Blob) Bytes() []byte {
 	}, []byte{}))
 }
 
+// WriteBinary writes the blob's OSC encoding to w: its int32 length,
+// followed by its bytes padded with null bytes to a multiple of 4.
+// Unlike WriteTo, which only writes the bytes, this is what Bytes returns.
+func (b Blob) WriteBinary(w io.Writer) (int64, error) {
+	var (
+		padding [3]byte
+		n       int64
+	)
+	if err := binary.Write(w, byteOrder, int32(len(b))); err != nil {
+		return 0, errors.Wrap(err, "write blob length")
+	}
+	n += 4
+
+	nw, err := w.Write(b)
+	n += int64(nw)
+	if err != nil {
+		return n, errors.Wrap(err, "write blob")
+	}
+	nw, err = w.Write(padding[:paddedLen(len(b))-len(b)])
+	n += int64(nw)
+	if err != nil {
+		return n, errors.Wrap(err, "write blob padding")
+	}
+	return n, nil
+}
+
+// PaddedLen returns the number of bytes the blob occupies when encoded,
+// including the length prefix.
+func (b Blob) PaddedLen() int {
+	return 4 + paddedLen(len(b))
+}
+
 // Equal returns true if the argument equals the other one, false otherwise.
 func (b Blob) Equal(other Argument) bool {
 	if other.Typetag() != TypetagBlob {
@@ -312,6 +578,9 @@ func (b Blob) Equal(other Argument) bool {
 // ReadInt32 reads a 32-bit integer from the arg.
 func (b Blob) ReadInt32() (int32, error) { return 0, ErrInvalidTypeTag }
 
+// ReadInt64 reads a 64-bit integer from the arg.
+func (b Blob) ReadInt64() (int64, error) { return 0, ErrInvalidTypeTag }
+
 // ReadFloat32 reads a 32-bit float from the arg.
 func (b Blob) ReadFloat32() (float32, error) { return 0, ErrInvalidTypeTag }
 
@@ -325,16 +594,122 @@ func (b Blob) ReadString() (string, error) { return "", ErrInvalidTypeTag }
 func (b Blob) ReadBlob() ([]byte, error) { return []byte(b), nil }
 
 // String converts the arg to a string.
-func (b Blob) String() string { return base64.StdEncoding.EncodeToString([]byte(b)) }
+func (b Blob) String() string { return b.Base64() }
 
 // Typetag returns the argument's type tag.
 func (b Blob) Typetag() byte { return TypetagBlob }
 
+// Size returns the number of bytes of the encoded arg, see PaddedLen.
+func (b Blob) Size() int { return b.PaddedLen() }
+
 // WriteTo writes the arg to an io.Writer.
 func (b Blob) WriteTo(w io.Writer) (int64, error) {
 	written, err := w.Write([]byte(b))
 	return int64(written), err
 }
 
+// Base64 returns the blob in standard base64 encoding,
+// which is how blobs are represented in text and JSON.
+func (b Blob) Base64() string {
+	return base64.StdEncoding.EncodeToString(b)
+}
+
+// BlobFromBase64 returns the blob encoded in s in standard base64 encoding, see Blob.Base64.
+func BlobFromBase64(s string) (Blob, error) {
+	b, err := base64.StdEncoding.DecodeString(s)
+	if err != nil {
+		return nil, errors.Wrap(err, "decode base64 blob")
+	}
+	return Blob(b), nil
+}
+
+// AsPacket parses the blob as an embedded message or bundle.
+// The sender is passed on to the parsed messages.
+func (b Blob) AsPacket(sender net.Addr) (Packet, error) {
+	p, err := parsePacket(b, sender)
+	if err != nil {
+		return nil, errors.Wrap(err, "parse blob as packet")
+	}
+	return p, nil
+}
+
+// AsFloat32LE interprets the blob as little-endian 32-bit float samples.
+func (b Blob) AsFloat32LE() ([]float32, error) {
+	return b.asFloat32(binary.LittleEndian)
+}
+
+// AsFloat32BE interprets the blob as big-endian 32-bit float samples.
+func (b Blob) AsFloat32BE() ([]float32, error) {
+	return b.asFloat32(binary.BigEndian)
+}
+
+func (b Blob) asFloat32(order binary.ByteOrder) ([]float32, error) {
+	if len(b)%4 != 0 {
+		return nil, errors.Wrapf(ErrBlobLength, "length %d", len(b))
+	}
+	samples := make([]float32, len(b)/4)
+	for i := range samples {
+		samples[i] = math.Float32frombits(order.Uint32(b[i*4:]))
+	}
+	return samples, nil
+}
+
+// AsInt16LE interprets the blob as little-endian 16-bit integer samples.
+func (b Blob) AsInt16LE() ([]int16, error) {
+	return b.asInt16(binary.LittleEndian)
+}
+
+// AsInt16BE interprets the blob as big-endian 16-bit integer samples.
+func (b Blob) AsInt16BE() ([]int16, error) {
+	return b.asInt16(binary.BigEndian)
+}
+
+func (b Blob) asInt16(order binary.ByteOrder) ([]int16, error) {
+	if len(b)%2 != 0 {
+		return nil, errors.Wrapf(ErrBlobLength, "length %d", len(b))
+	}
+	samples := make([]int16, len(b)/2)
+	for i := range samples {
+		samples[i] = int16(order.Uint16(b[i*2:]))
+	}
+	return samples, nil
+}
+
+// BlobFromFloat32LE creates a blob from 32-bit float samples in little-endian byte order.
+func BlobFromFloat32LE(samples []float32) Blob {
+	return blobFromFloat32(binary.LittleEndian, samples)
+}
+
+// BlobFromFloat32BE creates a blob from 32-bit float samples in big-endian byte order.
+func BlobFromFloat32BE(samples []float32) Blob {
+	return blobFromFloat32(binary.BigEndian, samples)
+}
+
+func blobFromFloat32(order binary.ByteOrder, samples []float32) Blob {
+	b := make(Blob, len(samples)*4)
+	for i, sample := range samples {
+		order.PutUint32(b[i*4:], math.Float32bits(sample))
+	}
+	return b
+}
+
+// BlobFromInt16LE creates a blob from 16-bit integer samples in little-endian byte order.
+func BlobFromInt16LE(samples []int16) Blob {
+	return blobFromInt16(binary.LittleEndian, samples)
+}
+
+// BlobFromInt16BE creates a blob from 16-bit integer samples in big-endian byte order.
+func BlobFromInt16BE(samples []int16) Blob {
+	return blobFromInt16(binary.BigEndian, samples)
+}
+
+func blobFromInt16(order binary.ByteOrder, samples []int16) Blob {
+	b := make(Blob, len(samples)*2)
+	for i, sample := range samples {
+		order.PutUint16(b[i*2:], uint16(sample))
+	}
+	return b
+}
+
 // Arguments is a slice of Argument.
 type Arguments []Argument
@@ -3,8 +3,12 @@ package osc
 import (
 	"bytes"
 	"encoding/base64"
+	"io"
 	"io/ioutil"
+	"math"
+	"reflect"
 	"testing"
+	"time"
 
 	"github.com/pkg/errors"
 )
@@ -104,6 +108,46 @@ func TestIntWriteTo(t *testing.T) {
 	}
 }
 
+func TestIntClamp(t *testing.T) {
+	for _, testcase := range []struct {
+		Int      Int
+		Expected Int
+		InRange  bool
+	}{
+		{Int: -5, Expected: 0},
+		{Int: 0, Expected: 0, InRange: true},
+		{Int: 64, Expected: 64, InRange: true},
+		{Int: 127, Expected: 127, InRange: true},
+		{Int: 300, Expected: 127},
+	} {
+		if expected, got := testcase.Expected, testcase.Int.Clamp(0, 127); expected != got {
+			t.Fatalf("expected %d, got %d", expected, got)
+		}
+		if expected, got := testcase.InRange, testcase.Int.InRange(0, 127); expected != got {
+			t.Fatalf("(%d) expected %t, got %t", testcase.Int, expected, got)
+		}
+	}
+}
+
+func TestIntAbsNegate(t *testing.T) {
+	for _, testcase := range []struct {
+		Int, Abs, Negated Int
+	}{
+		{Int: 0, Abs: 0, Negated: 0},
+		{Int: 5, Abs: 5, Negated: -5},
+		{Int: -5, Abs: 5, Negated: 5},
+		{Int: math.MaxInt32, Abs: math.MaxInt32, Negated: -math.MaxInt32},
+		{Int: math.MinInt32, Abs: math.MaxInt32, Negated: math.MaxInt32},
+	} {
+		if expected, got := testcase.Abs, testcase.Int.Abs(); expected != got {
+			t.Fatalf("(%d) expected abs %d, got %d", testcase.Int, expected, got)
+		}
+		if expected, got := Argument(testcase.Negated), testcase.Int.Negate(); expected != got {
+			t.Fatalf("(%d) expected negated %s, got %s", testcase.Int, expected, got)
+		}
+	}
+}
+
 func TestFloatBytes(t *testing.T) {
 	if expected, got := []byte{0x40, 0x48, 0xf5, 0xc3}, Float(3.14).Bytes(); !bytes.Equal(expected, got) {
 		t.Fatalf("expected %x, got %x", expected, got)
@@ -166,6 +210,61 @@ func TestFloatWriteTo(t *testing.T) {
 	}
 }
 
+func TestFloatClamp(t *testing.T) {
+	for _, testcase := range []struct {
+		Float    Float
+		Expected Float
+		InRange  bool
+	}{
+		{Float: -0.5, Expected: 0},
+		{Float: 0, Expected: 0, InRange: true},
+		{Float: 0.5, Expected: 0.5, InRange: true},
+		{Float: 1, Expected: 1, InRange: true},
+		{Float: 1.5, Expected: 1},
+	} {
+		if expected, got := testcase.Expected, testcase.Float.Clamp(0, 1); expected != got {
+			t.Fatalf("expected %f, got %f", expected, got)
+		}
+		if expected, got := testcase.InRange, testcase.Float.InRange(0, 1); expected != got {
+			t.Fatalf("(%f) expected %t, got %t", testcase.Float, expected, got)
+		}
+	}
+}
+
+func TestFloatNearlyEqual(t *testing.T) {
+	for _, testcase := range []struct {
+		A, B     Float
+		Expected bool
+	}{
+		{A: 0.1 + 0.2, B: 0.3, Expected: true},
+		{A: 1, B: 1.25, Expected: true},
+		{A: 1.25, B: 1, Expected: true},
+		{A: 1, B: 1.5},
+		{A: -1, B: 1},
+	} {
+		if expected, got := testcase.Expected, testcase.A.NearlyEqual(testcase.B, 0.25); expected != got {
+			t.Fatalf("(%f, %f) expected %t, got %t", testcase.A, testcase.B, expected, got)
+		}
+	}
+}
+
+func TestFloatAbsNegate(t *testing.T) {
+	for _, testcase := range []struct {
+		Float, Abs, Negated Float
+	}{
+		{Float: 0, Abs: 0, Negated: 0},
+		{Float: 0.5, Abs: 0.5, Negated: -0.5},
+		{Float: -0.5, Abs: 0.5, Negated: 0.5},
+	} {
+		if expected, got := testcase.Abs, testcase.Float.Abs(); expected != got {
+			t.Fatalf("(%f) expected abs %f, got %f", testcase.Float, expected, got)
+		}
+		if expected, got := Argument(testcase.Negated), testcase.Float.Negate(); expected != got {
+			t.Fatalf("(%f) expected negated %s, got %s", testcase.Float, expected, got)
+		}
+	}
+}
+
 func TestBoolBytes(t *testing.T) {
 	arg := Bool(false)
 	if expected, got := []byte{}, arg.Bytes(); !bytes.Equal(expected, got) {
@@ -242,6 +341,14 @@ func TestStringBytes(t *testing.T) {
 	}
 }
 
+func TestStringPaddedLen(t *testing.T) {
+	for _, s := range []String{"", "a", "abc", "abcd", "abcdefgh"} {
+		if expected, got := len(s.Bytes()), s.PaddedLen(); expected != got {
+			t.Fatalf("(%q) expected %d, got %d", s, expected, got)
+		}
+	}
+}
+
 func TestStringEqual(t *testing.T) {
 	arg := String("foo")
 	if other := String("foo"); !arg.Equal(other) {
@@ -307,6 +414,14 @@ func TestBlobBytes(t *testing.T) {
 	}
 }
 
+func TestBlobPaddedLen(t *testing.T) {
+	for _, b := range []Blob{{}, {1}, {1, 2, 3, 4}, {1, 2, 3, 4, 5}} {
+		if expected, got := len(b.Bytes()), b.PaddedLen(); expected != got {
+			t.Fatalf("(%v) expected %d, got %d", b, expected, got)
+		}
+	}
+}
+
 func TestBlobEqual(t *testing.T) {
 	arg := Blob([]byte{'f', 'o', 'o'})
 	if other := Blob([]byte{'f', 'o', 'o'}); !arg.Equal(other) {
@@ -358,6 +473,32 @@ func TestBlobString(t *testing.T) {
 	}
 }
 
+func TestBlobBase64(t *testing.T) {
+	for _, testcase := range []struct {
+		Blob     Blob
+		Expected string
+	}{
+		{Blob: Blob{}, Expected: ""},
+		{Blob: Blob{0}, Expected: "AA=="},
+		{Blob: Blob{0xff, 0x00, 0x80}, Expected: "/wCA"},
+		{Blob: Blob{1, 2, 3, 4, 5}, Expected: "AQIDBAU="},
+	} {
+		if expected, got := testcase.Expected, testcase.Blob.Base64(); expected != got {
+			t.Fatalf("expected %s, got %s", expected, got)
+		}
+		b, err := BlobFromBase64(testcase.Expected)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !testcase.Blob.Equal(b) {
+			t.Fatalf("expected %x, got %x", testcase.Blob, b)
+		}
+	}
+	if _, err := BlobFromBase64("not base64!"); err == nil {
+		t.Fatal("expected error, got nil")
+	}
+}
+
 func TestBlobTypetag(t *testing.T) {
 	arg := Blob([]byte{'f', 'o', 'o'})
 	if expected, got := TypetagBlob, arg.Typetag(); expected != got {
@@ -417,7 +558,7 @@ func TestReadArgument(t *testing.T) {
 		{
 			// Length followed by blob
 			Input:    Input{tt: TypetagBlob, data: []byte{0, 0, 0, 5, 'a', 'b', 'c', 'd', 'e'}},
-			Expected: Output{Argument: Blob([]byte{'a', 'b', 'c', 'd', 'e', 0, 0, 0}), Consumed: 12},
+			Expected: Output{Argument: Blob([]byte{'a', 'b', 'c', 'd', 'e'}), Consumed: 12},
 		},
 		{
 			Input:    Input{tt: TypetagBlob, data: []byte{}},
@@ -466,7 +607,7 @@ func TestReadArguments(t *testing.T) {
 		},
 		{
 			Input:    Input{Typetags: []byte{TypetagInt}, Data: []byte{}},
-			Expected: Output{Err: errors.New("read argument 0: read int argument: EOF")},
+			Expected: Output{Err: errors.New("parsing argument 0 (typetag 'i'): short buffer")},
 		},
 		{
 			Input:    Input{Typetags: []byte{TypetagInt}, Data: []byte{0, 0, 0, 1}},
@@ -502,3 +643,235 @@ func TestReadArguments(t *testing.T) {
 		}
 	}
 }
+
+func TestBlobAsPacket(t *testing.T) {
+	bundle := Bundle{
+		Timetag: 5,
+		Packets: []Packet{
+			Message{Address: "/foo", Arguments: []Argument{Int(1)}},
+			Bundle{Timetag: 6, Packets: []Packet{Message{Address: "/bar"}}},
+		},
+	}
+	for _, expected := range []Packet{
+		bundle,
+		Message{Address: "/baz", Arguments: []Argument{String("qux")}},
+	} {
+		// Embed the packet in a message and extract it back.
+		msg := Message{Address: "/embed", Arguments: []Argument{Blob(expected.Bytes())}}
+		parsed, err := ParseMessage(msg.Bytes(), nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		b, err := parsed.Arguments[0].ReadBlob()
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := Blob(b).AsPacket(nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !expected.Equal(got) {
+			t.Fatalf("expected %v, got %v", expected, got)
+		}
+	}
+	for _, blob := range []Blob{{}, {1, 2, 3, 4}, Blob("#bundle")} {
+		if _, err := blob.AsPacket(nil); err == nil {
+			t.Fatalf("(%v) expected error, got nil", blob)
+		}
+	}
+}
+
+func TestBlobFloat32(t *testing.T) {
+	samples := []float32{0, 0.5, -1, 3.14}
+
+	le := BlobFromFloat32LE(samples)
+	if expected, got := []byte{0, 0, 0, 0, 0, 0, 0, 0x3f}, []byte(le[:8]); !bytes.Equal(expected, got) {
+		t.Fatalf("expected %x, got %x", expected, got)
+	}
+	gotLE, err := le.AsFloat32LE()
+	if err != nil {
+		t.Fatal(err)
+	}
+	be := BlobFromFloat32BE(samples)
+	if expected, got := []byte{0, 0, 0, 0, 0x3f, 0, 0, 0}, []byte(be[:8]); !bytes.Equal(expected, got) {
+		t.Fatalf("expected %x, got %x", expected, got)
+	}
+	gotBE, err := be.AsFloat32BE()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, expected := range samples {
+		if got := gotLE[i]; expected != got {
+			t.Fatalf("(little-endian sample %d) expected %f, got %f", i, expected, got)
+		}
+		if got := gotBE[i]; expected != got {
+			t.Fatalf("(big-endian sample %d) expected %f, got %f", i, expected, got)
+		}
+	}
+	if _, err := Blob([]byte{1, 2, 3}).AsFloat32LE(); errors.Cause(err) != ErrBlobLength {
+		t.Fatalf("expected ErrBlobLength, got %v", err)
+	}
+}
+
+func TestBlobInt16(t *testing.T) {
+	samples := []int16{0, 1, -2, 32767}
+
+	gotLE, err := BlobFromInt16LE(samples).AsInt16LE()
+	if err != nil {
+		t.Fatal(err)
+	}
+	gotBE, err := BlobFromInt16BE(samples).AsInt16BE()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, expected := range samples {
+		if got := gotLE[i]; expected != got {
+			t.Fatalf("(little-endian sample %d) expected %d, got %d", i, expected, got)
+		}
+		if got := gotBE[i]; expected != got {
+			t.Fatalf("(big-endian sample %d) expected %d, got %d", i, expected, got)
+		}
+	}
+	if _, err := Blob([]byte{1, 2, 3}).AsInt16BE(); errors.Cause(err) != ErrBlobLength {
+		t.Fatalf("expected ErrBlobLength, got %v", err)
+	}
+}
+
+func TestArgumentToInterface(t *testing.T) {
+	for i, testcase := range []struct {
+		Arg      Argument
+		Expected interface{}
+	}{
+		{Arg: Int(-3), Expected: int32(-3)},
+		{Arg: Float(1.5), Expected: float32(1.5)},
+		{Arg: Bool(true), Expected: true},
+		{Arg: String("foo"), Expected: "foo"},
+		{Arg: Blob{1, 2}, Expected: []byte{1, 2}},
+		{Arg: nil, Expected: nil},
+	} {
+		if expected, got := testcase.Expected, ArgumentToInterface(testcase.Arg); !reflect.DeepEqual(expected, got) {
+			t.Fatalf("(case %d) expected %#v, got %#v", i, expected, got)
+		}
+	}
+}
+
+func TestReadBoolLenient(t *testing.T) {
+	if _, err := Int(1).ReadBool(); err != ErrInvalidTypeTag {
+		t.Fatalf("expected ErrInvalidTypeTag, got %v", err)
+	}
+	for _, testcase := range []struct {
+		Arg      Argument
+		Expected bool
+	}{
+		{Arg: Int(0), Expected: false},
+		{Arg: Int(1), Expected: true},
+		{Arg: Bool(true), Expected: true},
+	} {
+		b, err := ReadBoolLenient(testcase.Arg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if expected, got := testcase.Expected, b; expected != got {
+			t.Fatalf("(%s) expected %t, got %t", testcase.Arg, expected, got)
+		}
+	}
+	if _, err := ReadBoolLenient(Int(2)); errors.Cause(err) != ErrIntNotBool {
+		t.Fatalf("expected ErrIntNotBool, got %v", err)
+	}
+	if _, err := ReadBoolLenient(String("1")); err != ErrInvalidTypeTag {
+		t.Fatalf("expected ErrInvalidTypeTag, got %v", err)
+	}
+}
+
+func TestBlobPadding(t *testing.T) {
+	for _, length := range []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 13} {
+		blob := make(Blob, length)
+		for i := range blob {
+			blob[i] = byte(i + 1)
+		}
+		expected := append(Int(length).Bytes(), blob...)
+		for len(expected)%4 != 0 {
+			expected = append(expected, 0)
+		}
+		if got := blob.Bytes(); !bytes.Equal(expected, got) {
+			t.Fatalf("(length %d) expected %v, got %v", length, expected, got)
+		}
+		buf := &bytes.Buffer{}
+		n, err := blob.WriteBinary(buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if expected, got := int64(len(expected)), n; expected != got {
+			t.Fatalf("(length %d) expected %d bytes written, got %d", length, expected, got)
+		}
+		if got := buf.Bytes(); !bytes.Equal(expected, got) {
+			t.Fatalf("(length %d) expected %v, got %v", length, expected, got)
+		}
+
+		// Follow the blob with an int to check that the padding is consumed.
+		data := append(buf.Bytes(), Int(42).Bytes()...)
+		args, err := ReadArguments([]byte("bi"), data)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !blob.Equal(args[0]) {
+			t.Fatalf("(length %d) expected %v, got %v", length, []byte(blob), args[0])
+		}
+		if !Int(42).Equal(args[1]) {
+			t.Fatalf("(length %d) expected Int(42), got %v", length, args[1])
+		}
+	}
+}
+
+func TestBlobWriteBinaryError(t *testing.T) {
+	for i := 1; i <= 3; i++ {
+		if _, err := (Blob{1}).WriteBinary(&errWriter{erridx: i}); err == nil {
+			t.Fatalf("(write %d) expected error, got nil", i)
+		}
+	}
+}
+
+func TestReadBlobFromNegativeLength(t *testing.T) {
+	if _, _, err := ReadBlobFrom(Int(-1).Bytes()); errors.Cause(err) != ErrParse {
+		t.Fatalf("expected ErrParse, got %v", err)
+	}
+}
+
+func TestArgumentSize(t *testing.T) {
+	for i, a := range []Argument{
+		Int(-3),
+		Float(1.5),
+		Bool(true),
+		Bool(false),
+		String("foo"),
+		String("food"),
+		Blob{},
+		Blob{1, 2, 3, 4, 5},
+		UInt32(7),
+		Color{R: 1, G: 2, B: 3, A: 4},
+		Int64(-5),
+		FromTime(time.Unix(0, 0)),
+		NewReaderBlob(bytes.NewReader([]byte{1, 2, 3}), 3),
+	} {
+		if expected, got := len(a.Bytes()), a.Size(); expected != got {
+			t.Fatalf("(%d) expected %d, got %d", i, expected, got)
+		}
+	}
+}
+
+func TestReadArgumentsShortBuffer(t *testing.T) {
+	_, err := ReadArguments([]byte(",iii"), append(Int(1).Bytes(), Int(2).Bytes()...))
+	if errors.Cause(err) != io.ErrShortBuffer {
+		t.Fatalf("expected io.ErrShortBuffer, got %v", err)
+	}
+	if expected, got := "parsing argument 2 (typetag 'i'): short buffer", err.Error(); expected != got {
+		t.Fatalf("expected %s, got %s", expected, got)
+	}
+	var pe ParseError
+	if !errors.As(err, &pe) {
+		t.Fatalf("expected a ParseError, got %v", err)
+	}
+	if expected, got := 8, pe.Offset; expected != got {
+		t.Fatalf("expected offset %d, got %d", expected, got)
+	}
+}
@@ -0,0 +1,50 @@
+package osc_test
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/scgolang/osc"
+)
+
+// benchmarkDispatch measures dispatching a bundle with a single message to a dispatcher
+// with n methods. Every method address is compared with the message's address pattern,
+// so the cost of a dispatch grows with the number of methods.
+// The wildcard benchmarks use a pattern that matches a single method,
+// so they measure the scan rather than the handlers.
+func benchmarkDispatch(b *testing.B, n int, address string, exactMatch bool) {
+	d := osc.Dispatcher{}
+	for i := 0; i < n; i++ {
+		d["/synth/"+strconv.Itoa(i)+"/freq"] = osc.Method(func(msg osc.Message) error { return nil })
+	}
+	bundle := osc.Bundle{
+		Timetag: osc.Immediately,
+		Packets: []osc.Packet{
+			osc.Message{Address: address, Arguments: []osc.Argument{osc.Float(440)}},
+		},
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := d.Dispatch(bundle, exactMatch); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkDispatch_1Handler(b *testing.B) {
+	benchmarkDispatch(b, 1, "/synth/0/fre?", false)
+}
+
+func BenchmarkDispatch_10Handlers(b *testing.B) {
+	benchmarkDispatch(b, 10, "/synth/0/fre?", false)
+}
+
+func BenchmarkDispatch_100Handlers(b *testing.B) {
+	benchmarkDispatch(b, 100, "/synth/0/fre?", false)
+}
+
+// BenchmarkDispatch_ExactMatch dispatches a message without wildcards
+// to one of 100 methods using exact matching.
+func BenchmarkDispatch_ExactMatch(b *testing.B) {
+	benchmarkDispatch(b, 100, "/synth/42/freq", true)
+}
@@ -0,0 +1,245 @@
+// Package mqtt bridges OSC and MQTT.
+//
+// Payloads published to the subscribed MQTT topics are converted to OSC messages
+// and dispatched, and OSC messages handled by the bridge are published to
+// the MQTT topic named after their address.
+package mqtt
+
+import (
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	paho "github.com/eclipse/paho.mqtt.golang"
+	"github.com/pkg/errors"
+	"github.com/scgolang/osc"
+)
+
+// DefaultTimeout is how long the bridge waits for the broker by default.
+const DefaultTimeout = 10 * time.Second
+
+// Common errors.
+var (
+	ErrTimeout = errors.New("timed out waiting for the broker")
+)
+
+// client is the part of a paho client that the bridge uses.
+type client interface {
+	Publish(topic string, qos byte, retained bool, payload interface{}) paho.Token
+	Subscribe(topic string, qos byte, callback paho.MessageHandler) paho.Token
+	Disconnect(quiesce uint)
+}
+
+// PayloadConverter converts an MQTT payload to an OSC message.
+type PayloadConverter func(topic string, payload []byte) (osc.Message, error)
+
+// Option configures a Bridge.
+type Option func(*config) error
+
+type config struct {
+	clientID   string
+	convert    PayloadConverter
+	exactMatch bool
+	qos        byte
+	timeout    time.Duration
+	topics     []string
+}
+
+// WithClientID sets the MQTT client ID.
+func WithClientID(id string) Option {
+	return func(c *config) error {
+		c.clientID = id
+		return nil
+	}
+}
+
+// WithConverter sets the function that converts MQTT payloads to OSC messages.
+// The default is PayloadToMessage.
+func WithConverter(convert PayloadConverter) Option {
+	return func(c *config) error {
+		if convert == nil {
+			return errors.New("converter must not be nil")
+		}
+		c.convert = convert
+		return nil
+	}
+}
+
+// WithExactMatch makes the bridge only dispatch messages to methods
+// whose addresses match the message's address exactly.
+func WithExactMatch(value bool) Option {
+	return func(c *config) error {
+		c.exactMatch = value
+		return nil
+	}
+}
+
+// WithQoS sets the MQTT quality of service used to subscribe and publish.
+func WithQoS(qos byte) Option {
+	return func(c *config) error {
+		if qos > 2 {
+			return errors.Errorf("invalid qos %d", qos)
+		}
+		c.qos = qos
+		return nil
+	}
+}
+
+// WithTimeout sets how long the bridge waits for the broker.
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *config) error {
+		c.timeout = timeout
+		return nil
+	}
+}
+
+// WithTopics sets the MQTT topic filters the bridge subscribes to.
+func WithTopics(topics ...string) Option {
+	return func(c *config) error {
+		c.topics = append(c.topics, topics...)
+		return nil
+	}
+}
+
+// newConfig applies the options and fills in defaults.
+func newConfig(opts []Option) (config, error) {
+	c := config{convert: PayloadToMessage, timeout: DefaultTimeout}
+	for _, opt := range opts {
+		if err := opt(&c); err != nil {
+			return c, err
+		}
+	}
+	return c, nil
+}
+
+// Bridge forwards messages between OSC and MQTT.
+// A Bridge is an osc.MessageHandler, so it can be added to the dispatcher
+// of an OSC server to publish the messages the server receives.
+// Be careful not to add it to the dispatcher the bridge dispatches to,
+// since every message received from MQTT would be published again.
+type Bridge struct {
+	client     client
+	config     config
+	dispatcher osc.Dispatcher
+
+	mu   sync.Mutex
+	errs []error
+}
+
+// NewBridge connects to the MQTT broker at brokerURL, e.g. tcp://localhost:1883,
+// and subscribes to the configured topics.
+// Messages converted from MQTT payloads are dispatched to dispatcher.
+func NewBridge(brokerURL string, dispatcher osc.Dispatcher, opts ...Option) (*Bridge, error) {
+	if dispatcher == nil {
+		return nil, osc.ErrNilDispatcher
+	}
+	c, err := newConfig(opts)
+	if err != nil {
+		return nil, err
+	}
+	clientOpts := paho.NewClientOptions().AddBroker(brokerURL).SetClientID(c.clientID)
+	pc := paho.NewClient(clientOpts)
+	if err := wait(pc.Connect(), c.timeout); err != nil {
+		return nil, errors.Wrap(err, "connect")
+	}
+	b, err := newBridge(pc, dispatcher, c)
+	if err != nil {
+		pc.Disconnect(0)
+		return nil, err
+	}
+	return b, nil
+}
+
+// newBridge creates a bridge using a connected client.
+func newBridge(client client, dispatcher osc.Dispatcher, c config) (*Bridge, error) {
+	b := &Bridge{client: client, config: c, dispatcher: dispatcher}
+	for _, topic := range c.topics {
+		if err := wait(client.Subscribe(topic, c.qos, b.receive), c.timeout); err != nil {
+			return nil, errors.Wrapf(err, "subscribe to %s", topic)
+		}
+	}
+	return b, nil
+}
+
+// Close disconnects from the broker.
+func (b *Bridge) Close() error {
+	b.client.Disconnect(uint(b.config.timeout / time.Millisecond))
+	return nil
+}
+
+// Errors returns the errors that happened while converting and dispatching
+// MQTT payloads since the last call to Errors.
+// Payloads arrive asynchronously, so their errors can not be returned directly.
+func (b *Bridge) Errors() []error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	errs := b.errs
+	b.errs = nil
+	return errs
+}
+
+// Handle publishes the message to the MQTT topic named after its address.
+// The payload is the OSC encoding of the message.
+func (b *Bridge) Handle(msg osc.Message) error {
+	topic := AddressToTopic(msg.Address)
+	if err := wait(b.client.Publish(topic, b.config.qos, false, msg.Bytes()), b.config.timeout); err != nil {
+		return errors.Wrapf(err, "publish to %s", topic)
+	}
+	return nil
+}
+
+// receive converts an MQTT payload to an OSC message and dispatches it.
+func (b *Bridge) receive(_ paho.Client, m paho.Message) {
+	msg, err := b.config.convert(m.Topic(), m.Payload())
+	if err != nil {
+		b.addError(errors.Wrapf(err, "convert payload from %s", m.Topic()))
+		return
+	}
+	if err := b.dispatcher.Invoke(msg, b.config.exactMatch); err != nil {
+		b.addError(errors.Wrapf(err, "dispatch payload from %s", m.Topic()))
+	}
+}
+
+// addError records an error that happened while receiving a payload.
+func (b *Bridge) addError(err error) {
+	b.mu.Lock()
+	b.errs = append(b.errs, err)
+	b.mu.Unlock()
+}
+
+// PayloadToMessage is the default PayloadConverter.
+// If the payload is an OSC message it is parsed, otherwise the message's address
+// is derived from the topic and its only argument is the payload, as a string
+// if it is valid UTF-8 and as a blob otherwise.
+func PayloadToMessage(topic string, payload []byte) (osc.Message, error) {
+	if len(payload) > 0 && payload[0] == osc.MessageChar {
+		if msg, err := osc.ParseMessage(payload, nil); err == nil {
+			return msg, nil
+		}
+	}
+	var arg osc.Argument = osc.Blob(payload)
+	if utf8.Valid(payload) {
+		arg = osc.String(payload)
+	}
+	return osc.Message{Address: TopicToAddress(topic), Arguments: []osc.Argument{arg}}, nil
+}
+
+// AddressToTopic returns the MQTT topic for an OSC address,
+// which is the address without its leading '/'.
+func AddressToTopic(address string) string {
+	return strings.TrimPrefix(address, string(osc.MessageChar))
+}
+
+// TopicToAddress returns the OSC address for an MQTT topic.
+func TopicToAddress(topic string) string {
+	return string(osc.MessageChar) + strings.TrimPrefix(topic, string(osc.MessageChar))
+}
+
+// wait waits for an MQTT operation to complete.
+func wait(token paho.Token, timeout time.Duration) error {
+	if !token.WaitTimeout(timeout) {
+		return ErrTimeout
+	}
+	return token.Error()
+}
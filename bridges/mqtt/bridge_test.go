@@ -0,0 +1,214 @@
+package mqtt
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	paho "github.com/eclipse/paho.mqtt.golang"
+	"github.com/pkg/errors"
+	"github.com/scgolang/osc"
+)
+
+// token is a completed paho.Token.
+type token struct{ err error }
+
+func (t token) Wait() bool                     { return true }
+func (t token) WaitTimeout(time.Duration) bool { return true }
+func (t token) Done() <-chan struct{} {
+	c := make(chan struct{})
+	close(c)
+	return c
+}
+func (t token) Error() error { return t.err }
+
+// message is a paho.Message.
+type message struct {
+	topic   string
+	payload []byte
+}
+
+func (m message) Duplicate() bool   { return false }
+func (m message) Qos() byte         { return 0 }
+func (m message) Retained() bool    { return false }
+func (m message) Topic() string     { return m.topic }
+func (m message) MessageID() uint16 { return 0 }
+func (m message) Payload() []byte   { return m.payload }
+func (m message) Ack()              {}
+
+// fakeClient records what is published and delivers payloads to subscribers.
+type fakeClient struct {
+	mu           sync.Mutex
+	published    map[string][]byte
+	subscribers  map[string]paho.MessageHandler
+	subscribeErr error
+}
+
+func newFakeClient() *fakeClient {
+	return &fakeClient{published: map[string][]byte{}, subscribers: map[string]paho.MessageHandler{}}
+}
+
+func (c *fakeClient) Publish(topic string, qos byte, retained bool, payload interface{}) paho.Token {
+	c.mu.Lock()
+	c.published[topic] = payload.([]byte)
+	c.mu.Unlock()
+	return token{}
+}
+
+func (c *fakeClient) Subscribe(topic string, qos byte, callback paho.MessageHandler) paho.Token {
+	if c.subscribeErr != nil {
+		return token{err: c.subscribeErr}
+	}
+	c.subscribers[topic] = callback
+	return token{}
+}
+
+func (c *fakeClient) Disconnect(quiesce uint) {}
+
+func (c *fakeClient) deliver(topic string, payload []byte) {
+	c.subscribers[topic](nil, message{topic: topic, payload: payload})
+}
+
+func TestBridgeReceive(t *testing.T) {
+	var (
+		client   = newFakeClient()
+		received []osc.Message
+		d        = osc.Dispatcher{
+			"/sensors/temp": osc.Method(func(msg osc.Message) error {
+				received = append(received, msg)
+				return nil
+			}),
+		}
+	)
+	c, err := newConfig([]Option{WithTopics("sensors/temp"), WithExactMatch(true)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := newBridge(client, d, c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = b.Close() }() // Best effort.
+
+	client.deliver("sensors/temp", []byte("21.5"))
+	client.deliver("sensors/temp", osc.Message{Address: "/sensors/temp", Arguments: []osc.Argument{osc.Float(21.5)}}.Bytes())
+
+	expected := []osc.Message{
+		{Address: "/sensors/temp", Arguments: []osc.Argument{osc.String("21.5")}},
+		{Address: "/sensors/temp", Arguments: []osc.Argument{osc.Float(21.5)}},
+	}
+	if len(received) != len(expected) {
+		t.Fatalf("expected %d messages, got %d", len(expected), len(received))
+	}
+	for i := range expected {
+		if !expected[i].Equal(received[i]) {
+			t.Fatalf("expected %v, got %v", expected[i], received[i])
+		}
+	}
+	if errs := b.Errors(); len(errs) != 0 {
+		t.Fatalf("expected no errors, got %v", errs)
+	}
+}
+
+func TestBridgeReceiveError(t *testing.T) {
+	client := newFakeClient()
+	c, err := newConfig([]Option{
+		WithTopics("foo"),
+		WithConverter(func(topic string, payload []byte) (osc.Message, error) {
+			return osc.Message{}, errors.New("oops")
+		}),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := newBridge(client, osc.Dispatcher{}, c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client.deliver("foo", []byte("bar"))
+
+	if expected, got := 1, len(b.Errors()); expected != got {
+		t.Fatalf("expected %d errors, got %d", expected, got)
+	}
+	if expected, got := 0, len(b.Errors()); expected != got {
+		t.Fatalf("expected %d errors, got %d", expected, got)
+	}
+}
+
+func TestBridgeHandle(t *testing.T) {
+	client := newFakeClient()
+	c, err := newConfig(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := newBridge(client, osc.Dispatcher{}, c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	msg := osc.Message{Address: "/synth/freq", Arguments: []osc.Argument{osc.Float(440)}}
+
+	// Forward every message an OSC server receives.
+	if err := (osc.Dispatcher{"/synth/freq": b}).Invoke(msg, true); err != nil {
+		t.Fatal(err)
+	}
+	payload, ok := client.published["synth/freq"]
+	if !ok {
+		t.Fatal("expected message to be published to synth/freq")
+	}
+	got, err := osc.ParseMessage(payload, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !msg.Equal(got) {
+		t.Fatalf("expected %v, got %v", msg, got)
+	}
+}
+
+func TestNewBridgeErrors(t *testing.T) {
+	if _, err := NewBridge("tcp://127.0.0.1:1", nil); err != osc.ErrNilDispatcher {
+		t.Fatalf("expected ErrNilDispatcher, got %v", err)
+	}
+	if _, err := NewBridge("tcp://127.0.0.1:1", osc.Dispatcher{}, WithQoS(3)); err == nil {
+		t.Fatal("expected error, got nil")
+	}
+	if _, err := NewBridge("tcp://127.0.0.1:1", osc.Dispatcher{}, WithTimeout(time.Second)); err == nil {
+		t.Fatal("expected error, got nil")
+	}
+	client := newFakeClient()
+	client.subscribeErr = errors.New("denied")
+	c, err := newConfig([]Option{WithTopics("foo")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := newBridge(client, osc.Dispatcher{}, c); errors.Cause(err) != client.subscribeErr {
+		t.Fatalf("expected %v, got %v", client.subscribeErr, err)
+	}
+}
+
+func TestTopicToAddress(t *testing.T) {
+	for _, testcase := range []struct {
+		Topic   string
+		Address string
+	}{
+		{Topic: "synth/freq", Address: "/synth/freq"},
+		{Topic: "/synth/freq", Address: "/synth/freq"},
+	} {
+		if expected, got := testcase.Address, TopicToAddress(testcase.Topic); expected != got {
+			t.Fatalf("expected %s, got %s", expected, got)
+		}
+	}
+	if expected, got := "synth/freq", AddressToTopic("/synth/freq"); expected != got {
+		t.Fatalf("expected %s, got %s", expected, got)
+	}
+}
+
+func TestPayloadToMessageBlob(t *testing.T) {
+	msg, err := PayloadToMessage("raw", []byte{0xff, 0xfe})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := osc.Message{Address: "/raw", Arguments: []osc.Argument{osc.Blob{0xff, 0xfe}}}
+	if !expected.Equal(msg) {
+		t.Fatalf("expected %v, got %v", expected, msg)
+	}
+}
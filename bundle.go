@@ -2,6 +2,7 @@ package osc
 
 import (
 	"bytes"
+	"context"
 	"encoding/binary"
 	"net"
 
@@ -31,13 +32,19 @@ type Bundle struct {
 
 // ParseBundle parses a bundle from a byte slice.
 func ParseBundle(data []byte, sender net.Addr) (Bundle, error) {
-	return parseBundle(data, sender, -1)
+	return parseBundle(data, sender, -1, ParseOptions{})
+}
+
+// ParseBundleWith parses a bundle from a byte slice,
+// parsing the messages in the bundle with the given parse options.
+func ParseBundleWith(data []byte, sender net.Addr, opts ParseOptions) (Bundle, error) {
+	return parseBundle(data, sender, -1, opts)
 }
 
 // parseBundle parses a bundle from a byte slice.
 // It will stop after reading limit bytes.
 // If you wish to have it consume as many bytes as possible, pass -1 as the limit.
-func parseBundle(data []byte, sender net.Addr, limit int32) (Bundle, error) {
+func parseBundle(data []byte, sender net.Addr, limit int32, opts ParseOptions) (Bundle, error) {
 	b := Bundle{}
 
 	// If 0 <= limit < 16 this is an error.
@@ -64,7 +71,7 @@ func parseBundle(data []byte, sender net.Addr, limit int32) (Bundle, error) {
 	}
 
 	// We take away 16 from limit so that readPackets doesn't have to know we have already read 16 bytes.
-	packets, err := readPackets(data, sender, limit-16)
+	packets, err := readPackets(data, sender, limit-16, opts)
 	if err != nil {
 		return b, errors.Wrap(err, "read packets")
 	}
@@ -90,6 +97,8 @@ func (b Bundle) Bytes() []byte {
 }
 
 // Equal returns true if one bundle equals another, and false otherwise.
+// Bundles are equal if their timetags are equal and they contain equal packets
+// in the same order. Nested bundles are compared recursively.
 func (b Bundle) Equal(other Packet) bool {
 	b2, ok := other.(Bundle)
 	if !ok {
@@ -109,6 +118,33 @@ func (b Bundle) Equal(other Packet) bool {
 	return true
 }
 
+// setSeq sets the receive sequence number of every message in the bundle.
+func (b Bundle) setSeq(seq uint64) Bundle {
+	for i, p := range b.Packets {
+		switch x := p.(type) {
+		case Message:
+			x.Seq = seq
+			b.Packets[i] = x
+		case Bundle:
+			b.Packets[i] = x.setSeq(seq)
+		}
+	}
+	return b
+}
+
+// withContext sets the context of all the messages in the bundle, see Message.Context.
+func (b Bundle) withContext(ctx context.Context) Bundle {
+	for i, p := range b.Packets {
+		switch x := p.(type) {
+		case Message:
+			b.Packets[i] = x.WithContext(ctx)
+		case Bundle:
+			b.Packets[i] = x.withContext(ctx)
+		}
+	}
+	return b
+}
+
 // sliceBundleTag slices the bundle tag off the data.
 // If the bundle tag is not present or is not correct, an error is returned.
 func sliceBundleTag(data []byte) ([]byte, error) {
@@ -124,7 +160,7 @@ func sliceBundleTag(data []byte) ([]byte, error) {
 }
 
 // readPackets reads bundle packets from a byte slice.
-func readPackets(data []byte, sender net.Addr, limit int32) ([]Packet, error) {
+func readPackets(data []byte, sender net.Addr, limit int32, opts ParseOptions) ([]Packet, error) {
 	ps := []Packet{}
 
 	var (
@@ -133,7 +169,7 @@ func readPackets(data []byte, sender net.Addr, limit int32) ([]Packet, error) {
 		err error
 	)
 	for {
-		p, l, err = readPacket(data, sender)
+		p, l, err = readPacket(data, sender, opts)
 		if err == ErrEndOfPackets {
 			return ps, nil
 		}
@@ -158,7 +194,7 @@ func readPackets(data []byte, sender net.Addr, limit int32) ([]Packet, error) {
 // If ErrEndOfPackets is returned then Packet will always be nil.
 // The returned packet length includes the length of the packet length integer itself,
 // so it is actually packet_length + 4.
-func readPacket(data []byte, sender net.Addr) (Packet, int32, error) {
+func readPacket(data []byte, sender net.Addr, opts ParseOptions) (Packet, int32, error) {
 	if len(data) < 4 {
 		return nil, int32(len(data)), ErrEndOfPackets
 	}
@@ -176,13 +212,13 @@ func readPacket(data []byte, sender net.Addr) (Packet, int32, error) {
 
 	switch data[0] {
 	case MessageChar:
-		msg, err := ParseMessage(data, sender)
+		msg, err := ParseMessageWith(data, sender, opts)
 		if err != nil {
 			return nil, 0, errors.Wrap(err, "parse message from packet")
 		}
 		return msg, l, nil // The returned length includes the packet length integer.
 	case BundleTag[0]:
-		bundle, err := parseBundle(data, sender, l)
+		bundle, err := parseBundle(data, sender, l, opts)
 		if err != nil {
 			return nil, 0, errors.Wrap(err, "parse bundle from packet")
 		}
@@ -0,0 +1,132 @@
+package osc
+
+import (
+	"bufio"
+	"encoding/binary"
+	"io"
+
+	"github.com/pkg/errors"
+)
+
+// StreamingBundle writes a bundle to an io.Writer one element at a time,
+// so that bundles with very many elements do not have to be held in memory.
+// This is useful for large state dumps written to files or pipes.
+type StreamingBundle struct {
+	w io.Writer
+	n int
+}
+
+// NewStreamingBundle writes the bundle tag and the timetag to w
+// and returns a StreamingBundle that writes elements to w.
+func NewStreamingBundle(w io.Writer, tt Timetag) (*StreamingBundle, error) {
+	if w == nil {
+		return nil, ErrNilWriter
+	}
+	if _, err := w.Write(append(ToBytes(BundleTag), tt.Bytes()...)); err != nil {
+		return nil, errors.Wrap(err, "write bundle header")
+	}
+	return &StreamingBundle{w: w}, nil
+}
+
+// Add writes a bundle element, that is the packet prefixed with its size.
+func (sb *StreamingBundle) Add(p Packet) error {
+	if err := encodeElement(sb.w, p); err != nil {
+		return errors.Wrapf(err, "element %d", sb.n)
+	}
+	sb.n++
+	return nil
+}
+
+// Len returns the number of elements that have been written.
+func (sb *StreamingBundle) Len() int {
+	return sb.n
+}
+
+// encodeElement writes a bundle element to w.
+func encodeElement(w io.Writer, p Packet) error {
+	data := p.Bytes()
+	if err := binary.Write(w, byteOrder, int32(len(data))); err != nil {
+		return errors.Wrap(err, "write element size")
+	}
+	if _, err := w.Write(data); err != nil {
+		return errors.Wrap(err, "write element")
+	}
+	return nil
+}
+
+// BundleReader reads the elements of a bundle from an io.Reader one at a time,
+// e.g. a bundle that was written by a StreamingBundle.
+type BundleReader struct {
+	Timetag Timetag
+
+	r   io.Reader
+	err error
+}
+
+// NewBundleReader reads the bundle tag and the timetag from r
+// and returns a BundleReader that reads elements from r.
+func NewBundleReader(r io.Reader) (*BundleReader, error) {
+	br := bufio.NewReader(r)
+
+	header := make([]byte, len(ToBytes(BundleTag))+TimetagSize)
+	if _, err := io.ReadFull(br, header); err != nil {
+		return nil, errors.Wrap(err, "read bundle header")
+	}
+	data, err := sliceBundleTag(header)
+	if err != nil {
+		return nil, err
+	}
+	tt, err := ReadTimetag(data)
+	if err != nil {
+		return nil, errors.Wrap(err, "read timetag")
+	}
+	return &BundleReader{Timetag: tt, r: br}, nil
+}
+
+// Next reads the next element of the bundle.
+// It returns io.EOF after the last element.
+func (br *BundleReader) Next() (Packet, error) {
+	var size int32
+	if err := binary.Read(br.r, byteOrder, &size); err != nil {
+		if err == io.EOF {
+			return nil, err
+		}
+		return nil, errors.Wrap(err, "read element size")
+	}
+	if size <= 0 {
+		return nil, errors.Wrapf(ErrParse, "invalid element size %d", size)
+	}
+	data := make([]byte, size)
+	if _, err := io.ReadFull(br.r, data); err != nil {
+		return nil, errors.Wrap(err, "read element")
+	}
+	return parsePacket(data, nil)
+}
+
+// Packets returns a channel that receives the elements of the bundle as they are read.
+// The channel is closed after the last element or when an error happens,
+// which Err returns afterwards. The channel must be drained, otherwise the
+// goroutine reading the elements is never done.
+func (br *BundleReader) Packets() <-chan Packet {
+	packets := make(chan Packet)
+	go func() {
+		defer close(packets)
+		for {
+			p, err := br.Next()
+			if err == io.EOF {
+				return
+			}
+			if err != nil {
+				br.err = err
+				return
+			}
+			packets <- p
+		}
+	}()
+	return packets
+}
+
+// Err returns the error that closed the channel returned by Packets, if any.
+func (br *BundleReader) Err() error {
+	return br.err
+}
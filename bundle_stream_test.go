@@ -0,0 +1,107 @@
+package osc
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/pkg/errors"
+)
+
+func TestStreamingBundle(t *testing.T) {
+	const numElements = 70000
+
+	var (
+		buf = &bytes.Buffer{}
+		tt  = Timetag(0x0102030405060708)
+	)
+	sb, err := NewStreamingBundle(buf, tt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < numElements; i++ {
+		if err := sb.Add(Message{Address: "/param", Arguments: []Argument{Int(i)}}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := sb.Add(Bundle{Timetag: tt, Packets: []Packet{Message{Address: "/nested"}}}); err != nil {
+		t.Fatal(err)
+	}
+	if expected, got := numElements+1, sb.Len(); expected != got {
+		t.Fatalf("expected %d elements, got %d", expected, got)
+	}
+
+	// The result can be parsed like any other bundle.
+	b, err := ParseBundle(buf.Bytes(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected, got := numElements+1, len(b.Packets); expected != got {
+		t.Fatalf("expected %d packets, got %d", expected, got)
+	}
+
+	br, err := NewBundleReader(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected, got := tt, br.Timetag; expected != got {
+		t.Fatalf("expected %s, got %s", expected, got)
+	}
+	i := 0
+	for p := range br.Packets() {
+		if !b.Packets[i].Equal(p) {
+			t.Fatalf("(element %d) expected %v, got %v", i, b.Packets[i], p)
+		}
+		i++
+	}
+	if err := br.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if expected, got := numElements+1, i; expected != got {
+		t.Fatalf("expected %d elements, got %d", expected, got)
+	}
+}
+
+func TestStreamingBundleErrors(t *testing.T) {
+	if _, err := NewStreamingBundle(nil, Immediately); err != ErrNilWriter {
+		t.Fatalf("expected ErrNilWriter, got %v", err)
+	}
+	if _, err := NewStreamingBundle(&errWriter{erridx: 1}, Immediately); err == nil {
+		t.Fatal("expected error, got nil")
+	}
+	sb, err := NewStreamingBundle(&errWriter{erridx: 3}, Immediately)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := sb.Add(Message{Address: "/foo"}); err == nil {
+		t.Fatal("expected error, got nil")
+	}
+}
+
+func TestBundleReaderErrors(t *testing.T) {
+	if _, err := NewBundleReader(bytes.NewReader([]byte("#bun"))); err == nil {
+		t.Fatal("expected error, got nil")
+	}
+	if _, err := NewBundleReader(bytes.NewReader([]byte("#notbundle\x00\x00\x00\x00\x00\x00"))); err == nil {
+		t.Fatal("expected error, got nil")
+	}
+	header := Bundle{Timetag: Immediately}.Bytes()
+
+	br, err := NewBundleReader(bytes.NewReader(append(header, 0, 0, 0, 0)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := br.Next(); errors.Cause(err) != ErrParse {
+		t.Fatalf("expected ErrParse, got %v", err)
+	}
+	br, err = NewBundleReader(bytes.NewReader(append(header, 0, 0, 0, 8, '/', 'f')))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for range br.Packets() {
+		t.Fatal("expected no packets")
+	}
+	if errors.Cause(br.Err()) != io.ErrUnexpectedEOF {
+		t.Fatalf("expected io.ErrUnexpectedEOF, got %v", br.Err())
+	}
+}
@@ -129,6 +129,82 @@ func TestBundleEqual(t *testing.T) {
 				},
 			},
 		},
+		{
+			b: Bundle{
+				Timetag: 5,
+				Packets: []Packet{
+					Message{Address: "/bar", Arguments: []Argument{Int(1)}},
+					Bundle{
+						Timetag: 6,
+						Packets: []Packet{
+							Message{Address: "/baz", Arguments: []Argument{Float(2)}},
+						},
+					},
+				},
+			},
+			e: []Bundle{
+				{
+					Timetag: 5,
+					Packets: []Packet{
+						Message{Address: "/bar", Arguments: []Argument{Int(1)}},
+						Bundle{
+							Timetag: 6,
+							Packets: []Packet{
+								Message{Address: "/baz", Arguments: []Argument{Float(2)}},
+							},
+						},
+					},
+				},
+			},
+			ne: []Packet{
+				// Differing nested message.
+				Bundle{
+					Timetag: 5,
+					Packets: []Packet{
+						Message{Address: "/bar", Arguments: []Argument{Int(1)}},
+						Bundle{
+							Timetag: 6,
+							Packets: []Packet{
+								Message{Address: "/baz", Arguments: []Argument{Float(3)}},
+							},
+						},
+					},
+				},
+				// Differing nested timetag.
+				Bundle{
+					Timetag: 5,
+					Packets: []Packet{
+						Message{Address: "/bar", Arguments: []Argument{Int(1)}},
+						Bundle{
+							Timetag: 7,
+							Packets: []Packet{
+								Message{Address: "/baz", Arguments: []Argument{Float(2)}},
+							},
+						},
+					},
+				},
+				// Differing packet order.
+				Bundle{
+					Timetag: 5,
+					Packets: []Packet{
+						Bundle{
+							Timetag: 6,
+							Packets: []Packet{
+								Message{Address: "/baz", Arguments: []Argument{Float(2)}},
+							},
+						},
+						Message{Address: "/bar", Arguments: []Argument{Int(1)}},
+					},
+				},
+				// Differing packet count.
+				Bundle{
+					Timetag: 5,
+					Packets: []Packet{
+						Message{Address: "/bar", Arguments: []Argument{Int(1)}},
+					},
+				},
+			},
+		},
 	} {
 		b := testcase.b
 		for i, e := range testcase.e {
@@ -298,7 +374,7 @@ func TestParseBundle(t *testing.T) {
 				[]byte{},
 			),
 			Expected: Output{
-				err: errors.New(`read packets: read packet: parse message from packet: parse message: read argument 1: typetag "Q": invalid type tag`),
+				err: errors.New(`read packets: read packet: parse message from packet: parsing argument 1 (typetag 'Q'): invalid type tag`),
 			},
 		},
 		// testcase 9
@@ -363,7 +439,7 @@ func TestParseBundle(t *testing.T) {
 				{0x3F, 0x80, 0x00, 0x00},
 			}, []byte{}),
 			Expected: Output{
-				err: errors.New(`read packets: read packet: parse bundle from packet: read packets: read packet: parse message from packet: parse message: read argument 0: typetag "Q": invalid type tag`),
+				err: errors.New(`read packets: read packet: parse bundle from packet: read packets: read packet: parse message from packet: parsing argument 0 (typetag 'Q'): invalid type tag`),
 			},
 		},
 	} {
@@ -385,8 +461,24 @@ func TestParseBundle(t *testing.T) {
 
 func TestParseBundleLimit(t *testing.T) {
 	// Test the limit parameter of parseBundle.
-	_, limitErr := parseBundle(nil, nil, 10)
+	_, limitErr := parseBundle(nil, nil, 10, ParseOptions{})
 	if expected, got := errors.New("limit must be >= 16 or < 0"), limitErr; got == nil || (expected.Error() != got.Error()) {
 		t.Fatalf("expected %s, got %s", expected, got)
 	}
 }
+
+func TestBundleSetSeq(t *testing.T) {
+	b := Bundle{
+		Packets: []Packet{
+			Message{Address: "/foo"},
+			Bundle{Packets: []Packet{Message{Address: "/bar"}}},
+		},
+	}.setSeq(7)
+
+	if expected, got := uint64(7), b.Packets[0].(Message).Seq; expected != got {
+		t.Fatalf("expected %d, got %d", expected, got)
+	}
+	if expected, got := uint64(7), b.Packets[1].(Bundle).Packets[0].(Message).Seq; expected != got {
+		t.Fatalf("expected %d, got %d", expected, got)
+	}
+}
@@ -0,0 +1,94 @@
+package osc
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+)
+
+// DetectByteOrder guesses the byte order that encoded arguments were written with.
+// OSC is big-endian, but some nonconforming peers send little-endian data.
+// data holds the encoded arguments and typetags their type tags, with or without the leading comma.
+//
+// Each int and float is decoded in both byte orders, and the order that yields more
+// plausible values wins: ints of moderate magnitude, and floats that are
+// neither NaN, infinite, nor very small or very large. A blob length that exceeds
+// the data counts against the byte order that read it.
+//
+// The returned confidence is between 0 and 1, where 0 means the data does not
+// favor either byte order, in which case binary.BigEndian is returned.
+// This is a heuristic meant for debugging interoperability problems.
+func DetectByteOrder(data []byte, typetags string) (binary.ByteOrder, float64) {
+	if len(typetags) > 0 && typetags[0] == TypetagPrefix {
+		typetags = typetags[1:]
+	}
+	be, n := plausibility(data, typetags, binary.BigEndian)
+	le, _ := plausibility(data, typetags, binary.LittleEndian)
+	if n == 0 || be == le {
+		return binary.BigEndian, 0
+	}
+	if le > be {
+		return binary.LittleEndian, float64(le-be) / float64(n)
+	}
+	return binary.BigEndian, float64(be-le) / float64(n)
+}
+
+// plausibility returns the number of plausible values in data when it is read
+// with the given byte order, and the number of values that were checked.
+func plausibility(data []byte, typetags string, order binary.ByteOrder) (score, checked int) {
+	for _, tt := range []byte(typetags) {
+		switch tt {
+		case TypetagInt, TypetagFloat:
+			if len(data) < 4 {
+				return score, checked
+			}
+			v := order.Uint32(data)
+			checked++
+			if tt == TypetagInt && plausibleInt(int32(v)) || tt == TypetagFloat && plausibleFloat(math.Float32frombits(v)) {
+				score++
+			}
+			data = data[4:]
+		case TypetagString:
+			idx := bytes.IndexByte(data, 0)
+			if idx < 0 {
+				return score, checked
+			}
+			n := paddedLen(idx + 1)
+			if n > len(data) {
+				n = len(data)
+			}
+			data = data[n:]
+		case TypetagBlob:
+			if len(data) < 4 {
+				return score, checked
+			}
+			length := int64(order.Uint32(data))
+			checked++
+			if length > int64(len(data)-4) {
+				return score, checked
+			}
+			score++
+			n := paddedLen(int(length))
+			if n > len(data)-4 {
+				n = len(data) - 4
+			}
+			data = data[4+n:]
+		}
+	}
+	return score, checked
+}
+
+// plausibleInt returns true if the int has a magnitude that is common in OSC messages.
+func plausibleInt(i int32) bool {
+	return i > -(1<<24) && i < 1<<24
+}
+
+// plausibleFloat returns true if the float is finite and
+// zero or of a magnitude that is common in OSC messages.
+func plausibleFloat(f float32) bool {
+	abs := math.Abs(float64(f))
+	if math.IsNaN(abs) || math.IsInf(abs, 0) {
+		return false
+	}
+	return abs == 0 || (abs >= 1e-6 && abs <= 1e7)
+}
@@ -0,0 +1,55 @@
+package osc
+
+import (
+	"encoding/binary"
+	"math"
+	"testing"
+)
+
+func TestDetectByteOrder(t *testing.T) {
+	msg := Message{Address: "/foo", Arguments: []Argument{Int(42), Float(440), String("bar"), Float(-0.5)}}
+	be, err := msg.ArgumentBytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	typetags := msg.TypetagString()
+
+	le := make([]byte, 0, len(be))
+	le = binary.LittleEndian.AppendUint32(le, 42)
+	le = binary.LittleEndian.AppendUint32(le, math.Float32bits(440))
+	le = append(le, "bar\x00"...)
+	le = binary.LittleEndian.AppendUint32(le, math.Float32bits(-0.5))
+
+	order, confidence := DetectByteOrder(le, typetags)
+	if expected, got := binary.ByteOrder(binary.LittleEndian), order; expected != got {
+		t.Fatalf("expected %s, got %s", expected, got)
+	}
+	if confidence <= 0.5 {
+		t.Fatalf("expected confidence above 0.5, got %f", confidence)
+	}
+	order, confidence = DetectByteOrder(be, typetags)
+	if expected, got := binary.ByteOrder(binary.BigEndian), order; expected != got {
+		t.Fatalf("expected %s, got %s", expected, got)
+	}
+	if confidence <= 0.5 {
+		t.Fatalf("expected confidence above 0.5, got %f", confidence)
+	}
+}
+
+func TestDetectByteOrderUndecided(t *testing.T) {
+	// Zero reads the same in both byte orders.
+	order, confidence := DetectByteOrder(make([]byte, 8), "ii")
+	if expected, got := binary.ByteOrder(binary.BigEndian), order; expected != got {
+		t.Fatalf("expected %s, got %s", expected, got)
+	}
+	if expected, got := 0.0, confidence; expected != got {
+		t.Fatalf("expected %f, got %f", expected, got)
+	}
+}
+
+func TestDetectByteOrderBlob(t *testing.T) {
+	data := []byte{3, 0, 0, 0, 1, 2, 3, 0}
+	if order, _ := DetectByteOrder(data, "b"); order != binary.LittleEndian {
+		t.Fatalf("expected %s, got %s", binary.LittleEndian, order)
+	}
+}
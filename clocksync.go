@@ -0,0 +1,120 @@
+package osc
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Addresses used for clock synchronization.
+const (
+	ClockPingAddress = "/osc/ping"
+	ClockPongAddress = "/osc/pong"
+)
+
+// clockSyncTimeout is how long MeasureClockOffset waits for each pong.
+var clockSyncTimeout = time.Second
+
+// ClockSyncMethod returns a method that answers the pings sent by MeasureClockOffset.
+// Add it to a server's dispatcher at ClockPingAddress.
+// The pong it sends to the sender contains the ping's timestamp,
+// the time the ping was received, and the time the pong was sent,
+// each as a blob containing an OSC timetag.
+func ClockSyncMethod(conn Conn) Method {
+	return Method(func(msg Message) error {
+		received := FromTime(time.Now())
+		if len(msg.Arguments) != 1 {
+			return errors.Errorf("expected 1 argument, got %d", len(msg.Arguments))
+		}
+		return conn.SendTo(msg.Sender, Message{
+			Address: ClockPongAddress,
+			Arguments: []Argument{
+				msg.Arguments[0],
+				Blob(received.Bytes()),
+				Blob(FromTime(time.Now()).Bytes()),
+			},
+		})
+	})
+}
+
+// MeasureClockOffset estimates the offset of the remote peer's clock
+// from the local clock using the NTP algorithm.
+// The returned duration can be added to time.Now() to get an estimate
+// of the remote peer's time.
+//
+// The connection must be dialed to a peer that serves ClockSyncMethod,
+// and must not be serving, since MeasureClockOffset reads the pongs itself.
+// Of all the rounds, the one with the shortest round trip is used,
+// since it is the least affected by network jitter.
+func MeasureClockOffset(conn Conn, rounds int) (time.Duration, error) {
+	if rounds < 1 {
+		return 0, errors.New("rounds must be at least 1")
+	}
+	var (
+		best     time.Duration
+		minDelay time.Duration = -1
+		data                   = make([]byte, bufSize)
+	)
+	defer func() { _ = conn.SetReadDeadline(time.Time{}) }() // Best effort.
+
+	for i := 0; i < rounds; i++ {
+		sent := time.Now()
+		if err := conn.Send(Message{
+			Address:   ClockPingAddress,
+			Arguments: []Argument{Blob(FromTime(sent).Bytes())},
+		}); err != nil {
+			return 0, errors.Wrap(err, "send ping")
+		}
+		if err := conn.SetReadDeadline(time.Now().Add(clockSyncTimeout)); err != nil {
+			return 0, errors.Wrap(err, "set read deadline")
+		}
+		n, err := conn.Read(data)
+		if err != nil {
+			return 0, errors.Wrap(err, "read pong")
+		}
+		received := time.Now()
+
+		t1, t2, t3, err := readPong(data[:n])
+		if err != nil {
+			return 0, err
+		}
+		if !t1.Equal(FromTime(sent).Time()) {
+			return 0, errors.New("pong does not belong to the last ping")
+		}
+		var (
+			offset = (t2.Sub(t1) + t3.Sub(received)) / 2
+			delay  = received.Sub(t1) - t3.Sub(t2)
+		)
+		if minDelay < 0 || delay < minDelay {
+			best, minDelay = offset, delay
+		}
+	}
+	return best, nil
+}
+
+// readPong reads the timestamps from a pong message.
+func readPong(data []byte) (t1, t2, t3 time.Time, err error) {
+	msg, err := ParseMessage(data, nil)
+	if err != nil {
+		return t1, t2, t3, errors.Wrap(err, "parse pong")
+	}
+	if msg.Address != ClockPongAddress {
+		return t1, t2, t3, errors.Errorf("expected %s, got %s", ClockPongAddress, msg.Address)
+	}
+	if len(msg.Arguments) != 3 {
+		return t1, t2, t3, errors.Errorf("expected 3 arguments, got %d", len(msg.Arguments))
+	}
+	times := make([]time.Time, 3)
+	for i, arg := range msg.Arguments {
+		b, err := arg.ReadBlob()
+		if err != nil {
+			return t1, t2, t3, errors.Wrapf(err, "read timestamp %d", i)
+		}
+		tt, err := ReadTimetag(b)
+		if err != nil {
+			return t1, t2, t3, errors.Wrapf(err, "read timestamp %d", i)
+		}
+		times[i] = tt.Time()
+	}
+	return times[0], times[1], times[2], nil
+}
@@ -0,0 +1,63 @@
+package osc
+
+import (
+	"net"
+	"testing"
+	"time"
+)
+
+func TestMeasureClockOffset(t *testing.T) {
+	laddr, err := net.ResolveUDPAddr("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	server, err := ListenUDP("udp", laddr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = server.Close() }() // Best effort.
+
+	go func() {
+		_ = server.Serve(1, Dispatcher{
+			ClockPingAddress: ClockSyncMethod(server),
+		})
+	}()
+
+	raddr, err := net.ResolveUDPAddr("udp", server.LocalAddr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	client, err := DialUDP("udp", nil, raddr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = client.Close() }() // Best effort.
+
+	offset, err := MeasureClockOffset(client, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Both ends share the same clock.
+	if offset < -50*time.Millisecond || offset > 50*time.Millisecond {
+		t.Fatalf("expected an offset close to zero, got %s", offset)
+	}
+}
+
+func TestMeasureClockOffsetRounds(t *testing.T) {
+	if _, err := MeasureClockOffset(nil, 0); err == nil {
+		t.Fatal("expected error, got nil")
+	}
+}
+
+func TestReadPong(t *testing.T) {
+	for _, msg := range []Message{
+		{Address: "/foo"},
+		{Address: ClockPongAddress},
+		{Address: ClockPongAddress, Arguments: []Argument{Int(1), Int(2), Int(3)}},
+		{Address: ClockPongAddress, Arguments: []Argument{Blob{}, Blob{}, Blob{}}},
+	} {
+		if _, _, _, err := readPong(msg.Bytes()); err == nil {
+			t.Fatalf("(%v) expected error, got nil", msg)
+		}
+	}
+}
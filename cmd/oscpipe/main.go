@@ -0,0 +1,114 @@
+// Command oscpipe bridges OSC over UDP and a pair of pipes.
+// Messages read from stdin are sent to a UDP address, and messages received
+// over UDP are written to stdout. On both pipes every message is prefixed
+// with its int32 size, see osc.ReadMessageFromPipe and osc.WriteMessageToPipe.
+// The messages of bundles received over UDP are written to stdout one by one.
+//
+// Usage:
+//
+//	oscpipe -listen 127.0.0.1:57121 -send 127.0.0.1:57120
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"net"
+	"os"
+
+	"github.com/pkg/errors"
+	"github.com/scgolang/osc"
+)
+
+func main() {
+	var (
+		listen = flag.String("listen", "127.0.0.1:0", "local UDP address to receive messages on")
+		send   = flag.String("send", "", "remote UDP address to send messages from stdin to")
+	)
+	flag.Parse()
+
+	if err := run(*listen, *send); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+// run forwards messages between the pipes and UDP until stdin is closed.
+func run(listen, send string) error {
+	laddr, err := net.ResolveUDPAddr("udp", listen)
+	if err != nil {
+		return errors.Wrap(err, "resolve listen address")
+	}
+	conn, err := osc.ListenUDP("udp", laddr)
+	if err != nil {
+		return errors.Wrap(err, "listen")
+	}
+	defer func() { _ = conn.Close() }() // Best effort.
+
+	fmt.Fprintf(os.Stderr, "oscpipe: listening on %s\n", conn.LocalAddr())
+
+	errs := make(chan error, 2)
+	go func() {
+		errs <- receive(conn, os.Stdout)
+	}()
+	if send != "" {
+		raddr, err := net.ResolveUDPAddr("udp", send)
+		if err != nil {
+			return errors.Wrap(err, "resolve send address")
+		}
+		go func() {
+			errs <- forward(os.Stdin, conn, raddr)
+		}()
+	}
+	return <-errs
+}
+
+// forward sends the messages read from r to raddr until r ends.
+func forward(r io.Reader, conn *osc.UDPConn, raddr net.Addr) error {
+	for {
+		msg, err := osc.ReadMessageFromPipe(r)
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if err := conn.SendTo(raddr, msg); err != nil {
+			return errors.Wrap(err, "send message")
+		}
+	}
+}
+
+// receive writes the messages received over UDP to w.
+func receive(conn *osc.UDPConn, w io.Writer) error {
+	buf := make([]byte, 65536)
+	for {
+		n, _, err := conn.ReadFromUDP(buf)
+		if err != nil {
+			return errors.Wrap(err, "receive packet")
+		}
+		p, _, err := osc.ParsePacketN(buf[:n], nil)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "oscpipe: %s\n", err)
+			continue
+		}
+		if err := writePacket(p, w); err != nil {
+			return err
+		}
+	}
+}
+
+// writePacket writes a message, or the messages of a bundle, to w.
+func writePacket(p osc.Packet, w io.Writer) error {
+	switch x := p.(type) {
+	case osc.Message:
+		return osc.WriteMessageToPipe(x, w)
+	case osc.Bundle:
+		for _, nested := range x.Packets {
+			if err := writePacket(nested, w); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+package osc
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/pkg/errors"
+)
+
+// TypetagColor is the type tag of Color arguments.
+// It is an optional type of the OSC specification, so some receivers may reject it.
+const TypetagColor byte = 'r'
+
+// Color represents a 32-bit RGBA color with 8 bits per channel.
+type Color struct {
+	R, G, B, A uint8
+}
+
+// ReadColorFrom reads a color from a byte slice.
+func ReadColorFrom(data []byte) (Argument, int64, error) {
+	if len(data) < 4 {
+		return nil, 0, errors.Wrap(io.ErrUnexpectedEOF, "read color argument")
+	}
+	return Color{R: data[0], G: data[1], B: data[2], A: data[3]}, 4, nil
+}
+
+// Bytes converts the arg to a byte slice suitable for adding to the binary representation of an OSC message.
+func (c Color) Bytes() []byte {
+	return []byte{c.R, c.G, c.B, c.A}
+}
+
+// Equal returns true if the argument equals the other one, false otherwise.
+func (c Color) Equal(other Argument) bool {
+	if other.Typetag() != TypetagColor {
+		return false
+	}
+	c2 := other.(Color)
+	return c == c2
+}
+
+// Floats returns the channels of the color normalized to [0, 1].
+func (c Color) Floats() (r, g, b, a float32) {
+	return float32(c.R) / 255, float32(c.G) / 255, float32(c.B) / 255, float32(c.A) / 255
+}
+
+// ReadInt32 reads a 32-bit integer from the arg.
+func (c Color) ReadInt32() (int32, error) { return 0, ErrInvalidTypeTag }
+
+// ReadInt64 reads a 64-bit integer from the arg.
+func (c Color) ReadInt64() (int64, error) { return 0, ErrInvalidTypeTag }
+
+// ReadFloat32 reads a 32-bit float from the arg.
+func (c Color) ReadFloat32() (float32, error) { return 0, ErrInvalidTypeTag }
+
+// ReadBool bool reads a boolean from the arg.
+func (c Color) ReadBool() (bool, error) { return false, ErrInvalidTypeTag }
+
+// ReadString string reads a string from the arg.
+func (c Color) ReadString() (string, error) { return "", ErrInvalidTypeTag }
+
+// ReadBlob reads a slice of bytes from the arg.
+func (c Color) ReadBlob() ([]byte, error) { return nil, ErrInvalidTypeTag }
+
+// String converts the arg to a string.
+func (c Color) String() string { return fmt.Sprintf("Color(#%02x%02x%02x%02x)", c.R, c.G, c.B, c.A) }
+
+// Typetag returns the argument's type tag.
+func (c Color) Typetag() byte { return TypetagColor }
+
+// Size returns the number of bytes of the encoded arg.
+func (c Color) Size() int { return 4 }
+
+// WriteTo writes the arg to an io.Writer.
+func (c Color) WriteTo(w io.Writer) (int64, error) {
+	written, err := fmt.Fprintf(w, "#%02x%02x%02x%02x", c.R, c.G, c.B, c.A)
+	return int64(written), err
+}
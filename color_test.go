@@ -0,0 +1,32 @@
+package osc
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestColor(t *testing.T) {
+	msg := Message{Address: "/light", Arguments: []Argument{Color{R: 0xff, G: 0x80, B: 0, A: 0x33}}}
+	data := msg.Bytes()
+
+	got, err := ParseMessage(data, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !msg.Equal(got) {
+		t.Fatalf("expected %v, got %v", msg, got)
+	}
+	buf := make([]byte, msg.EncodedLen())
+	if _, err := msg.MarshalTo(buf); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(data, buf) {
+		t.Fatalf("expected %x, got %x", data, buf)
+	}
+	if expected, got := "Color(#ff800033)", msg.Arguments[0].String(); expected != got {
+		t.Fatalf("expected %s, got %s", expected, got)
+	}
+	if _, _, err := ReadColorFrom([]byte{1, 2, 3}); err == nil {
+		t.Fatal("expected error, got nil")
+	}
+}
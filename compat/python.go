@@ -0,0 +1,86 @@
+// Package compat helps OSC code interoperate with other languages and libraries.
+package compat
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/scgolang/osc"
+)
+
+// Common errors.
+var (
+	ErrByteOrder   = errors.New("byte order must be big-endian")
+	ErrUnsupported = errors.New("type has no equivalent")
+)
+
+// PythonStructToTypetags converts a Python struct format string like "!if"
+// to an OSC type tag string like ",if".
+//
+// OSC is big-endian, so the format must start with '!' or '>'.
+// Only 'i' and 'l' (both 32-bit signed ints in standard mode) and 'f' are supported,
+// optionally preceded by a repeat count as in "3f". Unsigned and 64-bit types have
+// no OSC equivalent, and neither do Python's fixed-size strings.
+func PythonStructToTypetags(format string) (string, error) {
+	if len(format) == 0 || (format[0] != '!' && format[0] != '>') {
+		return "", errors.Wrapf(ErrByteOrder, "format %q", format)
+	}
+	var (
+		tags  = []byte{osc.TypetagPrefix}
+		count = ""
+	)
+	for i := 1; i < len(format); i++ {
+		c := format[i]
+		if c >= '0' && c <= '9' {
+			count += string(c)
+			continue
+		}
+		if c == ' ' || c == '\t' {
+			continue
+		}
+		var tt byte
+		switch c {
+		case 'i', 'l':
+			tt = osc.TypetagInt
+		case 'f':
+			tt = osc.TypetagFloat
+		default:
+			return "", errors.Wrapf(ErrUnsupported, "format character %q", string(c))
+		}
+		n := 1
+		if count != "" {
+			var err error
+			if n, err = strconv.Atoi(count); err != nil {
+				return "", errors.Wrapf(err, "repeat count %s", count)
+			}
+			count = ""
+		}
+		tags = append(tags, strings.Repeat(string(tt), n)...)
+	}
+	if count != "" {
+		return "", errors.Errorf("repeat count %s is not followed by a format character", count)
+	}
+	return string(tags), nil
+}
+
+// TypetagsToPythonStruct converts OSC type tags like ",if" to a
+// Python struct format string like "!if". The leading comma is optional.
+// Only int and float type tags are supported, since the other OSC types
+// either have a variable length or are not encoded in the argument data.
+func TypetagsToPythonStruct(tags string) (string, error) {
+	tags = strings.TrimPrefix(tags, string(osc.TypetagPrefix))
+
+	format := []byte{'!'}
+	for _, tt := range []byte(tags) {
+		switch tt {
+		case osc.TypetagInt:
+			format = append(format, 'i')
+		case osc.TypetagFloat:
+			format = append(format, 'f')
+		default:
+			return "", errors.Wrapf(ErrUnsupported, "typetag %q", string(tt))
+		}
+	}
+	return string(format), nil
+}
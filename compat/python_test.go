@@ -0,0 +1,65 @@
+package compat
+
+import (
+	"testing"
+
+	"github.com/pkg/errors"
+)
+
+func TestPythonStructToTypetags(t *testing.T) {
+	for _, testcase := range []struct {
+		Format   string
+		Expected string
+	}{
+		{Format: "!", Expected: ","},
+		{Format: "!if", Expected: ",if"},
+		{Format: ">lf", Expected: ",if"},
+		{Format: "!2i3f", Expected: ",iifff"},
+		{Format: "! i f", Expected: ",if"},
+	} {
+		tags, err := PythonStructToTypetags(testcase.Format)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if expected, got := testcase.Expected, tags; expected != got {
+			t.Fatalf("(%s) expected %s, got %s", testcase.Format, expected, got)
+		}
+	}
+	for _, testcase := range []struct {
+		Format   string
+		Expected error
+	}{
+		{Format: "", Expected: ErrByteOrder},
+		{Format: "if", Expected: ErrByteOrder},
+		{Format: "<if", Expected: ErrByteOrder},
+		{Format: "@if", Expected: ErrByteOrder},
+		{Format: "!I", Expected: ErrUnsupported},
+		{Format: "!H", Expected: ErrUnsupported},
+		{Format: "!d", Expected: ErrUnsupported},
+		{Format: "!4s", Expected: ErrUnsupported},
+	} {
+		if _, err := PythonStructToTypetags(testcase.Format); errors.Cause(err) != testcase.Expected {
+			t.Fatalf("(%s) expected %v, got %v", testcase.Format, testcase.Expected, err)
+		}
+	}
+	if _, err := PythonStructToTypetags("!i2"); err == nil {
+		t.Fatal("expected error, got nil")
+	}
+}
+
+func TestTypetagsToPythonStruct(t *testing.T) {
+	for _, tags := range []string{",iff", "iff"} {
+		format, err := TypetagsToPythonStruct(tags)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if expected, got := "!iff", format; expected != got {
+			t.Fatalf("expected %s, got %s", expected, got)
+		}
+	}
+	for _, tags := range []string{",s", ",b", ",T"} {
+		if _, err := TypetagsToPythonStruct(tags); errors.Cause(err) != ErrUnsupported {
+			t.Fatalf("(%s) expected ErrUnsupported, got %v", tags, err)
+		}
+	}
+}
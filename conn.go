@@ -16,6 +16,7 @@ const (
 // Common errors.
 var (
 	ErrNilDispatcher  = errors.New("nil dispatcher")
+	ErrNilPacket      = errors.New("nil packet")
 	ErrPrematureClose = errors.New("server cannot be closed before calling Listen")
 )
 
@@ -33,6 +34,12 @@ var invalidAddressRunes = []rune{'*', '?', ',', '[', ']', '{', '}', '#', ' '}
 
 // ValidateAddress returns an error if addr contains
 // characters that are disallowed by the OSC spec.
+//
+// It validates the concrete addresses of methods, which must not contain
+// '*', '?', ',', '[', ']', '{', '}', '#' or ' ', not even escaped.
+// In the address patterns of messages '*', '?', ',', '[', ']', '{' and '}' are wildcards;
+// a pattern matches them literally, e.g. to reach a peer whose method addresses
+// do not follow the spec, if they are escaped with EscapeAddressSegment.
 func ValidateAddress(addr string) error {
 	for _, chr := range invalidAddressRunes {
 		if strings.ContainsRune(addr, chr) {
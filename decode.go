@@ -0,0 +1,83 @@
+package osc
+
+import (
+	"reflect"
+
+	"github.com/pkg/errors"
+)
+
+// Common errors.
+var (
+	ErrArgumentCount = errors.New("number of arguments does not match number of destinations")
+	ErrInvalidDest   = errors.New("destination must be a non-nil pointer to int32, float32, bool, string or []byte")
+)
+
+// DecodeArguments populates the values pointed to by dest with the arguments.
+// Each destination must be a pointer to an int32, float32, bool, string or []byte
+// (or a type whose underlying type is one of these), and the argument at the
+// same position must have the matching type.
+//
+//	var (
+//		note int32
+//		vel  float32
+//	)
+//	err := osc.DecodeArguments(msg.Arguments, &note, &vel)
+func DecodeArguments(args []Argument, dest ...interface{}) error {
+	if len(args) != len(dest) {
+		return errors.Wrapf(ErrArgumentCount, "%d arguments, %d destinations", len(args), len(dest))
+	}
+	for i, d := range dest {
+		if err := decodeArgument(args[i], d); err != nil {
+			return errors.Wrapf(err, "decode argument %d", i)
+		}
+	}
+	return nil
+}
+
+// decodeArgument sets the value pointed to by dest to the argument's value.
+func decodeArgument(arg Argument, dest interface{}) error {
+	v := reflect.ValueOf(dest)
+	if v.Kind() != reflect.Ptr || v.IsNil() {
+		return ErrInvalidDest
+	}
+	v = v.Elem()
+
+	switch v.Kind() {
+	case reflect.Int32:
+		i, err := arg.ReadInt32()
+		if err != nil {
+			return err
+		}
+		v.SetInt(int64(i))
+	case reflect.Float32:
+		f, err := arg.ReadFloat32()
+		if err != nil {
+			return err
+		}
+		v.SetFloat(float64(f))
+	case reflect.Bool:
+		b, err := arg.ReadBool()
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	case reflect.String:
+		s, err := arg.ReadString()
+		if err != nil {
+			return err
+		}
+		v.SetString(s)
+	case reflect.Slice:
+		if v.Type().Elem().Kind() != reflect.Uint8 {
+			return ErrInvalidDest
+		}
+		b, err := arg.ReadBlob()
+		if err != nil {
+			return err
+		}
+		v.SetBytes(b)
+	default:
+		return ErrInvalidDest
+	}
+	return nil
+}
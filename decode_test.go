@@ -0,0 +1,66 @@
+package osc
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/pkg/errors"
+)
+
+func TestDecodeArguments(t *testing.T) {
+	var (
+		note  int32
+		vel   float32
+		on    bool
+		name  string
+		data  []byte
+		level Int
+	)
+	args := []Argument{Int(60), Float(0.5), Bool(true), String("piano"), Blob([]byte{1, 2}), Int(3)}
+
+	if err := DecodeArguments(args, &note, &vel, &on, &name, &data, &level); err != nil {
+		t.Fatal(err)
+	}
+	if expected, got := int32(60), note; expected != got {
+		t.Fatalf("expected %d, got %d", expected, got)
+	}
+	if expected, got := float32(0.5), vel; expected != got {
+		t.Fatalf("expected %f, got %f", expected, got)
+	}
+	if expected, got := true, on; expected != got {
+		t.Fatalf("expected %t, got %t", expected, got)
+	}
+	if expected, got := "piano", name; expected != got {
+		t.Fatalf("expected %s, got %s", expected, got)
+	}
+	if expected, got := []byte{1, 2}, data; !bytes.Equal(expected, got) {
+		t.Fatalf("expected %v, got %v", expected, got)
+	}
+	if expected, got := Int(3), level; expected != got {
+		t.Fatalf("expected %d, got %d", expected, got)
+	}
+}
+
+func TestDecodeArgumentsErrors(t *testing.T) {
+	var (
+		i int32
+		f float32
+		u uint8
+	)
+	for _, testcase := range []struct {
+		Args     []Argument
+		Dest     []interface{}
+		Expected error
+	}{
+		{Args: []Argument{Int(1)}, Dest: []interface{}{&i, &f}, Expected: ErrArgumentCount},
+		{Args: []Argument{Int(1)}, Dest: []interface{}{&f}, Expected: ErrInvalidTypeTag},
+		{Args: []Argument{Int(1)}, Dest: []interface{}{i}, Expected: ErrInvalidDest},
+		{Args: []Argument{Int(1)}, Dest: []interface{}{(*int32)(nil)}, Expected: ErrInvalidDest},
+		{Args: []Argument{Int(1)}, Dest: []interface{}{&u}, Expected: ErrInvalidDest},
+		{Args: []Argument{Blob{}}, Dest: []interface{}{&[]int{}}, Expected: ErrInvalidDest},
+	} {
+		if expected, got := testcase.Expected, errors.Cause(DecodeArguments(testcase.Args, testcase.Dest...)); expected != got {
+			t.Fatalf("expected %v, got %v", expected, got)
+		}
+	}
+}
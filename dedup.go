@@ -0,0 +1,76 @@
+package osc
+
+import (
+	"container/list"
+	"hash/fnv"
+	"sync"
+	"time"
+)
+
+// DeduplicateFilter detects messages that arrive more than once within a time window,
+// e.g. because of network jitter.
+// Messages are duplicates if their address, type tags and arguments are equal.
+// It is safe for concurrent use.
+type DeduplicateFilter struct {
+	window time.Duration
+	now    func() time.Time
+
+	mu    sync.Mutex
+	seen  map[uint64]*list.Element
+	order *list.List // Of seenMessage, oldest first.
+}
+
+// seenMessage is a message that was seen by a DeduplicateFilter.
+type seenMessage struct {
+	hash uint64
+	at   time.Time
+}
+
+// NewDeduplicateFilter creates a filter that considers a message a duplicate
+// if an equal message was seen less than window ago.
+func NewDeduplicateFilter(window time.Duration) *DeduplicateFilter {
+	return &DeduplicateFilter{
+		window: window,
+		now:    time.Now,
+		seen:   map[uint64]*list.Element{},
+		order:  list.New(),
+	}
+}
+
+// IsDuplicate returns true if an equal message was seen within the filter's window.
+// Otherwise the message is remembered and false is returned.
+// Messages are compared by a 64-bit hash of their encoding.
+func (f *DeduplicateFilter) IsDuplicate(msg Message) bool {
+	h := fnv.New64a()
+	_, _ = h.Write(msg.Bytes()) // Never fails.
+	sum := h.Sum64()
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	now := f.now()
+	f.expire(now)
+
+	if _, ok := f.seen[sum]; ok {
+		return true
+	}
+	f.seen[sum] = f.order.PushBack(seenMessage{hash: sum, at: now})
+	return false
+}
+
+// Transform is a Transformer that drops duplicate messages.
+func (f *DeduplicateFilter) Transform(msg Message) (Message, bool, error) {
+	return msg, !f.IsDuplicate(msg), nil
+}
+
+// expire forgets the messages that were seen longer than the window ago.
+func (f *DeduplicateFilter) expire(now time.Time) {
+	for e := f.order.Front(); e != nil; e = f.order.Front() {
+		sm := e.Value.(seenMessage)
+		if now.Sub(sm.at) < f.window {
+			return
+		}
+		f.order.Remove(e)
+		delete(f.seen, sm.hash)
+	}
+}
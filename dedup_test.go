@@ -0,0 +1,49 @@
+package osc
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDeduplicateFilter(t *testing.T) {
+	var (
+		now = time.Now()
+		f   = NewDeduplicateFilter(10 * time.Millisecond)
+		msg = Message{Address: "/foo", Arguments: []Argument{Int(1)}}
+	)
+	f.now = func() time.Time { return now }
+
+	if f.IsDuplicate(msg) {
+		t.Fatal("expected first message not to be a duplicate")
+	}
+	if !f.IsDuplicate(msg) {
+		t.Fatal("expected second message to be a duplicate")
+	}
+	if f.IsDuplicate(Message{Address: "/foo", Arguments: []Argument{Int(2)}}) {
+		t.Fatal("expected message with different arguments not to be a duplicate")
+	}
+	if f.IsDuplicate(Message{Address: "/foo", Arguments: []Argument{Float(1)}}) {
+		t.Fatal("expected message with different type tags not to be a duplicate")
+	}
+	now = now.Add(10 * time.Millisecond)
+
+	if f.IsDuplicate(msg) {
+		t.Fatal("expected message outside the window not to be a duplicate")
+	}
+	if expected, got := 1, len(f.seen); expected != got {
+		t.Fatalf("expected %d remembered messages, got %d", expected, got)
+	}
+}
+
+func TestDeduplicateFilterTransform(t *testing.T) {
+	var (
+		f   = NewDeduplicateFilter(time.Minute)
+		msg = Message{Address: "/foo"}
+	)
+	if _, keep, _ := f.Transform(msg); !keep {
+		t.Fatal("expected first message to be kept")
+	}
+	if _, keep, _ := f.Transform(msg); keep {
+		t.Fatal("expected duplicate to be dropped")
+	}
+}
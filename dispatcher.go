@@ -2,7 +2,9 @@ package osc
 
 import (
 	"fmt"
+	"regexp"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/pkg/errors"
@@ -30,58 +32,335 @@ type MessageHandler interface {
 type Dispatcher map[string]MessageHandler
 
 // Dispatch invokes an OSC bundle's messages.
+// It is equivalent to DispatchWith(b, MatchOptions{Exact: exactMatch}).
 func (d Dispatcher) Dispatch(b Bundle, exactMatch bool) error {
+	return d.DispatchWith(b, MatchOptions{Exact: exactMatch})
+}
+
+// DispatchWith invokes an OSC bundle's messages using the given match options.
+// The bundle's messages are invoked once its timetag has been reached.
+// Nested bundles with a later timetag are scheduled independently,
+// so they do not hold up the messages that are due right away.
+// Dispatch returns once every message in the bundle has been invoked.
+func (d Dispatcher) DispatchWith(b Bundle, opts MatchOptions) error {
 	var (
 		now = time.Now()
 		tt  = b.Timetag.Time()
 	)
 	if tt.Before(now) {
-		return d.immediately(b, exactMatch)
+		return d.immediately(b, opts)
 	}
 	<-time.After(tt.Sub(now))
-	return d.immediately(b, exactMatch)
+	return d.immediately(b, opts)
 }
 
 // immediately invokes an OSC bundle immediately.
-func (d Dispatcher) immediately(b Bundle, exactMatch bool) error {
+// Nested bundles whose timetag is in the future are dispatched concurrently.
+func (d Dispatcher) immediately(b Bundle, opts MatchOptions) error {
+	var (
+		errs = []string{}
+		mu   sync.Mutex
+		wg   sync.WaitGroup
+	)
+	addErr := func(err error) {
+		mu.Lock()
+		errs = append(errs, err.Error())
+		mu.Unlock()
+	}
 	for _, p := range b.Packets {
-		errs := []string{}
-		if err := d.invoke(p, exactMatch); err != nil {
-			errs = append(errs, err.Error())
+		if nested, ok := p.(Bundle); ok && nested.Timetag.Time().After(time.Now()) {
+			wg.Add(1)
+			go func(nested Bundle) {
+				defer wg.Done()
+				if err := d.DispatchWith(nested, opts); err != nil {
+					addErr(err)
+				}
+			}(nested)
+			continue
 		}
-		if len(errs) > 0 {
-			return errors.New(strings.Join(errs, " and "))
+		if msg, ok := p.(Message); ok {
+			// Messages remember the timetag they are dispatched with, see Message.Age.
+			msg.timetag = b.Timetag
+			p = msg
 		}
+		if err := d.invokeWith(p, opts); err != nil {
+			addErr(err)
+		}
+	}
+	wg.Wait()
+
+	if len(errs) > 0 {
+		return errors.New(strings.Join(errs, " and "))
 	}
 	return nil
 }
 
 // invoke invokes an OSC packet, which could be a message or a bundle of messages.
 func (d Dispatcher) invoke(p Packet, exactMatch bool) error {
+	return d.invokeWith(p, MatchOptions{Exact: exactMatch})
+}
+
+// invokeWith invokes an OSC packet using the given match options.
+func (d Dispatcher) invokeWith(p Packet, opts MatchOptions) error {
 	switch x := p.(type) {
 	case Message:
-		return d.Invoke(x, exactMatch)
+		return d.InvokeWith(x, opts)
 	case Bundle:
-		return d.immediately(x, exactMatch)
+		return d.immediately(x, opts)
 	default:
 		return errors.Errorf("unsupported type for dispatcher: %T", p)
 	}
 }
 
 // Invoke invokes an OSC message.
+// It is equivalent to InvokeWith(msg, MatchOptions{Exact: exactMatch}).
 func (d Dispatcher) Invoke(msg Message, exactMatch bool) error {
-	fmt.Printf("got message: %v\n", msg)
+	return d.InvokeWith(msg, MatchOptions{Exact: exactMatch})
+}
+
+// InvokeWith invokes an OSC message using the given match options.
+// Every handler that matches the message is invoked, even if some of them return an error.
+// The errors returned by the handlers are returned together.
+func (d Dispatcher) InvokeWith(msg Message, opts MatchOptions) error {
+	notifyWaiters(d, msg)
+
+	errs := []string{}
 	for address, handler := range d {
-		if address == "*" {
-			handler.Handle(msg)
+		var matched bool
+		if rh, ok := handler.(regexHandler); ok {
+			matched = rh.re.MatchString(msg.Address)
+		} else if address == "*" {
+			matched = true
+		} else {
+			var err error
+			if matched, err = msg.MatchWith(address, opts); err != nil {
+				return err
+			}
 		}
-		matched, err := msg.Match(address, exactMatch)
-		if err != nil {
-			return err
+		if !matched {
+			continue
+		}
+		if err := handler.Handle(msg); err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+	if len(errs) > 0 {
+		return errors.New(strings.Join(errs, " and "))
+	}
+	return nil
+}
+
+// OnTrigger adds a method at address that calls fn whenever a message matches it,
+// regardless of the message's arguments. fn is passed the method's address,
+// which is useful when the message was sent to a pattern.
+// This is a convenience for trigger-style messages that carry no meaningful arguments.
+// An error is returned if the address is invalid.
+func (d Dispatcher) OnTrigger(address string, fn func(addr string)) error {
+	if err := ValidateAddress(address); err != nil {
+		return err
+	}
+	d[address] = Method(func(msg Message) error {
+		fn(address)
+		return nil
+	})
+	return nil
+}
+
+// AddHandlerForPatterns adds the handler to the dispatcher at every one of the addresses.
+// If any of the addresses are invalid, nothing is added and the returned error lists all of them.
+func (d Dispatcher) AddHandlerForPatterns(addresses []string, handler MessageHandler) error {
+	invalid := []string{}
+	for _, addr := range addresses {
+		if err := ValidateAddress(addr); err != nil {
+			invalid = append(invalid, fmt.Sprintf("%q", addr))
+		}
+	}
+	if len(invalid) > 0 {
+		return errors.Wrapf(ErrInvalidAddress, "addresses %s", strings.Join(invalid, ", "))
+	}
+	for _, addr := range addresses {
+		d[addr] = handler
+	}
+	return nil
+}
+
+// Handlers is a handler that calls several handlers in order, see Dispatcher.HandleAll.
+type Handlers []MessageHandler
+
+// Handle calls every handler with the message in order.
+// All of the handlers are called even if some of them fail,
+// and their errors are returned together.
+func (hs Handlers) Handle(msg Message) error {
+	errs := []string{}
+	for _, h := range hs {
+		if err := h.Handle(msg); err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+	if len(errs) > 0 {
+		return errors.New(strings.Join(errs, " and "))
+	}
+	return nil
+}
+
+// HandleAll adds handlers to the dispatcher at address, after the handlers
+// that are already there. Messages that match address are handled by all of them
+// in the order they were added, see Handlers.
+// Note that assigning a handler to the dispatcher directly replaces all
+// the handlers at that address.
+// An error is returned if the address is invalid.
+func (d Dispatcher) HandleAll(address string, handlers ...MessageHandler) error {
+	if err := ValidateAddress(address); err != nil {
+		return err
+	}
+	var hs Handlers
+	switch existing := d[address].(type) {
+	case nil:
+	case Handlers:
+		hs = append(hs, existing...)
+	default:
+		hs = append(hs, existing)
+	}
+	d[address] = append(hs, handlers...)
+	return nil
+}
+
+// Remove removes all the handlers at address from the dispatcher
+// and returns how many handlers were removed, see HandleAll.
+// Handlers added with AddHandlerForRegex are removed by passing the regular expression's string.
+func (d Dispatcher) Remove(address string) int {
+	h, ok := d[address]
+	if !ok {
+		if h, ok = d[regexKeyPrefix+address]; !ok || !isRegexHandler(h) {
+			return 0
 		}
-		if matched {
-			handler.Handle(msg)
+		address = regexKeyPrefix + address
+	}
+	delete(d, address)
+	return handlerCount(h)
+}
+
+// Reset removes all the handlers from the dispatcher.
+// Like any other change to the dispatcher, it must not be called while
+// the dispatcher is being served.
+func (d Dispatcher) Reset() {
+	for address := range d {
+		delete(d, address)
+	}
+}
+
+// Clone returns a copy of the dispatcher with the same handlers,
+// which can be changed without changing the dispatcher.
+// The handlers themselves are not copied.
+func (d Dispatcher) Clone() Dispatcher {
+	clone := make(Dispatcher, len(d))
+	for address, h := range d {
+		if hs, ok := h.(Handlers); ok {
+			h = append(Handlers(nil), hs...)
 		}
+		clone[address] = h
+	}
+	return clone
+}
+
+// handlerCount returns the number of handlers h consists of.
+func handlerCount(h MessageHandler) int {
+	if hs, ok := h.(Handlers); ok {
+		return len(hs)
+	}
+	return 1
+}
+
+// regexHandler is a handler for the messages whose address matches a regular expression.
+type regexHandler struct {
+	MessageHandler
+	re *regexp.Regexp
+}
+
+// regexKeyPrefix starts the keys of regex handlers in a dispatcher.
+// Such keys are not OSC addresses since they do not start with '/'.
+const regexKeyPrefix = "regexp:"
+
+// isRegexHandler returns true if the handler was added with AddHandlerForRegex.
+func isRegexHandler(h MessageHandler) bool {
+	_, ok := h.(regexHandler)
+	return ok
+}
+
+// AddHandlerForRegex adds a handler for the messages whose address matches re.
+// This bypasses OSC address pattern matching: re is matched against the address
+// of incoming messages as-is, so wildcards in the address are not expanded,
+// and the match options of the dispatcher do not apply.
+// The handler is stored in the dispatcher at "regexp:" followed by re.String().
+// Since that is not an OSC address, regex handlers are left out
+// wherever the dispatcher's addresses are listed, e.g. by ListPatterns and Export.
+func (d Dispatcher) AddHandlerForRegex(re *regexp.Regexp, handler MessageHandler) {
+	d[regexKeyPrefix+re.String()] = regexHandler{MessageHandler: handler, re: re}
+}
+
+// DispatcherGroup registers methods in a Dispatcher under a common address prefix.
+type DispatcherGroup struct {
+	dispatcher Dispatcher
+	prefix     string
+	version    string
+}
+
+// Group returns a group that registers methods in the dispatcher
+// at addresses that start with prefix.
+func (d Dispatcher) Group(prefix string) *DispatcherGroup {
+	return &DispatcherGroup{dispatcher: d, prefix: prefix}
+}
+
+// WithVersion returns a group that registers every method both at its
+// address and at its address prefixed with the version.
+// This helps with migrating an API to versioned addresses, e.g.
+// a method added at "/foo" with WithVersion("v2") receives messages
+// sent to both "/foo" and "/v2/foo".
+func (d Dispatcher) WithVersion(version string) *DispatcherGroup {
+	return &DispatcherGroup{dispatcher: d, version: joinAddress("", version)}
+}
+
+// AddHandler adds a handler to the dispatcher at the group's prefix
+// followed by address.
+// An error is returned if the resulting address is invalid.
+func (g *DispatcherGroup) AddHandler(address string, handler MessageHandler) error {
+	addrs := []string{joinAddress(g.prefix, address)}
+	if g.version != "" {
+		addrs = append(addrs, joinAddress(g.version, addrs[0]))
+	}
+	for _, addr := range addrs {
+		if err := ValidateAddress(addr); err != nil {
+			return err
+		}
+	}
+	for _, addr := range addrs {
+		g.dispatcher[addr] = handler
 	}
 	return nil
 }
+
+// Group returns a nested group whose prefix is the group's prefix followed by subprefix.
+func (g *DispatcherGroup) Group(subprefix string) *DispatcherGroup {
+	return &DispatcherGroup{
+		dispatcher: g.dispatcher,
+		prefix:     joinAddress(g.prefix, subprefix),
+		version:    g.version,
+	}
+}
+
+// Prefix returns the group's address prefix.
+func (g *DispatcherGroup) Prefix() string {
+	return g.prefix
+}
+
+// joinAddress joins two parts of an OSC address with exactly one '/' between them.
+func joinAddress(prefix, address string) string {
+	mc := string(MessageChar)
+	if prefix == "" || prefix == mc {
+		return mc + strings.TrimPrefix(address, mc)
+	}
+	if address == "" {
+		return strings.TrimSuffix(prefix, mc)
+	}
+	return strings.TrimSuffix(prefix, mc) + mc + strings.TrimPrefix(address, mc)
+}
@@ -0,0 +1,87 @@
+package osc
+
+import (
+	"fmt"
+	"reflect"
+	"runtime"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Common errors.
+var (
+	ErrUnknownHandler = errors.New("unknown handler")
+)
+
+// HandlerConfig describes a handler registered in a dispatcher.
+type HandlerConfig struct {
+	Pattern   string `json:"pattern"`
+	HandlerID string `json:"handler_id"`
+}
+
+// namedHandler is a handler with a name that identifies it in a HandlerConfig.
+type namedHandler struct {
+	MessageHandler
+	name string
+}
+
+// Named returns a handler that is identified by name when the dispatcher is exported.
+func Named(name string, handler MessageHandler) MessageHandler {
+	return namedHandler{MessageHandler: handler, name: name}
+}
+
+// Export returns the handler registrations of the dispatcher, sorted by pattern.
+// Handlers created with Named are identified by their name. Other handlers are
+// identified by their function name if they are a Method, or by their type otherwise.
+// Handlers added with AddHandlerForRegex can not be imported, so they are not exported.
+func (d Dispatcher) Export() []HandlerConfig {
+	configs := make([]HandlerConfig, 0, len(d))
+	for pattern, handler := range d {
+		if isRegexHandler(handler) {
+			continue
+		}
+		configs = append(configs, HandlerConfig{Pattern: pattern, HandlerID: handlerID(handler)})
+	}
+	sort.Slice(configs, func(i, j int) bool {
+		return configs[i].Pattern < configs[j].Pattern
+	})
+	return configs
+}
+
+// Import adds the handlers described by configs to the dispatcher,
+// looking them up by their HandlerID in the registry.
+// The handlers are added with Named, so exporting the dispatcher returns the same configs.
+// Patterns must be OSC addresses that start with '/', or "*".
+// Nothing is added if any of the configs is invalid.
+func (d Dispatcher) Import(configs []HandlerConfig, registry map[string]MessageHandler) error {
+	for _, config := range configs {
+		if !strings.HasPrefix(config.Pattern, "/") && config.Pattern != "*" {
+			return errors.Wrapf(ErrInvalidAddress, "pattern %s", config.Pattern)
+		}
+		if err := ValidateAddress(config.Pattern); err != nil {
+			return errors.Wrapf(err, "pattern %s", config.Pattern)
+		}
+		if _, ok := registry[config.HandlerID]; !ok {
+			return errors.Wrapf(ErrUnknownHandler, "handler %s", config.HandlerID)
+		}
+	}
+	for _, config := range configs {
+		d[config.Pattern] = Named(config.HandlerID, registry[config.HandlerID])
+	}
+	return nil
+}
+
+// handlerID returns the ID of the handler used by Export.
+func handlerID(handler MessageHandler) string {
+	switch h := handler.(type) {
+	case namedHandler:
+		return h.name
+	case Method:
+		if fn := runtime.FuncForPC(reflect.ValueOf(h).Pointer()); fn != nil {
+			return fn.Name()
+		}
+	}
+	return fmt.Sprintf("%T", handler)
+}
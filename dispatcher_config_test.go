@@ -0,0 +1,78 @@
+package osc
+
+import (
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/pkg/errors"
+)
+
+type counter struct{ n int }
+
+func (c *counter) Handle(msg Message) error {
+	c.n++
+	return nil
+}
+
+func exportedMethod(msg Message) error { return nil }
+
+func TestDispatcherExport(t *testing.T) {
+	d := Dispatcher{
+		"/b": Method(exportedMethod),
+		"/a": Named("gain", Method(exportedMethod)),
+		"/c": &counter{},
+	}
+	d.AddHandlerForRegex(regexp.MustCompile(`^/d/[0-9]+$`), Method(exportedMethod))
+	configs := d.Export()
+	if expected, got := 3, len(configs); expected != got {
+		t.Fatalf("expected %d configs, got %d", expected, got)
+	}
+	if expected, got := (HandlerConfig{Pattern: "/a", HandlerID: "gain"}), configs[0]; expected != got {
+		t.Fatalf("expected %v, got %v", expected, got)
+	}
+	if expected, got := "exportedMethod", configs[1].HandlerID; !strings.HasSuffix(got, expected) {
+		t.Fatalf("expected %s to end with %s", got, expected)
+	}
+	if expected, got := "*osc.counter", configs[2].HandlerID; expected != got {
+		t.Fatalf("expected %s, got %s", expected, got)
+	}
+}
+
+func TestDispatcherImport(t *testing.T) {
+	var (
+		c        = &counter{}
+		registry = map[string]MessageHandler{"count": c}
+		configs  = []HandlerConfig{
+			{Pattern: "/synth/1/gate", HandlerID: "count"},
+			{Pattern: "/synth/2/gate", HandlerID: "count"},
+		}
+		d = Dispatcher{}
+	)
+	if err := d.Import(configs, registry); err != nil {
+		t.Fatal(err)
+	}
+	if expected, got := configs, d.Export(); !reflect.DeepEqual(expected, got) {
+		t.Fatalf("expected %v, got %v", expected, got)
+	}
+	if err := d.Invoke(Message{Address: "/synth/*/gate"}, false); err != nil {
+		t.Fatal(err)
+	}
+	if expected, got := 2, c.n; expected != got {
+		t.Fatalf("expected %d invocations, got %d", expected, got)
+	}
+	bad := Dispatcher{}
+	if err := bad.Import([]HandlerConfig{{Pattern: "/foo", HandlerID: "count"}, {Pattern: "/bar", HandlerID: "nope"}}, registry); errors.Cause(err) != ErrUnknownHandler {
+		t.Fatalf("expected ErrUnknownHandler, got %v", err)
+	}
+	if err := bad.Import([]HandlerConfig{{Pattern: "/foo*", HandlerID: "count"}}, registry); errors.Cause(err) != ErrInvalidAddress {
+		t.Fatalf("expected ErrInvalidAddress, got %v", err)
+	}
+	if err := bad.Import([]HandlerConfig{{Pattern: `^/foo/\d+$`, HandlerID: "count"}}, registry); errors.Cause(err) != ErrInvalidAddress {
+		t.Fatalf("expected ErrInvalidAddress, got %v", err)
+	}
+	if expected, got := 0, len(bad); expected != got {
+		t.Fatalf("expected %d handlers, got %d", expected, got)
+	}
+}
@@ -1,6 +1,8 @@
 package osc
 
 import (
+	"reflect"
+	"regexp"
 	"testing"
 	"time"
 
@@ -74,6 +76,90 @@ func TestDispatcherDispatchNestedBundle(t *testing.T) {
 	<-c
 }
 
+func TestDispatcherDispatchMixedBundle(t *testing.T) {
+	invoked := make(chan string, 2)
+	d := Dispatcher{
+		"/now": Method(func(msg Message) error {
+			invoked <- msg.Address
+			return nil
+		}),
+		"/later": Method(func(msg Message) error {
+			invoked <- msg.Address
+			return nil
+		}),
+	}
+	var (
+		start = time.Now()
+		later = start.Add(50 * time.Millisecond)
+	)
+	b := Bundle{
+		Timetag: Immediately,
+		Packets: []Packet{
+			Bundle{
+				Timetag: FromTime(later),
+				Packets: []Packet{Message{Address: "/later"}},
+			},
+			Message{Address: "/now"},
+		},
+	}
+	errChan := make(chan error)
+	go func() {
+		errChan <- d.Dispatch(b, false)
+	}()
+	if expected, got := "/now", <-invoked; expected != got {
+		t.Fatalf("expected %s to be invoked first, got %s", expected, got)
+	}
+	if elapsed := time.Since(start); elapsed >= 50*time.Millisecond {
+		t.Fatalf("expected /now to be invoked immediately, took %s", elapsed)
+	}
+	if expected, got := "/later", <-invoked; expected != got {
+		t.Fatalf("expected %s, got %s", expected, got)
+	}
+	if err := <-errChan; err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(start); elapsed < 50*time.Millisecond {
+		t.Fatalf("expected /later to be invoked after its timetag, took %s", elapsed)
+	}
+}
+
+func TestDispatcherMessageAge(t *testing.T) {
+	ages := map[string]time.Duration{}
+	d := Dispatcher{
+		"/past": Method(func(msg Message) error {
+			ages[msg.Address] = msg.Age()
+			return nil
+		}),
+		"/nested": Method(func(msg Message) error {
+			ages[msg.Address] = msg.Age()
+			return nil
+		}),
+	}
+	past := time.Now().Add(-time.Hour)
+	b := Bundle{
+		Timetag: FromTime(past),
+		Packets: []Packet{
+			Message{Address: "/past"},
+			Bundle{
+				Timetag: FromTime(past.Add(-time.Hour)),
+				Packets: []Packet{Message{Address: "/nested"}},
+			},
+		},
+	}
+	if err := d.Dispatch(b, false); err != nil {
+		t.Fatal(err)
+	}
+	if age := ages["/past"]; age < time.Hour || age > time.Hour+time.Minute {
+		t.Fatalf("expected an age of about an hour, got %s", age)
+	}
+	if age := ages["/nested"]; age < 2*time.Hour || age > 2*time.Hour+time.Minute {
+		t.Fatalf("expected an age of about two hours, got %s", age)
+	}
+	if expected, got := time.Duration(0), (Message{Address: "/past"}).Age(); expected != got {
+		t.Fatalf("expected age %s for a message outside of a bundle, got %s", expected, got)
+	}
+}
+
 func TestDispatcherMiss(t *testing.T) {
 	d := Dispatcher{
 		"/foo": Method(func(msg Message) error {
@@ -115,3 +201,310 @@ func TestDispatcherInvoke(t *testing.T) {
 		t.Fatal("expected error, got nil")
 	}
 }
+
+func TestDispatcherInvokeCaseInsensitive(t *testing.T) {
+	invoked := false
+	d := Dispatcher{
+		"/synth/freq": Method(func(msg Message) error {
+			invoked = true
+			return nil
+		}),
+	}
+	if err := d.InvokeWith(Message{Address: "/Synth/Freq"}, MatchOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if invoked {
+		t.Fatal("expected matching to be case-sensitive by default")
+	}
+	if err := d.InvokeWith(Message{Address: "/Synth/Freq"}, MatchOptions{CaseInsensitive: true}); err != nil {
+		t.Fatal(err)
+	}
+	if !invoked {
+		t.Fatal("expected handler to be invoked")
+	}
+}
+
+func TestDispatcherHandleAll(t *testing.T) {
+	var (
+		d     = Dispatcher{}
+		fired []int
+	)
+	handler := func(n int) MessageHandler {
+		return Method(func(msg Message) error {
+			fired = append(fired, n)
+			return nil
+		})
+	}
+	d["/x"] = handler(1)
+
+	if err := d.HandleAll("/x", handler(2), handler(3)); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.HandleAll("/x", handler(4)); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.Invoke(Message{Address: "/x"}, false); err != nil {
+		t.Fatal(err)
+	}
+	if expected, got := []int{1, 2, 3, 4}, fired; !reflect.DeepEqual(expected, got) {
+		t.Fatalf("expected %v, got %v", expected, got)
+	}
+	if err := d.HandleAll("/x/*", handler(5)); err != ErrInvalidAddress {
+		t.Fatalf("expected ErrInvalidAddress, got %v", err)
+	}
+}
+
+func TestDispatcherRemove(t *testing.T) {
+	var (
+		d     = Dispatcher{}
+		fired []string
+	)
+	handler := func(name string) MessageHandler {
+		return Method(func(msg Message) error {
+			fired = append(fired, name)
+			return nil
+		})
+	}
+	if err := d.HandleAll("/x", handler("x1"), handler("x2")); err != nil {
+		t.Fatal(err)
+	}
+	d["/y"] = handler("y")
+	d.AddHandlerForRegex(regexp.MustCompile(`^/z$`), handler("z"))
+
+	for _, testcase := range []struct {
+		Address  string
+		Expected int
+	}{
+		{Address: "/x", Expected: 2},
+		{Address: "/y", Expected: 1},
+		{Address: `^/z$`, Expected: 1},
+		{Address: "/x", Expected: 0},
+		{Address: "/nope", Expected: 0},
+	} {
+		if expected, got := testcase.Expected, d.Remove(testcase.Address); expected != got {
+			t.Fatalf("(%s) expected %d handlers removed, got %d", testcase.Address, expected, got)
+		}
+	}
+	for _, addr := range []string{"/x", "/y", "/z"} {
+		if err := d.Invoke(Message{Address: addr}, false); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if len(fired) != 0 {
+		t.Fatalf("expected removed handlers not to fire, got %v", fired)
+	}
+}
+
+func TestDispatcherResetClone(t *testing.T) {
+	var fired []string
+	handler := func(name string) MessageHandler {
+		return Method(func(msg Message) error {
+			fired = append(fired, name)
+			return nil
+		})
+	}
+	d := Dispatcher{"/x": handler("x")}
+	if err := d.HandleAll("/y", handler("y1"), handler("y2")); err != nil {
+		t.Fatal(err)
+	}
+	clone := d.Clone()
+
+	// Changing the clone does not change the dispatcher.
+	if err := clone.HandleAll("/y", handler("y3")); err != nil {
+		t.Fatal(err)
+	}
+	clone["/z"] = handler("z")
+
+	if expected, got := []HandlerInfo{{Pattern: "/x", Count: 1}, {Pattern: "/y", Count: 2}}, d.ListHandlers(); !reflect.DeepEqual(expected, got) {
+		t.Fatalf("expected %v, got %v", expected, got)
+	}
+	if err := clone.Invoke(Message{Address: "/y"}, true); err != nil {
+		t.Fatal(err)
+	}
+	if expected, got := []string{"y1", "y2", "y3"}, fired; !reflect.DeepEqual(expected, got) {
+		t.Fatalf("expected %v, got %v", expected, got)
+	}
+
+	d.Reset()
+	if expected, got := 0, len(d); expected != got {
+		t.Fatalf("expected %d handlers after reset, got %d", expected, got)
+	}
+	if expected, got := 3, len(clone); expected != got {
+		t.Fatalf("expected %d addresses in the clone, got %d", expected, got)
+	}
+}
+
+func TestHandlersError(t *testing.T) {
+	var calls int
+	hs := Handlers{
+		Method(func(msg Message) error { calls++; return errors.New("first") }),
+		Method(func(msg Message) error { calls++; return nil }),
+		Method(func(msg Message) error { calls++; return errors.New("third") }),
+	}
+	err := hs.Handle(Message{Address: "/x"})
+	if err == nil {
+		t.Fatal("expected error, got nil")
+	}
+	if expected, got := "first and third", err.Error(); expected != got {
+		t.Fatalf("expected %s, got %s", expected, got)
+	}
+	if expected, got := 3, calls; expected != got {
+		t.Fatalf("expected %d calls, got %d", expected, got)
+	}
+}
+
+func TestDispatcherOnTrigger(t *testing.T) {
+	var (
+		d         = Dispatcher{}
+		triggered []string
+	)
+	for _, addr := range []string{"/beat/1", "/beat/2"} {
+		if err := d.OnTrigger(addr, func(addr string) {
+			triggered = append(triggered, addr)
+		}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := d.Invoke(Message{Address: "/beat/1"}, false); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.Invoke(Message{Address: "/beat/2", Arguments: []Argument{Int(1)}}, false); err != nil {
+		t.Fatal(err)
+	}
+	if expected, got := []string{"/beat/1", "/beat/2"}, triggered; !reflect.DeepEqual(expected, got) {
+		t.Fatalf("expected %v, got %v", expected, got)
+	}
+	if err := d.OnTrigger("/beat/*", func(string) {}); err != ErrInvalidAddress {
+		t.Fatalf("expected ErrInvalidAddress, got %v", err)
+	}
+}
+
+func TestDispatcherGroup(t *testing.T) {
+	var (
+		d       = Dispatcher{}
+		handler = Method(func(msg Message) error { return nil })
+		synth   = d.Group("/synth")
+	)
+	if err := synth.AddHandler("/freq", handler); err != nil {
+		t.Fatal(err)
+	}
+	if err := synth.Group("1/").AddHandler("gain", handler); err != nil {
+		t.Fatal(err)
+	}
+	for _, addr := range []string{"/synth/freq", "/synth/1/gain"} {
+		if _, ok := d[addr]; !ok {
+			t.Fatalf("expected handler at %s", addr)
+		}
+	}
+	if expected, got := 2, len(d); expected != got {
+		t.Fatalf("expected %d handlers, got %d", expected, got)
+	}
+	if err := synth.AddHandler("/f*", handler); err != ErrInvalidAddress {
+		t.Fatalf("expected ErrInvalidAddress, got %v", err)
+	}
+}
+
+func TestDispatcherWithVersion(t *testing.T) {
+	var (
+		d       = Dispatcher{}
+		invoked = 0
+		handler = Method(func(msg Message) error {
+			invoked++
+			return nil
+		})
+	)
+	v2 := d.WithVersion("v2")
+	if err := v2.AddHandler("/foo", handler); err != nil {
+		t.Fatal(err)
+	}
+	if err := v2.Group("/synth").AddHandler("/freq", handler); err != nil {
+		t.Fatal(err)
+	}
+	for _, addr := range []string{"/foo", "/v2/foo", "/synth/freq", "/v2/synth/freq"} {
+		if _, ok := d[addr]; !ok {
+			t.Fatalf("expected handler at %s", addr)
+		}
+	}
+	for _, addr := range []string{"/foo", "/v2/foo"} {
+		if err := d.Invoke(Message{Address: addr}, true); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if expected, got := 2, invoked; expected != got {
+		t.Fatalf("expected %d invocations, got %d", expected, got)
+	}
+}
+
+func TestDispatcherAddHandlerForPatterns(t *testing.T) {
+	var (
+		d       = Dispatcher{}
+		invoked = 0
+		handler = Method(func(msg Message) error {
+			invoked++
+			return nil
+		})
+	)
+	if err := d.AddHandlerForPatterns([]string{"/foo", "/bar"}, handler); err != nil {
+		t.Fatal(err)
+	}
+	for _, addr := range []string{"/foo", "/bar", "/baz"} {
+		if err := d.Invoke(Message{Address: addr}, true); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if expected, got := 2, invoked; expected != got {
+		t.Fatalf("expected %d invocations, got %d", expected, got)
+	}
+	err := d.AddHandlerForPatterns([]string{"/qux", "/a*", "/b", "/c?"}, handler)
+	if errors.Cause(err) != ErrInvalidAddress {
+		t.Fatalf("expected ErrInvalidAddress, got %v", err)
+	}
+	if expected, got := `addresses "/a*", "/c?": invalid OSC address`, err.Error(); expected != got {
+		t.Fatalf("expected %s, got %s", expected, got)
+	}
+	if _, ok := d["/qux"]; ok {
+		t.Fatal("expected no handler to be added")
+	}
+}
+
+func TestDispatcherAddHandlerForRegex(t *testing.T) {
+	var (
+		d       = Dispatcher{}
+		invoked = []string{}
+	)
+	d.AddHandlerForRegex(regexp.MustCompile(`^/synth/[0-9]+/freq$`), Method(func(msg Message) error {
+		invoked = append(invoked, msg.Address)
+		return nil
+	}))
+	for _, addr := range []string{"/synth/1/freq", "/synth/12/freq", "/synth/a/freq", "/synth/*/freq"} {
+		if err := d.Invoke(Message{Address: addr}, false); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if expected, got := []string{"/synth/1/freq", "/synth/12/freq"}, invoked; !reflect.DeepEqual(expected, got) {
+		t.Fatalf("expected %v, got %v", expected, got)
+	}
+	d.AddHandlerForRegex(regexp.MustCompile(`^/mixer/.*$`), Method(func(msg Message) error { return nil }))
+	if err := checkDispatcher(d); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestJoinAddress(t *testing.T) {
+	for _, testcase := range []struct {
+		Prefix   string
+		Address  string
+		Expected string
+	}{
+		{Prefix: "", Address: "/foo", Expected: "/foo"},
+		{Prefix: "/", Address: "foo", Expected: "/foo"},
+		{Prefix: "/synth", Address: "/freq", Expected: "/synth/freq"},
+		{Prefix: "/synth/", Address: "/freq", Expected: "/synth/freq"},
+		{Prefix: "/synth", Address: "freq", Expected: "/synth/freq"},
+		{Prefix: "/synth/", Address: "", Expected: "/synth"},
+	} {
+		if expected, got := testcase.Expected, joinAddress(testcase.Prefix, testcase.Address); expected != got {
+			t.Fatalf("expected %s, got %s", expected, got)
+		}
+	}
+}
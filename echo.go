@@ -0,0 +1,18 @@
+package osc
+
+import (
+	"time"
+)
+
+// EnableEcho adds a method at address to the dispatcher that replies to the sender
+// of every message with the same address and arguments followed by the Timetag
+// at which the message was handled.
+// Replies are sent over conn, so clients can use it to measure the round trip time.
+func (d Dispatcher) EnableEcho(conn Conn, address string) {
+	d[address] = Method(func(msg Message) error {
+		args := make([]Argument, len(msg.Arguments), len(msg.Arguments)+1)
+		copy(args, msg.Arguments)
+		args = append(args, FromTime(time.Now()))
+		return conn.SendTo(msg.Sender, Message{Address: msg.Address, Arguments: args})
+	})
+}
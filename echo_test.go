@@ -0,0 +1,69 @@
+package osc
+
+import (
+	"net"
+	"testing"
+	"time"
+)
+
+func TestDispatcherEnableEcho(t *testing.T) {
+	laddr, err := net.ResolveUDPAddr("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	server, err := ListenUDP("udp", laddr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = server.Close() }() // Best effort.
+
+	d := Dispatcher{}
+	d.EnableEcho(server, "/echo")
+
+	go func() {
+		_ = server.Serve(1, d)
+	}()
+
+	raddr, err := net.ResolveUDPAddr("udp", server.LocalAddr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	client, err := DialUDP("udp", nil, raddr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = client.Close() }() // Best effort.
+
+	sent := time.Now()
+	if err := client.Send(Message{Address: "/echo", Arguments: []Argument{Int(1), String("ping")}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.SetReadDeadline(time.Now().Add(time.Second)); err != nil {
+		t.Fatal(err)
+	}
+	data := make([]byte, bufSize)
+	n, err := client.Read(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := ParseMessage(data[:n], nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected, got := "/echo", got.Address; expected != got {
+		t.Fatalf("expected %s, got %s", expected, got)
+	}
+	if expected, got := 3, len(got.Arguments); expected != got {
+		t.Fatalf("expected %d arguments, got %d", expected, got)
+	}
+	if !Int(1).Equal(got.Arguments[0]) || !String("ping").Equal(got.Arguments[1]) {
+		t.Fatalf("expected the original arguments, got %v", got.Arguments[:2])
+	}
+	tt, ok := got.Arguments[2].(Timetag)
+	if !ok {
+		t.Fatalf("expected a timetag, got %v", got.Arguments[2])
+	}
+	if received := tt.Time(); received.Before(sent.Add(-time.Second)) || received.After(time.Now().Add(time.Second)) {
+		t.Fatalf("expected a recent timetag, got %s", received)
+	}
+}
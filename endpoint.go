@@ -0,0 +1,66 @@
+package osc
+
+import (
+	"sort"
+
+	"github.com/pkg/errors"
+)
+
+// Endpoint describes a method registered in a dispatcher, e.g. for generating documentation.
+type Endpoint struct {
+	Pattern     string `json:"pattern"`
+	Typetags    string `json:"typetags,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+// typedHandler is a handler that declares the type tags of the messages it handles.
+type typedHandler struct {
+	MessageHandler
+	typetags    string
+	description string
+}
+
+// Typed returns a handler that declares the type tags of the messages it handles, e.g. ",ff",
+// along with a description of what it does.
+// Messages with other type tags are not passed to handler,
+// instead an error wrapping ErrInvalidTypeTag is returned.
+func Typed(typetags, description string, handler MessageHandler) MessageHandler {
+	return typedHandler{MessageHandler: handler, typetags: typetags, description: description}
+}
+
+// Handle handles the message if it has the declared type tags.
+func (th typedHandler) Handle(msg Message) error {
+	if tt := msg.TypetagString(); tt != th.typetags {
+		return errors.Wrapf(ErrInvalidTypeTag, "%s: expected %s, got %s", msg.Address, th.typetags, tt)
+	}
+	return th.MessageHandler.Handle(msg)
+}
+
+// Typetags returns the declared type tags.
+func (th typedHandler) Typetags() string {
+	return th.typetags
+}
+
+// Endpoints returns the methods registered in the dispatcher, sorted by pattern.
+// The type tags and descriptions are those declared with Typed.
+// Other handlers that implement TypedHandler also have their type tags returned.
+func (d Dispatcher) Endpoints() []Endpoint {
+	endpoints := make([]Endpoint, 0, len(d))
+	for pattern, handler := range d {
+		if isRegexHandler(handler) {
+			continue
+		}
+		e := Endpoint{Pattern: pattern}
+		if th, ok := handler.(TypedHandler); ok {
+			e.Typetags = th.Typetags()
+		}
+		if th, ok := handler.(typedHandler); ok {
+			e.Description = th.description
+		}
+		endpoints = append(endpoints, e)
+	}
+	sort.Slice(endpoints, func(i, j int) bool {
+		return endpoints[i].Pattern < endpoints[j].Pattern
+	})
+	return endpoints
+}
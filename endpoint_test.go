@@ -0,0 +1,46 @@
+package osc
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/pkg/errors"
+)
+
+func TestDispatcherEndpoints(t *testing.T) {
+	handler := Method(func(msg Message) error { return nil })
+	d := Dispatcher{
+		"/synth/freq": Typed(",f", "Sets the frequency in Hz.", handler),
+		"/synth/note": Typed(",ii", "Plays a note with a velocity.", handler),
+		"/synth/mute": typedMethod{Method: handler, typetags: ",T"},
+		"/reset":      handler,
+	}
+	expected := []Endpoint{
+		{Pattern: "/reset"},
+		{Pattern: "/synth/freq", Typetags: ",f", Description: "Sets the frequency in Hz."},
+		{Pattern: "/synth/mute", Typetags: ",T"},
+		{Pattern: "/synth/note", Typetags: ",ii", Description: "Plays a note with a velocity."},
+	}
+	if got := d.Endpoints(); !reflect.DeepEqual(expected, got) {
+		t.Fatalf("expected %v, got %v", expected, got)
+	}
+}
+
+func TestTyped(t *testing.T) {
+	var (
+		handled int
+		handler = Typed(",fi", "", Method(func(msg Message) error {
+			handled++
+			return nil
+		}))
+	)
+	if err := handler.Handle(Message{Address: "/foo", Arguments: []Argument{Float(1), Int(2)}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := handler.Handle(Message{Address: "/foo", Arguments: []Argument{Int(2), Float(1)}}); errors.Cause(err) != ErrInvalidTypeTag {
+		t.Fatalf("expected ErrInvalidTypeTag, got %v", err)
+	}
+	if expected, got := 1, handled; expected != got {
+		t.Fatalf("expected %d handled messages, got %d", expected, got)
+	}
+}
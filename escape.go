@@ -0,0 +1,57 @@
+package osc
+
+import (
+	"strings"
+)
+
+// addressSpecialChars are the characters that have a special meaning in address patterns.
+const addressSpecialChars = `\?*[]{},`
+
+// EscapeAddressSegment escapes the characters of s that have a special meaning
+// in address patterns with a backslash, so that a pattern containing the escaped
+// segment matches s literally, e.g. "a?b" is escaped to `a\?b`.
+// s must not contain '/', which separates the segments of an address.
+func EscapeAddressSegment(s string) string {
+	if !strings.ContainsAny(s, addressSpecialChars) {
+		return s
+	}
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if strings.IndexByte(addressSpecialChars, s[i]) >= 0 {
+			b.WriteByte('\\')
+		}
+		b.WriteByte(s[i])
+	}
+	return b.String()
+}
+
+// UnescapeAddressSegment reverses EscapeAddressSegment.
+// A backslash at the end of s is kept.
+func UnescapeAddressSegment(s string) string {
+	if strings.IndexByte(s, '\\') < 0 {
+		return s
+	}
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+1 < len(s) {
+			i++
+		}
+		b.WriteByte(s[i])
+	}
+	return b.String()
+}
+
+// JoinAddressSegments returns an address pattern made of the segments
+// that matches them literally, see EscapeAddressSegment.
+// For example JoinAddressSegments("mixer", "eq [low]") returns `/mixer/eq \[low\]`.
+func JoinAddressSegments(segments ...string) string {
+	var b strings.Builder
+	for _, segment := range segments {
+		b.WriteByte(MessageChar)
+		b.WriteString(EscapeAddressSegment(segment))
+	}
+	if b.Len() == 0 {
+		return string(MessageChar)
+	}
+	return b.String()
+}
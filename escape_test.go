@@ -0,0 +1,70 @@
+package osc
+
+import (
+	"testing"
+)
+
+func TestEscapeAddressSegment(t *testing.T) {
+	for _, testcase := range []struct {
+		Segment  string
+		Expected string
+	}{
+		{Segment: "", Expected: ""},
+		{Segment: "freq", Expected: "freq"},
+		{Segment: "a?b", Expected: `a\?b`},
+		{Segment: "*", Expected: `\*`},
+		{Segment: "eq [low]", Expected: `eq \[low\]`},
+		{Segment: "{a,b}", Expected: `\{a\,b\}`},
+		{Segment: `back\slash`, Expected: `back\\slash`},
+	} {
+		if expected, got := testcase.Expected, EscapeAddressSegment(testcase.Segment); expected != got {
+			t.Fatalf("expected %s, got %s", expected, got)
+		}
+		if expected, got := testcase.Segment, UnescapeAddressSegment(testcase.Expected); expected != got {
+			t.Fatalf("expected %s, got %s", expected, got)
+		}
+	}
+	if expected, got := `trailing\`, UnescapeAddressSegment(`trailing\`); expected != got {
+		t.Fatalf("expected %s, got %s", expected, got)
+	}
+}
+
+func TestJoinAddressSegments(t *testing.T) {
+	for _, testcase := range []struct {
+		Segments []string
+		Expected string
+	}{
+		{Segments: nil, Expected: "/"},
+		{Segments: []string{"mixer", "gain"}, Expected: "/mixer/gain"},
+		{Segments: []string{"mixer", "eq [low]"}, Expected: `/mixer/eq \[low\]`},
+	} {
+		if expected, got := testcase.Expected, JoinAddressSegments(testcase.Segments...); expected != got {
+			t.Fatalf("expected %s, got %s", expected, got)
+		}
+	}
+}
+
+func TestMessageMatchEscaped(t *testing.T) {
+	for _, testcase := range []struct {
+		Pattern  string
+		Address  string
+		Expected bool
+	}{
+		{Pattern: JoinAddressSegments("a?b"), Address: "/a?b", Expected: true},
+		{Pattern: JoinAddressSegments("a?b"), Address: "/axb", Expected: false},
+		{Pattern: "/a?b", Address: "/axb", Expected: true},
+		{Pattern: JoinAddressSegments("eq [low]", "*"), Address: "/eq [low]/*", Expected: true},
+		{Pattern: JoinAddressSegments("eq [low]", "*"), Address: "/eq [low]/gain", Expected: false},
+		{Pattern: `/eq \[low\]/*`, Address: "/eq [low]/gain", Expected: true},
+		{Pattern: JoinAddressSegments("{a,b}"), Address: "/a", Expected: false},
+		{Pattern: JoinAddressSegments("{a,b}"), Address: "/{a,b}", Expected: true},
+	} {
+		got, err := Message{Address: testcase.Pattern}.Match(testcase.Address, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if expected := testcase.Expected; expected != got {
+			t.Fatalf("(%s, %s) expected %t, got %t", testcase.Pattern, testcase.Address, expected, got)
+		}
+	}
+}
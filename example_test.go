@@ -0,0 +1,73 @@
+package osc
+
+import (
+	"fmt"
+	"log"
+)
+
+func ExampleMessage() {
+	msg := Message{
+		Address:   "/synth/freq",
+		Arguments: []Argument{Int(1), Float(440)},
+	}
+	fmt.Printf("%q\n", msg.Bytes())
+	// Output:
+	// "/synth/freq\x00,if\x00\x00\x00\x00\x01C\xdc\x00\x00"
+}
+
+func ExampleParseMessage() {
+	data := Message{
+		Address:   "/synth/freq",
+		Arguments: []Argument{Int(1), Float(440)},
+	}.Bytes()
+
+	msg, err := ParseMessage(data, nil)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(msg.Address)
+	for _, arg := range msg.Arguments {
+		fmt.Println(arg)
+	}
+	// Output:
+	// /synth/freq
+	// Int(1)
+	// Float(440.000000)
+}
+
+func ExampleMessage_Match() {
+	pattern := Message{Address: "/synth/*/freq"}
+
+	for _, address := range []string{"/synth/1/freq", "/synth/1/gain"} {
+		matched, err := pattern.Match(address, false)
+		if err != nil {
+			log.Fatal(err)
+		}
+		fmt.Println(address, matched)
+	}
+	// Output:
+	// /synth/1/freq true
+	// /synth/1/gain false
+}
+
+func ExampleGetRegex() {
+	exp, err := GetRegex("/synth/{1,2}/freq")
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(exp)
+	fmt.Println(exp.MatchString("/synth/2/freq"))
+	// Output:
+	// ^/synth/(1|2)/freq$
+	// true
+}
+
+func ExampleReadArgument() {
+	arg, consumed, err := ReadArgument(TypetagString, []byte{'f', 'o', 'o', 0})
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(arg, consumed)
+	// Output:
+	// foo 4
+}
@@ -0,0 +1,171 @@
+package osc
+
+import (
+	"bytes"
+	"io"
+	"math"
+
+	"github.com/pkg/errors"
+)
+
+// FixedMessage limits.
+const (
+	FixedAddressLen = 64
+	FixedMaxArgs    = 4
+)
+
+// Common errors.
+var (
+	ErrAddressTooLong   = errors.New("address is too long")
+	ErrTooManyArguments = errors.New("too many arguments")
+)
+
+// FixedMessage is an OSC message with a short address and a few fixed-size arguments
+// that can live on the stack. Encoding and decoding a FixedMessage does not allocate,
+// which makes it suitable for real-time threads.
+// Only int, float, true and false arguments are supported.
+// Each argument is stored in its encoded form in the first bytes of its slot in Args.
+type FixedMessage struct {
+	Address  [FixedAddressLen]byte
+	AddrLen  int
+	Args     [FixedMaxArgs][8]byte
+	ArgTypes [FixedMaxArgs]byte
+	ArgCount int
+}
+
+// SetAddress sets the message's address.
+func (fm *FixedMessage) SetAddress(address string) error {
+	if len(address) > FixedAddressLen {
+		return errors.Wrapf(ErrAddressTooLong, "%d bytes", len(address))
+	}
+	fm.AddrLen = copy(fm.Address[:], address)
+	return nil
+}
+
+// AddInt adds an int argument to the message.
+func (fm *FixedMessage) AddInt(i int32) error {
+	return fm.add(TypetagInt, uint32(i))
+}
+
+// AddFloat adds a float argument to the message.
+func (fm *FixedMessage) AddFloat(f float32) error {
+	return fm.add(TypetagFloat, math.Float32bits(f))
+}
+
+// AddBool adds a boolean argument to the message.
+func (fm *FixedMessage) AddBool(b bool) error {
+	if b {
+		return fm.add(TypetagTrue, 0)
+	}
+	return fm.add(TypetagFalse, 0)
+}
+
+// add adds an argument to the message.
+func (fm *FixedMessage) add(tt byte, v uint32) error {
+	if fm.ArgCount == FixedMaxArgs {
+		return ErrTooManyArguments
+	}
+	fm.ArgTypes[fm.ArgCount] = tt
+	byteOrder.PutUint32(fm.Args[fm.ArgCount][:], v)
+	fm.ArgCount++
+	return nil
+}
+
+// Encode writes the message to dst and returns the number of bytes written.
+// It returns io.ErrShortBuffer without writing anything if the message does not fit.
+func (fm *FixedMessage) Encode(dst []byte) (int, error) {
+	if fm.AddrLen < 0 || fm.AddrLen > FixedAddressLen {
+		return 0, errors.Wrapf(ErrAddressTooLong, "%d bytes", fm.AddrLen)
+	}
+	if fm.ArgCount < 0 || fm.ArgCount > FixedMaxArgs {
+		return 0, errors.Wrapf(ErrTooManyArguments, "%d arguments", fm.ArgCount)
+	}
+	size := paddedLen(fm.AddrLen+1) + paddedLen(fm.ArgCount+2)
+	for _, tt := range fm.ArgTypes[:fm.ArgCount] {
+		switch tt {
+		case TypetagInt, TypetagFloat:
+			size += 4
+		case TypetagTrue, TypetagFalse:
+		default:
+			return 0, errors.Wrapf(ErrInvalidTypeTag, "typetag %q", string(tt))
+		}
+	}
+	if size > len(dst) {
+		return 0, io.ErrShortBuffer
+	}
+	n := copy(dst, fm.Address[:fm.AddrLen])
+	n = putPadding(dst, n, paddedLen(fm.AddrLen+1))
+
+	tt := dst[n:]
+	tt[0] = TypetagPrefix
+	copy(tt[1:], fm.ArgTypes[:fm.ArgCount])
+	n += putPadding(tt, fm.ArgCount+1, paddedLen(fm.ArgCount+2))
+
+	for i, tt := range fm.ArgTypes[:fm.ArgCount] {
+		if tt == TypetagInt || tt == TypetagFloat {
+			n += copy(dst[n:], fm.Args[i][:4])
+		}
+	}
+	return n, nil
+}
+
+// Decode parses an OSC message from src into the fixed message.
+func (fm *FixedMessage) Decode(src []byte) error {
+	addrLen := bytes.IndexByte(src, 0)
+	if addrLen < 0 {
+		return errors.Wrap(ErrParse, "address is not null-terminated")
+	}
+	if addrLen > FixedAddressLen {
+		return errors.Wrapf(ErrAddressTooLong, "%d bytes", addrLen)
+	}
+	if len(src) < paddedLen(addrLen+1) {
+		return errors.Wrap(ErrParse, "address is not padded")
+	}
+	rest := src[paddedLen(addrLen+1):]
+
+	ttLen := bytes.IndexByte(rest, 0)
+	if ttLen < 1 || rest[0] != TypetagPrefix {
+		return errors.Wrap(ErrParse, "missing type tags")
+	}
+	if ttLen-1 > FixedMaxArgs {
+		return errors.Wrapf(ErrTooManyArguments, "%d arguments", ttLen-1)
+	}
+	if len(rest) < paddedLen(ttLen+1) {
+		return errors.Wrap(ErrParse, "type tags are not padded")
+	}
+	var (
+		typetags = rest[1:ttLen]
+		data     = rest[paddedLen(ttLen+1):]
+	)
+	fm.AddrLen = copy(fm.Address[:], src[:addrLen])
+	fm.ArgCount = 0
+
+	for _, tt := range typetags {
+		switch tt {
+		case TypetagInt, TypetagFloat:
+			if len(data) < 4 {
+				return errors.Wrapf(ErrParse, "argument %d: %s", fm.ArgCount, io.ErrUnexpectedEOF)
+			}
+			fm.ArgTypes[fm.ArgCount] = tt
+			copy(fm.Args[fm.ArgCount][:], data[:4])
+			data = data[4:]
+		case TypetagTrue, TypetagFalse:
+			fm.ArgTypes[fm.ArgCount] = tt
+		default:
+			return errors.Wrapf(ErrInvalidTypeTag, "typetag %q", string(tt))
+		}
+		fm.ArgCount++
+	}
+	return nil
+}
+
+// Message converts the fixed message to a Message.
+// Unlike Encode and Decode, this allocates.
+func (fm *FixedMessage) Message() (Message, error) {
+	buf := make([]byte, FixedAddressLen+4+paddedLen(FixedMaxArgs+2)+4*FixedMaxArgs)
+	n, err := fm.Encode(buf)
+	if err != nil {
+		return Message{}, err
+	}
+	return ParseMessage(buf[:n], nil)
+}
@@ -0,0 +1,103 @@
+package osc
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/pkg/errors"
+)
+
+func TestFixedMessageEncode(t *testing.T) {
+	var fm FixedMessage
+	if err := fm.SetAddress("/synth/1/freq"); err != nil {
+		t.Fatal(err)
+	}
+	if err := fm.AddFloat(440); err != nil {
+		t.Fatal(err)
+	}
+	if err := fm.AddInt(-3); err != nil {
+		t.Fatal(err)
+	}
+	if err := fm.AddBool(true); err != nil {
+		t.Fatal(err)
+	}
+	expected := Message{
+		Address:   "/synth/1/freq",
+		Arguments: []Argument{Float(440), Int(-3), Bool(true)},
+	}.Bytes()
+
+	buf := bytes.Repeat([]byte{0xff}, 64)
+	n, err := fm.Encode(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := buf[:n]; !bytes.Equal(expected, got) {
+		t.Fatalf("expected %q, got %q", expected, got)
+	}
+	if _, err := fm.Encode(buf[:n-1]); err != io.ErrShortBuffer {
+		t.Fatalf("expected io.ErrShortBuffer, got %v", err)
+	}
+	if allocs := testing.AllocsPerRun(10, func() { _, _ = fm.Encode(buf) }); allocs != 0 {
+		t.Fatalf("expected no allocations, got %f", allocs)
+	}
+}
+
+func TestFixedMessageDecode(t *testing.T) {
+	expected := Message{
+		Address:   "/foo",
+		Arguments: []Argument{Int(7), Bool(false), Float(0.5)},
+	}
+	data := expected.Bytes()
+
+	var fm FixedMessage
+	if err := fm.Decode(data); err != nil {
+		t.Fatal(err)
+	}
+	got, err := fm.Message()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !expected.Equal(got) {
+		t.Fatalf("expected %v, got %v", expected, got)
+	}
+	if allocs := testing.AllocsPerRun(10, func() { _ = fm.Decode(data) }); allocs != 0 {
+		t.Fatalf("expected no allocations, got %f", allocs)
+	}
+}
+
+func TestFixedMessageLimits(t *testing.T) {
+	var fm FixedMessage
+	if err := fm.SetAddress("/" + string(bytes.Repeat([]byte{'a'}, FixedAddressLen))); errors.Cause(err) != ErrAddressTooLong {
+		t.Fatalf("expected ErrAddressTooLong, got %v", err)
+	}
+	for i := 0; i < FixedMaxArgs; i++ {
+		if err := fm.AddInt(int32(i)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := fm.AddInt(4); err != ErrTooManyArguments {
+		t.Fatalf("expected ErrTooManyArguments, got %v", err)
+	}
+	for _, testcase := range []struct {
+		Msg      Message
+		Expected error
+	}{
+		{Msg: Message{Address: "/" + string(bytes.Repeat([]byte{'a'}, FixedAddressLen))}, Expected: ErrAddressTooLong},
+		{Msg: Message{Address: "/foo", Arguments: []Argument{Int(1), Int(2), Int(3), Int(4), Int(5)}}, Expected: ErrTooManyArguments},
+		{Msg: Message{Address: "/foo", Arguments: []Argument{String("bar")}}, Expected: ErrInvalidTypeTag},
+	} {
+		if err := fm.Decode(testcase.Msg.Bytes()); errors.Cause(err) != testcase.Expected {
+			t.Fatalf("(%v) expected %v, got %v", testcase.Msg, testcase.Expected, err)
+		}
+	}
+	for _, data := range [][]byte{
+		[]byte("/foo"),
+		[]byte("/foo\x00\x00\x00\x00"),
+		[]byte("/foo\x00\x00\x00\x00,i\x00\x00"),
+	} {
+		if err := fm.Decode(data); errors.Cause(err) != ErrParse {
+			t.Fatalf("(%q) expected ErrParse, got %v", data, err)
+		}
+	}
+}
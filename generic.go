@@ -0,0 +1,70 @@
+//go:build go1.18
+// +build go1.18
+
+package osc
+
+import (
+	"github.com/pkg/errors"
+)
+
+// ArgType is the set of Go types that have a corresponding OSC argument type.
+type ArgType interface {
+	int32 | float32 | bool | string | []byte
+}
+
+// Arg returns the OSC argument for a Go value,
+// i.e. an Int, Float, Bool, String or Blob.
+func Arg[T ArgType](v T) Argument {
+	switch x := any(v).(type) {
+	case int32:
+		return Int(x)
+	case float32:
+		return Float(x)
+	case bool:
+		return Bool(x)
+	case string:
+		return String(x)
+	case []byte:
+		return Blob(x)
+	}
+	panic("unreachable")
+}
+
+// DecodeArg reads a Go value from an OSC argument.
+// It returns ErrInvalidTypeTag if the argument does not hold a T.
+func DecodeArg[T ArgType](a Argument) (T, error) {
+	var (
+		v   T
+		err error
+	)
+	switch p := any(&v).(type) {
+	case *int32:
+		*p, err = a.ReadInt32()
+	case *float32:
+		*p, err = a.ReadFloat32()
+	case *bool:
+		*p, err = a.ReadBool()
+	case *string:
+		*p, err = a.ReadString()
+	case *[]byte:
+		*p, err = a.ReadBlob()
+	}
+	return v, err
+}
+
+// ArgAt reads the argument at index i of the message as a T.
+// It returns an error wrapping ErrIndexOutOfBounds if there is no such argument,
+// and one wrapping ErrInvalidTypeTag if the argument does not hold a T.
+func ArgAt[T ArgType](msg Message, i int) (T, error) {
+	var zero T
+
+	a, err := msg.argAt(i)
+	if err != nil {
+		return zero, err
+	}
+	v, err := DecodeArg[T](a)
+	if err != nil {
+		return zero, errors.Wrapf(err, "argument %d", i)
+	}
+	return v, nil
+}
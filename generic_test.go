@@ -0,0 +1,94 @@
+//go:build go1.18
+// +build go1.18
+
+package osc
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/pkg/errors"
+)
+
+func TestArg(t *testing.T) {
+	for _, testcase := range []struct {
+		Arg      Argument
+		Expected Argument
+	}{
+		{Arg: Arg(int32(1)), Expected: Int(1)},
+		{Arg: Arg(float32(1.5)), Expected: Float(1.5)},
+		{Arg: Arg(true), Expected: Bool(true)},
+		{Arg: Arg("foo"), Expected: String("foo")},
+		{Arg: Arg([]byte{1, 2}), Expected: Blob{1, 2}},
+	} {
+		if !testcase.Expected.Equal(testcase.Arg) {
+			t.Fatalf("expected %s, got %s", testcase.Expected, testcase.Arg)
+		}
+	}
+}
+
+func TestDecodeArg(t *testing.T) {
+	i, err := DecodeArg[int32](Int(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected, got := int32(1), i; expected != got {
+		t.Fatalf("expected %d, got %d", expected, got)
+	}
+	f, err := DecodeArg[float32](Float(1.5))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected, got := float32(1.5), f; expected != got {
+		t.Fatalf("expected %f, got %f", expected, got)
+	}
+	b, err := DecodeArg[bool](Bool(true))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected, got := true, b; expected != got {
+		t.Fatalf("expected %t, got %t", expected, got)
+	}
+	s, err := DecodeArg[string](String("foo"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected, got := "foo", s; expected != got {
+		t.Fatalf("expected %s, got %s", expected, got)
+	}
+	blob, err := DecodeArg[[]byte](Blob{1, 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected, got := []byte{1, 2}, blob; !bytes.Equal(expected, got) {
+		t.Fatalf("expected %v, got %v", expected, got)
+	}
+	if _, err := DecodeArg[string](Int(1)); errors.Cause(err) != ErrInvalidTypeTag {
+		t.Fatalf("expected ErrInvalidTypeTag, got %v", err)
+	}
+}
+
+func TestArgAt(t *testing.T) {
+	msg := Message{Address: "/note", Arguments: []Argument{Int(60), String("piano")}}
+
+	note, err := ArgAt[int32](msg, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected, got := int32(60), note; expected != got {
+		t.Fatalf("expected %d, got %d", expected, got)
+	}
+	instrument, err := ArgAt[string](msg, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected, got := "piano", instrument; expected != got {
+		t.Fatalf("expected %s, got %s", expected, got)
+	}
+	if _, err := ArgAt[string](msg, 0); errors.Cause(err) != ErrInvalidTypeTag {
+		t.Fatalf("expected ErrInvalidTypeTag, got %v", err)
+	}
+	if _, err := ArgAt[int32](msg, 2); errors.Cause(err) != ErrIndexOutOfBounds {
+		t.Fatalf("expected ErrIndexOutOfBounds, got %v", err)
+	}
+}
@@ -0,0 +1,149 @@
+package osc
+
+import (
+	"net"
+	"sync"
+	"time"
+)
+
+// HandlerContext gives handlers access to state that persists across
+// the messages received from the same sender, e.g. for per-controller smoothing.
+// See SenderState and Message.HandlerContext.
+type HandlerContext struct {
+	sender net.Addr
+	store  *Store
+}
+
+// Sender returns the address of the sender of the message.
+func (hc *HandlerContext) Sender() net.Addr {
+	return hc.sender
+}
+
+// Store returns the state of the sender of the message.
+func (hc *HandlerContext) Store() *Store {
+	return hc.store
+}
+
+// Store is a map that is safe for concurrent use.
+type Store struct {
+	mu     sync.Mutex
+	values map[string]interface{}
+}
+
+// Get returns the value stored for key and true, or nil and false if there is none.
+func (s *Store) Get(key string) (interface{}, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	v, ok := s.values[key]
+	return v, ok
+}
+
+// Set stores a value for key.
+func (s *Store) Set(key string, value interface{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.values == nil {
+		s.values = map[string]interface{}{}
+	}
+	s.values[key] = value
+}
+
+// Update stores the value returned by fn for key.
+// fn is called with the current value and false if there is none.
+// The store is locked while fn runs, so fn must not use the store.
+func (s *Store) Update(key string, fn func(value interface{}, ok bool) interface{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	v, ok := s.values[key]
+	if s.values == nil {
+		s.values = map[string]interface{}{}
+	}
+	s.values[key] = fn(v, ok)
+}
+
+// Delete removes the value stored for key.
+func (s *Store) Delete(key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.values, key)
+}
+
+// SenderState keeps a HandlerContext for every sender.
+// The state of a sender is forgotten when nothing was received from it for the idle timeout.
+// It is safe for concurrent use.
+type SenderState struct {
+	idleTimeout time.Duration
+	now         func() time.Time
+
+	mu      sync.Mutex
+	senders map[string]*senderEntry
+}
+
+// senderEntry is the state of a sender.
+type senderEntry struct {
+	ctx      *HandlerContext
+	lastSeen time.Time
+}
+
+// NewSenderState creates a sender state that forgets senders after idleTimeout.
+// Zero means senders are never forgotten.
+func NewSenderState(idleTimeout time.Duration) *SenderState {
+	return &SenderState{
+		idleTimeout: idleTimeout,
+		now:         time.Now,
+		senders:     map[string]*senderEntry{},
+	}
+}
+
+// Context returns the handler context of a sender.
+func (ss *SenderState) Context(sender net.Addr) *HandlerContext {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+
+	now := ss.now()
+	ss.expire(now)
+
+	key := senderKey(sender)
+	entry, ok := ss.senders[key]
+	if !ok {
+		entry = &senderEntry{ctx: &HandlerContext{sender: sender, store: &Store{}}}
+		ss.senders[key] = entry
+	}
+	entry.lastSeen = now
+	return entry.ctx
+}
+
+// Len returns the number of senders whose state is kept.
+func (ss *SenderState) Len() int {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+	ss.expire(ss.now())
+	return len(ss.senders)
+}
+
+// Transform is a Transformer that attaches the handler context of
+// the message's sender to the message, see Message.HandlerContext.
+func (ss *SenderState) Transform(msg Message) (Message, bool, error) {
+	msg.handlerContext = ss.Context(msg.Sender)
+	return msg, true, nil
+}
+
+// expire forgets the senders that were idle for longer than the idle timeout.
+func (ss *SenderState) expire(now time.Time) {
+	if ss.idleTimeout <= 0 {
+		return
+	}
+	for key, entry := range ss.senders {
+		if now.Sub(entry.lastSeen) >= ss.idleTimeout {
+			delete(ss.senders, key)
+		}
+	}
+}
+
+// senderKey returns the key a sender's state is stored under.
+func senderKey(sender net.Addr) string {
+	if sender == nil {
+		return ""
+	}
+	return sender.Network() + ":" + sender.String()
+}
@@ -0,0 +1,90 @@
+package osc
+
+import (
+	"net"
+	"testing"
+	"time"
+)
+
+func TestSenderState(t *testing.T) {
+	var (
+		now     = time.Unix(0, 0)
+		ss      = NewSenderState(time.Minute)
+		sender1 = &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1), Port: 9000}
+		sender2 = &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1), Port: 9001}
+	)
+	ss.now = func() time.Time { return now }
+
+	ss.Context(sender1).Store().Set("last", 1)
+
+	// State persists across messages from the same sender.
+	hc := ss.Context(sender1)
+	if expected, got := sender1, hc.Sender(); expected != got {
+		t.Fatalf("expected sender %s, got %s", expected, got)
+	}
+	if v, ok := hc.Store().Get("last"); !ok || v != 1 {
+		t.Fatalf("expected 1, got %v (%t)", v, ok)
+	}
+
+	// State is isolated between senders.
+	if v, ok := ss.Context(sender2).Store().Get("last"); ok {
+		t.Fatalf("expected no value for sender 2, got %v", v)
+	}
+	if expected, got := 2, ss.Len(); expected != got {
+		t.Fatalf("expected %d senders, got %d", expected, got)
+	}
+
+	// Idle senders are forgotten.
+	now = now.Add(30 * time.Second)
+	_ = ss.Context(sender2)
+	now = now.Add(30 * time.Second)
+	if expected, got := 1, ss.Len(); expected != got {
+		t.Fatalf("expected %d senders, got %d", expected, got)
+	}
+	if v, ok := ss.Context(sender1).Store().Get("last"); ok {
+		t.Fatalf("expected state of sender 1 to expire, got %v", v)
+	}
+}
+
+func TestSenderStateTransform(t *testing.T) {
+	ss := NewSenderState(0)
+	sender := &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1), Port: 9000}
+
+	if hc := (Message{}).HandlerContext(); hc != nil {
+		t.Fatalf("expected nil handler context, got %v", hc)
+	}
+	msg, keep, err := ss.Transform(Message{Address: "/foo", Sender: sender})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !keep {
+		t.Fatal("expected message to be kept")
+	}
+	if expected, got := ss.Context(sender), msg.HandlerContext(); expected != got {
+		t.Fatalf("expected handler context %v, got %v", expected, got)
+	}
+}
+
+func TestStore(t *testing.T) {
+	var s Store
+
+	if _, ok := s.Get("foo"); ok {
+		t.Fatal("expected no value")
+	}
+	s.Update("foo", func(v interface{}, ok bool) interface{} {
+		if ok {
+			t.Fatalf("expected no value, got %v", v)
+		}
+		return 1
+	})
+	s.Update("foo", func(v interface{}, ok bool) interface{} {
+		return v.(int) + 1
+	})
+	if v, ok := s.Get("foo"); !ok || v != 2 {
+		t.Fatalf("expected 2, got %v (%t)", v, ok)
+	}
+	s.Delete("foo")
+	if _, ok := s.Get("foo"); ok {
+		t.Fatal("expected value to be deleted")
+	}
+}
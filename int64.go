@@ -0,0 +1,79 @@
+package osc
+
+import (
+	"fmt"
+	"io"
+	"math"
+
+	"github.com/pkg/errors"
+)
+
+// TypetagInt64 is the type tag of Int64 arguments.
+// It is an optional type of the OSC specification, so some receivers may reject it.
+const TypetagInt64 byte = 'h'
+
+// Int64 represents a 64-bit integer, e.g. a timestamp or a large counter.
+type Int64 int64
+
+// ReadInt64From reads a 64-bit integer from a byte slice.
+func ReadInt64From(data []byte) (Argument, int64, error) {
+	if len(data) < 8 {
+		return nil, 0, errors.Wrap(io.ErrUnexpectedEOF, "read int64 argument")
+	}
+	return Int64(byteOrder.Uint64(data)), 8, nil
+}
+
+// Bytes converts the arg to a byte slice suitable for adding to the binary representation of an OSC message.
+func (i Int64) Bytes() []byte {
+	b := make([]byte, 8)
+	byteOrder.PutUint64(b, uint64(i))
+	return b
+}
+
+// Equal returns true if the argument equals the other one, false otherwise.
+func (i Int64) Equal(other Argument) bool {
+	if other.Typetag() != TypetagInt64 {
+		return false
+	}
+	i2 := other.(Int64)
+	return i == i2
+}
+
+// ReadInt32 narrows the arg to a 32-bit integer.
+// Values outside of [math.MinInt32, math.MaxInt32] return an error wrapping ErrOutOfRange.
+func (i Int64) ReadInt32() (int32, error) {
+	if i < math.MinInt32 || i > math.MaxInt32 {
+		return 0, errors.Wrapf(ErrOutOfRange, "%d does not fit in an int32", i)
+	}
+	return int32(i), nil
+}
+
+// ReadInt64 reads a 64-bit integer from the arg.
+func (i Int64) ReadInt64() (int64, error) { return int64(i), nil }
+
+// ReadFloat32 reads a 32-bit float from the arg.
+func (i Int64) ReadFloat32() (float32, error) { return 0, ErrInvalidTypeTag }
+
+// ReadBool bool reads a boolean from the arg.
+func (i Int64) ReadBool() (bool, error) { return false, ErrInvalidTypeTag }
+
+// ReadString string reads a string from the arg.
+func (i Int64) ReadString() (string, error) { return "", ErrInvalidTypeTag }
+
+// ReadBlob reads a slice of bytes from the arg.
+func (i Int64) ReadBlob() ([]byte, error) { return nil, ErrInvalidTypeTag }
+
+// String converts the arg to a string.
+func (i Int64) String() string { return fmt.Sprintf("Int64(%d)", i) }
+
+// Typetag returns the argument's type tag.
+func (i Int64) Typetag() byte { return TypetagInt64 }
+
+// Size returns the number of bytes of the encoded arg.
+func (i Int64) Size() int { return 8 }
+
+// WriteTo writes the arg to an io.Writer in its 8-byte big-endian wire form.
+func (i Int64) WriteTo(w io.Writer) (int64, error) {
+	written, err := w.Write(i.Bytes())
+	return int64(written), err
+}
@@ -0,0 +1,79 @@
+package osc
+
+import (
+	"bytes"
+	"math"
+	"testing"
+
+	"github.com/pkg/errors"
+)
+
+func TestInt64(t *testing.T) {
+	msg := Message{Address: "/counter", Arguments: []Argument{Int64(math.MaxInt64), Int64(-2), Int(1)}}
+	data := msg.Bytes()
+
+	if expected, got := ",hhi", string(bytes.TrimRight(data[12:20], "\x00")); expected != got {
+		t.Fatalf("expected type tags %q, got %q", expected, got)
+	}
+	if expected, got := []byte{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xfe}, data[28:36]; !bytes.Equal(expected, got) {
+		t.Fatalf("expected %x, got %x", expected, got)
+	}
+	got, err := ParseMessage(data, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !msg.Equal(got) {
+		t.Fatalf("expected %v, got %v", msg, got)
+	}
+	buf := make([]byte, msg.EncodedLen())
+	if _, err := msg.MarshalTo(buf); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(data, buf) {
+		t.Fatalf("expected %x, got %x", data, buf)
+	}
+
+	i, err := got.Arguments[0].ReadInt64()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected, got := int64(math.MaxInt64), i; expected != got {
+		t.Fatalf("expected %d, got %d", expected, got)
+	}
+	if _, err := got.Arguments[2].ReadInt64(); err != ErrInvalidTypeTag {
+		t.Fatalf("expected ErrInvalidTypeTag, got %v", err)
+	}
+	if _, err := got.Arguments[0].ReadInt32(); errors.Cause(err) != ErrOutOfRange {
+		t.Fatalf("expected ErrOutOfRange, got %v", err)
+	}
+	if _, err := Int64(math.MinInt32 - 1).ReadInt32(); errors.Cause(err) != ErrOutOfRange {
+		t.Fatalf("expected ErrOutOfRange, got %v", err)
+	}
+	n, err := got.Arguments[1].ReadInt32()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected, got := int32(-2), n; expected != got {
+		t.Fatalf("expected %d, got %d", expected, got)
+	}
+	if _, _, err := ReadInt64From([]byte{1, 2, 3, 4}); err == nil {
+		t.Fatal("expected error, got nil")
+	}
+	if Int64(1).Equal(Int(1)) {
+		t.Fatal("expected Int64 and Int to differ")
+	}
+}
+
+func TestInt64WriteTo(t *testing.T) {
+	var buf bytes.Buffer
+	n, err := Int64(-2).WriteTo(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected, got := int64(8), n; expected != got {
+		t.Fatalf("expected %d bytes written, got %d", expected, got)
+	}
+	if expected, got := Int64(-2).Bytes(), buf.Bytes(); !bytes.Equal(expected, got) {
+		t.Fatalf("expected %x, got %x", expected, got)
+	}
+}
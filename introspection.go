@@ -0,0 +1,99 @@
+package osc
+
+import (
+	"sort"
+
+	"github.com/pkg/errors"
+)
+
+// Addresses used for introspection, see EnableIntrospection.
+const (
+	WhoHasAddress      = "/who-has"
+	WhoHasReplyAddress = "/who-has/reply"
+	WhoAmIAddress      = "/who-am-i"
+	WhoAmIReplyAddress = "/who-am-i/reply"
+)
+
+// HandlerInfo describes the handlers at an address of a dispatcher.
+type HandlerInfo struct {
+	Pattern string
+	Count   int
+}
+
+// ListPatterns returns the addresses of all the methods in the dispatcher in sorted order.
+// Handlers added with AddHandlerForRegex are not methods, so they are left out.
+func (d Dispatcher) ListPatterns() []string {
+	patterns := make([]string, 0, len(d))
+	for pattern, handler := range d {
+		if isRegexHandler(handler) {
+			continue
+		}
+		patterns = append(patterns, pattern)
+	}
+	sort.Strings(patterns)
+	return patterns
+}
+
+// ListHandlers returns the number of handlers at every address of the dispatcher
+// sorted by address, see HandleAll.
+func (d Dispatcher) ListHandlers() []HandlerInfo {
+	patterns := d.ListPatterns()
+	infos := make([]HandlerInfo, len(patterns))
+	for i, pattern := range patterns {
+		infos[i] = HandlerInfo{Pattern: pattern, Count: handlerCount(d[pattern])}
+	}
+	return infos
+}
+
+// HasHandler returns true if a message sent to address would be dispatched to a method
+// using the default match options.
+func (d Dispatcher) HasHandler(address string) bool {
+	msg := Message{Address: address}
+	for pattern, handler := range d {
+		if pattern == "*" {
+			return true
+		}
+		if rh, ok := handler.(regexHandler); ok {
+			if rh.re.MatchString(address) {
+				return true
+			}
+			continue
+		}
+		if matched, err := msg.MatchWith(pattern, MatchOptions{}); err == nil && matched {
+			return true
+		}
+	}
+	return false
+}
+
+// EnableIntrospection adds methods to the dispatcher that answer
+// the introspection queries used by some OSC implementations, e.g. liblo.
+// Replies are sent over conn to the sender of the query.
+//
+// A message to WhoHasAddress with an address as its only argument is answered
+// at WhoHasReplyAddress with the address and whether the dispatcher has a handler for it,
+// see HasHandler.
+// A message to WhoAmIAddress is answered at WhoAmIReplyAddress with serverName
+// followed by the addresses of all the methods in the dispatcher in sorted order.
+func (d Dispatcher) EnableIntrospection(conn Conn, serverName string) {
+	d[WhoHasAddress] = Method(func(msg Message) error {
+		if len(msg.Arguments) != 1 {
+			return errors.Errorf("expected 1 argument, got %d", len(msg.Arguments))
+		}
+		address, err := msg.Arguments[0].ReadString()
+		if err != nil {
+			return errors.Wrap(err, "read address")
+		}
+		return conn.SendTo(msg.Sender, Message{
+			Address:   WhoHasReplyAddress,
+			Arguments: []Argument{String(address), Bool(d.HasHandler(address))},
+		})
+	})
+	d[WhoAmIAddress] = Method(func(msg Message) error {
+		args := []Argument{String(serverName)}
+		for _, pattern := range d.ListPatterns() {
+			args = append(args, String(pattern))
+		}
+		return conn.SendTo(msg.Sender, Message{Address: WhoAmIReplyAddress, Arguments: args})
+	})
+}
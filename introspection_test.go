@@ -0,0 +1,133 @@
+package osc
+
+import (
+	"net"
+	"reflect"
+	"regexp"
+	"testing"
+	"time"
+)
+
+func TestDispatcherListHandlers(t *testing.T) {
+	d := Dispatcher{
+		"/foo/bar": Method(func(msg Message) error { return nil }),
+	}
+	if err := d.HandleAll("/baz", Method(func(msg Message) error { return nil }), Method(func(msg Message) error { return nil })); err != nil {
+		t.Fatal(err)
+	}
+	d.AddHandlerForRegex(regexp.MustCompile(`^/qux/.*$`), Method(func(msg Message) error { return nil }))
+	if expected, got := []string{"/baz", "/foo/bar"}, d.ListPatterns(); !reflect.DeepEqual(expected, got) {
+		t.Fatalf("expected %v, got %v", expected, got)
+	}
+	expected := []HandlerInfo{
+		{Pattern: "/baz", Count: 2},
+		{Pattern: "/foo/bar", Count: 1},
+	}
+	if got := d.ListHandlers(); !reflect.DeepEqual(expected, got) {
+		t.Fatalf("expected %v, got %v", expected, got)
+	}
+	if got := (Dispatcher{}).ListPatterns(); len(got) != 0 {
+		t.Fatalf("expected no patterns, got %v", got)
+	}
+}
+
+func TestDispatcherHasHandler(t *testing.T) {
+	d := Dispatcher{
+		"/foo/bar": Method(func(msg Message) error { return nil }),
+	}
+	d.AddHandlerForRegex(regexp.MustCompile(`^/baz/[0-9]+$`), Method(func(msg Message) error { return nil }))
+
+	for _, c := range []struct {
+		address  string
+		expected bool
+	}{
+		{address: "/foo/bar", expected: true},
+		{address: "/foo/*", expected: true},
+		{address: "/foo", expected: false},
+		{address: "/baz/1", expected: true},
+		{address: "/baz/x", expected: false},
+	} {
+		if expected, got := c.expected, d.HasHandler(c.address); expected != got {
+			t.Fatalf("(%s) expected %t, got %t", c.address, expected, got)
+		}
+	}
+	if !(Dispatcher{"*": Method(func(msg Message) error { return nil })}).HasHandler("/any") {
+		t.Fatal("expected the wildcard method to handle every address")
+	}
+}
+
+func TestDispatcherEnableIntrospection(t *testing.T) {
+	laddr, err := net.ResolveUDPAddr("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	server, err := ListenUDP("udp", laddr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = server.Close() }() // Best effort.
+
+	d := Dispatcher{
+		"/foo/bar": Method(func(msg Message) error { return nil }),
+	}
+	d.EnableIntrospection(server, "synth")
+
+	go func() {
+		_ = server.Serve(1, d)
+	}()
+
+	raddr, err := net.ResolveUDPAddr("udp", server.LocalAddr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	client, err := DialUDP("udp", nil, raddr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = client.Close() }() // Best effort.
+
+	for _, c := range []struct {
+		query    Message
+		expected Message
+	}{
+		{
+			query:    Message{Address: WhoHasAddress, Arguments: []Argument{String("/foo/bar")}},
+			expected: Message{Address: WhoHasReplyAddress, Arguments: []Argument{String("/foo/bar"), Bool(true)}},
+		},
+		{
+			query:    Message{Address: WhoHasAddress, Arguments: []Argument{String("/foo/baz")}},
+			expected: Message{Address: WhoHasReplyAddress, Arguments: []Argument{String("/foo/baz"), Bool(false)}},
+		},
+		{
+			query: Message{Address: WhoAmIAddress},
+			expected: Message{
+				Address: WhoAmIReplyAddress,
+				Arguments: []Argument{
+					String("synth"),
+					String("/foo/bar"),
+					String(WhoAmIAddress),
+					String(WhoHasAddress),
+				},
+			},
+		},
+	} {
+		if err := client.Send(c.query); err != nil {
+			t.Fatal(err)
+		}
+		if err := client.SetReadDeadline(time.Now().Add(time.Second)); err != nil {
+			t.Fatal(err)
+		}
+		data := make([]byte, bufSize)
+		n, err := client.Read(data)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := ParseMessage(data[:n], nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !c.expected.Equal(got) {
+			t.Fatalf("(%v) expected %v, got %v", c.query, c.expected, got)
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package osc
+
+import (
+	"encoding/json"
+)
+
+// StringAddr is a sender address that is only known by its string representation,
+// e.g. in environments without networking such as WebAssembly in a browser.
+type StringAddr string
+
+// Network returns "string".
+func (sa StringAddr) Network() string { return "string" }
+
+// String returns the address.
+func (sa StringAddr) String() string { return string(sa) }
+
+// messageJSON is the JSON representation of a parsed message.
+// Arguments are native Go values, see ArgumentToInterface,
+// except for blobs, which are base64 strings, see Blob.Base64.
+// Arguments of custom types are represented by their String method.
+type messageJSON struct {
+	Address   string        `json:"address,omitempty"`
+	Typetags  string        `json:"typetags,omitempty"`
+	Arguments []interface{} `json:"arguments,omitempty"`
+	Error     string        `json:"error,omitempty"`
+}
+
+// parseMessageJSON parses a message and returns its JSON representation.
+// If the message can not be parsed, the JSON object only has an error field.
+func parseMessageJSON(data []byte, sender StringAddr) []byte {
+	var mj messageJSON
+
+	msg, err := ParseMessage(data, sender)
+	if err != nil {
+		mj.Error = err.Error()
+	} else {
+		mj.Address = msg.Address
+		mj.Typetags = msg.TypetagString()
+		mj.Arguments = make([]interface{}, len(msg.Arguments))
+		for i, a := range msg.Arguments {
+			v := ArgumentToInterface(a)
+			switch x := a.(type) {
+			case Blob:
+				v = x.Base64()
+			default:
+				if _, ok := v.(Argument); ok {
+					v = a.String()
+				}
+			}
+			mj.Arguments[i] = v
+		}
+	}
+	out, err := json.Marshal(mj)
+	if err != nil {
+		// Floats that are NaN or infinite can not be marshaled.
+		out, _ = json.Marshal(messageJSON{Error: err.Error()}) // Never fails.
+	}
+	return out
+}
@@ -0,0 +1,44 @@
+package osc
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+)
+
+func TestParseMessageJSON(t *testing.T) {
+	msg := Message{
+		Address:   "/foo",
+		Arguments: []Argument{Int(1), Float(0.5), Bool(true), String("bar"), Blob{1, 2, 3}},
+	}
+	expected := `{"address":"/foo","typetags":",ifTsb","arguments":[1,0.5,true,"bar","AQID"]}`
+	if got := string(parseMessageJSON(msg.Bytes(), StringAddr("browser"))); expected != got {
+		t.Fatalf("expected %s, got %s", expected, got)
+	}
+	// Missing int argument.
+	var mj messageJSON
+	if err := json.Unmarshal(parseMessageJSON(append(ToBytes("/foo"), ToBytes(",i")...), StringAddr("")), &mj); err != nil {
+		t.Fatal(err)
+	}
+	if mj.Error == "" || mj.Address != "" {
+		t.Fatalf("expected only an error, got %+v", mj)
+	}
+	nan := Message{Address: "/foo", Arguments: []Argument{Float(math.NaN())}}
+	mj = messageJSON{}
+	if err := json.Unmarshal(parseMessageJSON(nan.Bytes(), StringAddr("")), &mj); err != nil {
+		t.Fatal(err)
+	}
+	if mj.Error == "" {
+		t.Fatalf("expected an error, got %+v", mj)
+	}
+}
+
+func TestStringAddr(t *testing.T) {
+	sa := StringAddr("127.0.0.1:8000")
+	if expected, got := "string", sa.Network(); expected != got {
+		t.Fatalf("expected %s, got %s", expected, got)
+	}
+	if expected, got := "127.0.0.1:8000", sa.String(); expected != got {
+		t.Fatalf("expected %s, got %s", expected, got)
+	}
+}
@@ -2,11 +2,14 @@ package osc
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"io"
+	"math"
 	"net"
 	"regexp"
 	"strings"
+	"time"
 
 	"github.com/pkg/errors"
 )
@@ -17,6 +20,17 @@ var (
 	ErrInvalidTypeTag   = errors.New("invalid type tag")
 	ErrNilWriter        = errors.New("writer must not be nil")
 	ErrParse            = errors.New("error parsing message")
+	ErrPrefixMismatch   = errors.New("address does not start with prefix")
+	ErrStringTooLarge   = errors.New("string argument too large")
+	ErrBlobTooLarge     = errors.New("blob argument too large")
+	ErrPacketTooLarge   = errors.New("packet too large")
+)
+
+// Default limits, see ParseOptions and Decoder.
+const (
+	DefaultMaxStringLen  = 4096
+	DefaultMaxArguments  = 256
+	DefaultMaxPacketSize = bufSize
 )
 
 // Message is an OSC message.
@@ -25,27 +39,216 @@ type Message struct {
 	Address   string `json:"address"`
 	Arguments []Argument
 	Sender    net.Addr
+
+	// Seq is the sequence number of the packet the message was received in.
+	// Servers number the packets they receive starting at 1,
+	// so Seq is 0 for messages that were not received by a server.
+	Seq uint64
+
+	// signature is a view of the type tags, without the comma,
+	// in the data the message was parsed from. See SignatureBytes.
+	signature []byte
+
+	// wireLen is the number of bytes the message occupied
+	// in the data it was parsed from. See WireLength.
+	wireLen int
+
+	// timetag is the timetag of the bundle the message was dispatched in, see Age.
+	timetag Timetag
+
+	// handlerContext is the state of the sender, see HandlerContext.
+	handlerContext *HandlerContext
+
+	// ctx is the context of the handlers, see Context.
+	ctx context.Context
+}
+
+// ParseOptions control how messages are parsed.
+// The zero value parses messages the same way ParseMessage does.
+type ParseOptions struct {
+	// Trace, if not nil, is called for every argument that is read
+	// with its type tag and its offset in the data being parsed.
+	// This can help with debugging messages that do not parse as expected.
+	Trace func(tag byte, offset int, arg Argument)
+
+	// PreallocLimit, if positive, caps the number of arguments that room is
+	// preallocated for before the arguments are read.
+	// By default room is preallocated for every type tag, so a message with a huge
+	// type tag string forces a huge allocation even if it has no argument data.
+	PreallocLimit int
+
+	// MaxStringLen is the maximum length in bytes of string arguments.
+	// Longer strings make parsing fail with ErrStringTooLarge before they are allocated.
+	// Zero means DefaultMaxStringLen and a negative value means no limit.
+	// The limit does not apply to the address, which is only limited by the size of the packet.
+	MaxStringLen int
+
+	// MaxBlobLen, if positive, is the maximum length in bytes of blob arguments.
+	// Longer blobs make parsing fail with ErrBlobTooLarge.
+	MaxBlobLen int
+
+	// MaxArguments is the maximum number of arguments of a message.
+	// Messages with more type tags make parsing fail with ErrTooManyArguments
+	// before any argument is read. An array, i.e. the type tags between '[' and ']',
+	// counts as a single argument.
+	// Zero means DefaultMaxArguments and a negative value means no limit.
+	MaxArguments int
+
+	// ReverseArgs reverses the order of the arguments after they are read.
+	// This is a workaround for a legacy device whose firmware sends its arguments
+	// in reverse order. The arguments are still decoded according to the type tags,
+	// only the resulting Arguments slice is reversed.
+	ReverseArgs bool
+}
+
+// prealloc returns the number of arguments to preallocate room for
+// given the number of type tags.
+func (opts ParseOptions) prealloc(n int) int {
+	if opts.PreallocLimit > 0 && n > opts.PreallocLimit {
+		return opts.PreallocLimit
+	}
+	return n
+}
+
+// checkArgumentCount returns an error if there are more arguments
+// than allowed for the type tags, which must not start with the type tag prefix.
+func (opts ParseOptions) checkArgumentCount(typetags []byte) error {
+	max := opts.MaxArguments
+	if max == 0 {
+		max = DefaultMaxArguments
+	}
+	if max < 0 {
+		return nil
+	}
+	var n, depth int
+	for _, tt := range typetags {
+		switch tt {
+		case '[':
+			if depth == 0 {
+				n++
+			}
+			depth++
+		case ']':
+			if depth > 0 {
+				depth--
+			}
+		default:
+			if depth == 0 {
+				n++
+			}
+		}
+	}
+	if n > max {
+		return errors.Wrapf(ErrTooManyArguments, "%d arguments, max %d", n, max)
+	}
+	return nil
+}
+
+// checkArgumentLen returns an error if the string or blob argument
+// at the start of data is longer than allowed.
+func (opts ParseOptions) checkArgumentLen(tt byte, data []byte) error {
+	switch tt {
+	case TypetagString:
+		max := opts.MaxStringLen
+		if max == 0 {
+			max = DefaultMaxStringLen
+		}
+		if max < 0 {
+			return nil
+		}
+		n := bytes.IndexByte(data, 0)
+		if n == -1 {
+			n = len(data)
+		}
+		if n > max {
+			return errors.Wrapf(ErrStringTooLarge, "%d bytes, max %d", n, max)
+		}
+	case TypetagBlob:
+		if opts.MaxBlobLen <= 0 || len(data) < 4 {
+			return nil
+		}
+		if n := int32(byteOrder.Uint32(data)); int64(n) > int64(opts.MaxBlobLen) {
+			return errors.Wrapf(ErrBlobTooLarge, "%d bytes, max %d", n, opts.MaxBlobLen)
+		}
+	}
+	return nil
 }
 
 // ParseMessage parses an OSC message from a slice of bytes.
 func ParseMessage(data []byte, sender net.Addr) (Message, error) {
-	address, idx := ReadString(data)
-	msg := Message{
-		Address: address,
-		Sender:  sender,
+	msg, _, err := ParseMessageN(data, sender)
+	return msg, err
+}
+
+// ParseMessageWith parses an OSC message from a slice of bytes using the given parse options.
+func ParseMessageWith(data []byte, sender net.Addr, opts ParseOptions) (Message, error) {
+	msg := Message{Arguments: []Argument{}}
+	if _, err := parseMessageInto(data, sender, &msg, opts); err != nil {
+		return Message{}, err
+	}
+	return msg, nil
+}
+
+// ParseMessageN parses an OSC message from a slice of bytes
+// and returns the number of bytes the message occupied.
+// This can be used to advance to the next packet when parsing
+// several packets from the same slice of bytes.
+func ParseMessageN(data []byte, sender net.Addr) (Message, int, error) {
+	msg := Message{Arguments: []Argument{}}
+	n, err := parseMessageInto(data, sender, &msg, ParseOptions{})
+	if err != nil {
+		return Message{}, 0, err
+	}
+	return msg, n, nil
+}
+
+// ParseMessageInto parses an OSC message from a slice of bytes into msg.
+// The message's Arguments slice is reused, so parsing many messages
+// into the same Message only allocates when a message has more arguments
+// than any message before it.
+// This means the arguments of the previous message must not be retained.
+// If an error is returned the contents of msg are undefined.
+func ParseMessageInto(data []byte, sender net.Addr, msg *Message) error {
+	_, err := parseMessageInto(data, sender, msg, ParseOptions{})
+	return err
+}
+
+// parseMessageInto parses an OSC message into msg and returns the number of bytes consumed.
+func parseMessageInto(data []byte, sender net.Addr, msg *Message, opts ParseOptions) (int, error) {
+	address, addressLen := ReadString(data)
+	typetags, typetagsLen := ReadString(data[addressLen:])
+
+	msg.Address = address
+	msg.Sender = sender
+	msg.Seq = 0
+	msg.signature = nil
+	msg.wireLen = 0
+	msg.timetag = 0
+	msg.handlerContext = nil
+	msg.ctx = nil
+	if start, end := int(addressLen)+1, int(addressLen)+len(typetags); len(typetags) > 0 && end <= len(data) {
+		msg.signature = data[start:end:end]
 	}
-	data = data[idx:]
-	typetags, idx := ReadString(data)
-	data = data[idx:]
 
 	// Read all arguments.
-	args, err := ReadArguments([]byte(typetags), data)
+	args, argsLen, err := appendArguments(msg.Arguments[:0], []byte(typetags), data[addressLen+typetagsLen:], opts, addressLen+typetagsLen)
 	if err != nil {
-		return Message{}, errors.Wrap(err, "parse message")
+		if pe, ok := err.(ParseError); ok {
+			// Describe the whole message rather than just its arguments.
+			// The error already names the argument that failed.
+			return 0, newParseError(data, pe.Offset, pe.Typetag, pe.Err)
+		}
+		return 0, errors.Wrap(err, "parse message")
+	}
+	if opts.ReverseArgs {
+		for i, j := 0, len(args)-1; i < j; i, j = i+1, j-1 {
+			args[i], args[j] = args[j], args[i]
+		}
 	}
 	msg.Arguments = args
+	msg.wireLen = int(addressLen + typetagsLen + argsLen)
 
-	return msg, nil
+	return msg.wireLen, nil
 }
 
 // Bytes returns the contents of the message as a slice of bytes.
@@ -55,11 +258,157 @@ func (msg Message) Bytes() []byte {
 		msg.Typetags(),
 	}
 	for _, a := range msg.Arguments {
-		b = append(b, a.Bytes())
+		b = append(b, EncodeArgument(a))
 	}
 	return bytes.Join(b, []byte{})
 }
 
+// ArgumentBytes returns the encoded arguments of the message,
+// without the address and type tags.
+// It is useful when the address and type tags are transmitted out-of-band,
+// ReadArguments reads the arguments back given the type tags.
+func (msg Message) ArgumentBytes() ([]byte, error) {
+	b := []byte{}
+	for i, a := range msg.Arguments {
+		if a == nil {
+			return nil, errors.Errorf("argument %d is nil", i)
+		}
+		b = append(b, EncodeArgument(a)...)
+	}
+	return b, nil
+}
+
+// EncodedLen returns the number of bytes returned by Bytes
+// without encoding the message.
+func (msg Message) EncodedLen() int {
+	n := String(msg.Address).PaddedLen() + paddedLen(len(msg.Arguments)+2)
+	for _, a := range msg.Arguments {
+		n += argumentSize(a)
+	}
+	return n
+}
+
+// WireLength returns the number of bytes the message occupied in the data it was parsed from,
+// without encoding the message. This is the same number ParseMessageN returns,
+// even if the message was changed after it was parsed.
+// For messages that were not parsed it returns EncodedLen.
+func (msg Message) WireLength() int {
+	if msg.wireLen > 0 {
+		return msg.wireLen
+	}
+	return msg.EncodedLen()
+}
+
+// MarshalTo writes the contents of the message to buf and returns the number of bytes written.
+// It returns io.ErrShortBuffer without writing anything if the message does not fit,
+// see EncodedLen. Apart from arguments of custom types, MarshalTo does not allocate.
+func (msg Message) MarshalTo(buf []byte) (int, error) {
+	if msg.EncodedLen() > len(buf) {
+		return 0, io.ErrShortBuffer
+	}
+	n := putString(buf, msg.Address)
+
+	// Type tags.
+	tt := buf[n:]
+	tt[0] = TypetagPrefix
+	for i, a := range msg.Arguments {
+		tt[i+1] = a.Typetag()
+	}
+	n += putPadding(tt, len(msg.Arguments)+1, paddedLen(len(msg.Arguments)+2))
+
+	for _, a := range msg.Arguments {
+		switch x := a.(type) {
+		case Int:
+			byteOrder.PutUint32(buf[n:], uint32(x))
+			n += 4
+		case Float:
+			byteOrder.PutUint32(buf[n:], math.Float32bits(float32(x)))
+			n += 4
+		case UInt32:
+			byteOrder.PutUint32(buf[n:], uint32(x))
+			n += 4
+		case Color:
+			buf[n], buf[n+1], buf[n+2], buf[n+3] = x.R, x.G, x.B, x.A
+			n += 4
+		case Int64:
+			byteOrder.PutUint64(buf[n:], uint64(x))
+			n += 8
+		case Timetag:
+			byteOrder.PutUint64(buf[n:], uint64(x))
+			n += 8
+		case Bool:
+		case String:
+			n += putString(buf[n:], string(x))
+		case Blob:
+			byteOrder.PutUint32(buf[n:], uint32(len(x)))
+			copy(buf[n+4:], x)
+			n += 4 + putPadding(buf[n+4:], len(x), paddedLen(len(x)))
+		case *ReaderBlob:
+			byteOrder.PutUint32(buf[n:], uint32(x.len))
+			if _, err := io.ReadFull(x.r, buf[n+4:n+4+x.len]); err != nil {
+				return 0, errors.Wrap(err, "read blob")
+			}
+			n += 4 + putPadding(buf[n+4:], x.len, paddedLen(x.len))
+		default:
+			n += copy(buf[n:], EncodeArgument(a))
+		}
+	}
+	return n, nil
+}
+
+// putString writes the OSC representation of s to buf like ToBytes,
+// and returns the number of bytes written.
+func putString(buf []byte, s string) int {
+	if len(s) == 0 {
+		return 0
+	}
+	copy(buf, s)
+	return putPadding(buf, len(s), paddedLen(len(s)+1))
+}
+
+// putPadding zeroes buf[from:to] and returns to.
+func putPadding(buf []byte, from, to int) int {
+	for i := from; i < to; i++ {
+		buf[i] = 0
+	}
+	return to
+}
+
+// HandlerContext returns the handler context of the message's sender,
+// or nil if the connection that received the message does not keep sender state,
+// see UDPConn.SetSenderState.
+func (msg Message) HandlerContext() *HandlerContext {
+	return msg.handlerContext
+}
+
+// Context returns the context of the message's handlers.
+// It is done when the handlers should give up, e.g. once the handler timeout
+// of the connection that received the message expired, see UDPConn.SetHandlerTimeout.
+// It is context.Background() if the message has no context.
+func (msg Message) Context() context.Context {
+	if msg.ctx == nil {
+		return context.Background()
+	}
+	return msg.ctx
+}
+
+// WithContext returns a copy of the message with the given context, see Context.
+func (msg Message) WithContext(ctx context.Context) Message {
+	msg.ctx = ctx
+	return msg
+}
+
+// Age returns how long ago the timetag of the bundle the message was dispatched in was,
+// e.g. to detect stale scheduled messages. It is negative for timetags in the future.
+// Age returns zero for messages that were not dispatched in a bundle
+// and for bundles with the Immediately timetag.
+func (msg Message) Age() time.Duration {
+	if msg.timetag == 0 || msg.timetag == Immediately {
+		return 0
+	}
+	return time.Since(msg.timetag.Time())
+}
+
 // Equal returns true if the messages are equal, false otherwise.
 func (msg Message) Equal(other Packet) bool {
 	msg2, ok := other.(Message)
@@ -80,22 +429,122 @@ func (msg Message) Equal(other Packet) bool {
 	return true
 }
 
+// EqualWithTolerance returns true if the messages are equal, comparing float arguments
+// with Float.NearlyEqual and all other arguments exactly.
+func EqualWithTolerance(a, b Message, epsilon float32) bool {
+	if a.Address != b.Address || len(a.Arguments) != len(b.Arguments) {
+		return false
+	}
+	for i, arg := range a.Arguments {
+		fa, ok := arg.(Float)
+		if !ok {
+			if !arg.Equal(b.Arguments[i]) {
+				return false
+			}
+			continue
+		}
+		fb, ok := b.Arguments[i].(Float)
+		if !ok || !fa.NearlyEqual(fb, epsilon) {
+			return false
+		}
+	}
+	return true
+}
+
+// MatchOptions control how the address pattern of a message is matched
+// against the address of a method.
+type MatchOptions struct {
+	// Exact only matches addresses that are identical to the message's address.
+	Exact bool
+
+	// CaseInsensitive ignores case when matching.
+	// Note that OSC addresses are normally case-sensitive.
+	CaseInsensitive bool
+}
+
 // Match returns true if the address of the OSC Message matches the given address.
 func (msg Message) Match(address string, exactMatch bool) (bool, error) {
-	if exactMatch {
-		return address == msg.Address, nil
+	return msg.MatchWith(address, MatchOptions{Exact: exactMatch})
+}
+
+// MatchWith returns true if the address of the OSC Message matches the given address
+// using the given match options.
+func (msg Message) MatchWith(address string, opts MatchOptions) (bool, error) {
+	pattern := msg.Address
+	if opts.CaseInsensitive {
+		address, pattern = strings.ToLower(address), strings.ToLower(pattern)
+	}
+	if opts.Exact {
+		return address == pattern, nil
 	}
 	// Verify same number of parts.
-	if !VerifyParts(address, msg.Address) {
+	if !VerifyParts(address, pattern) {
 		return false, nil
 	}
-	exp, err := GetRegex(msg.Address)
+	exp, err := getRegex(pattern, opts.CaseInsensitive)
 	if err != nil {
 		return false, err
 	}
 	return exp.MatchString(address), nil
 }
 
+// MatchScore scores how specifically pattern matches address,
+// so that the most specific of several matching patterns can be chosen.
+// It returns 0 if the pattern does not match, and 1000 if it is equal to the address.
+// Otherwise each segment of the pattern adds to the score:
+// 100 for a literal segment, 10 for a segment with '?' or '[',
+// 5 for a segment with '{' and 1 for a segment with '*'.
+// A segment that contains several kinds of wildcards scores as the least specific one.
+func MatchScore(pattern, address string) (int, error) {
+	if pattern == address {
+		return 1000, nil
+	}
+	matched, err := Message{Address: pattern}.MatchWith(address, MatchOptions{})
+	if err != nil {
+		return 0, err
+	}
+	if !matched {
+		return 0, nil
+	}
+	score := 0
+	for _, segment := range strings.Split(strings.TrimPrefix(pattern, string(MessageChar)), string(MessageChar)) {
+		switch {
+		case strings.Contains(segment, "*"):
+			score++
+		case strings.Contains(segment, "{"):
+			score += 5
+		case strings.ContainsAny(segment, "?["):
+			score += 10
+		default:
+			score += 100
+		}
+	}
+	return score, nil
+}
+
+// Rebase returns a copy of the message whose address has the leading oldPrefix replaced
+// with newPrefix, e.g. rebasing "/synth/1/freq" from "/synth" to "/rack/a" gives "/rack/a/1/freq".
+// Prefixes only match whole address segments, and an error is returned if the address
+// does not start with oldPrefix. An oldPrefix of "/" or "" matches every address.
+// Everything but the address, e.g. the sender and the timetag, is kept,
+// and the arguments are shared with the original message.
+func (msg Message) Rebase(oldPrefix, newPrefix string) (Message, error) {
+	oldPrefix = strings.TrimSuffix(oldPrefix, string(MessageChar))
+	newPrefix = strings.TrimSuffix(newPrefix, string(MessageChar))
+
+	rest := strings.TrimPrefix(msg.Address, oldPrefix)
+	if !strings.HasPrefix(msg.Address, oldPrefix) || (rest != "" && rest[0] != MessageChar) {
+		return Message{}, errors.Wrapf(ErrPrefixMismatch, "address %q, prefix %q", msg.Address, oldPrefix)
+	}
+	address := newPrefix + rest
+	if address == "" {
+		address = string(MessageChar)
+	}
+	rebased := msg
+	rebased.Address = address
+	return rebased, nil
+}
+
 // Typetags returns a padded byte slice of the message's type tags.
 func (msg Message) Typetags() []byte {
 	tt := make([]byte, len(msg.Arguments)+1)
@@ -106,6 +555,41 @@ func (msg Message) Typetags() []byte {
 	return Pad(append(tt, 0))
 }
 
+// SignatureBytes returns the message's type tags without the comma and padding, e.g. "ifs".
+// For messages that were parsed, and whose arguments have the same types since,
+// the returned bytes are a view into the parsed data and SignatureBytes does not allocate.
+// This lets routers compare signatures cheaply. The returned bytes must not be modified.
+func (msg Message) SignatureBytes() []byte {
+	if len(msg.signature) == len(msg.Arguments) {
+		valid := true
+		for i, a := range msg.Arguments {
+			if a.Typetag() != msg.signature[i] {
+				valid = false
+				break
+			}
+		}
+		if valid {
+			return msg.signature
+		}
+	}
+	sig := make([]byte, len(msg.Arguments))
+	for i, a := range msg.Arguments {
+		sig[i] = a.Typetag()
+	}
+	return sig
+}
+
+// TypetagString returns the message's type tag string, e.g. ",ifs",
+// without the padding that Typetags adds.
+func (msg Message) TypetagString() string {
+	tt := make([]byte, len(msg.Arguments)+1)
+	tt[0] = TypetagPrefix
+	for i, a := range msg.Arguments {
+		tt[i+1] = a.Typetag()
+	}
+	return string(tt)
+}
+
 // WriteTo writes the Message to an io.Writer.
 func (msg Message) WriteTo(w io.Writer) (int64, error) {
 	var bytesWritten int
@@ -127,18 +611,100 @@ func (msg Message) WriteTo(w io.Writer) (int64, error) {
 	return int64(bytesWritten), nil
 }
 
+// WriteBinary writes the OSC encoding of the message to w, i.e. what Bytes returns.
+// Reader blobs are copied to w without being held in memory.
+func (msg Message) WriteBinary(w io.Writer) (int64, error) {
+	var n int64
+
+	nw, err := w.Write(append(ToBytes(msg.Address), msg.Typetags()...))
+	n += int64(nw)
+	if err != nil {
+		return n, errors.Wrap(err, "write address and type tags")
+	}
+	for i, a := range msg.Arguments {
+		var (
+			na  int64
+			err error
+		)
+		switch x := a.(type) {
+		case Blob:
+			na, err = x.WriteBinary(w)
+		case *ReaderBlob:
+			na, err = x.WriteBinary(w)
+		default:
+			var nb int
+			nb, err = w.Write(EncodeArgument(a))
+			na = int64(nb)
+		}
+		n += na
+		if err != nil {
+			return n, errors.Wrapf(err, "write argument %d", i)
+		}
+	}
+	return n, nil
+}
+
 // GetRegex compiles and returns a regular expression object for the given address pattern.
 func GetRegex(pattern string) (*regexp.Regexp, error) {
-	pattern = strings.Replace(pattern, ".", "\\.", -1) // Escape all '.' in the pattern
-	pattern = strings.Replace(pattern, "(", "\\(", -1) // Escape all '(' in the pattern
-	pattern = strings.Replace(pattern, ")", "\\)", -1) // Escape all ')' in the pattern
-	pattern = strings.Replace(pattern, "*", ".*", -1)  // Replace a '*' with '.*' that matches zero or more characters
-	pattern = strings.Replace(pattern, "{", "(", -1)   // Change a '{' to '('
-	pattern = strings.Replace(pattern, ",", "|", -1)   // Change a ',' to '|'
-	pattern = strings.Replace(pattern, "}", ")", -1)   // Change a '}' to ')'
-	pattern = strings.Replace(pattern, "?", ".", -1)   // Change a '?' to '.'
-	pattern = "^" + pattern + "$"
-	return regexp.Compile(pattern)
+	return getRegex(pattern, false)
+}
+
+// getRegex compiles a regular expression for the given address pattern
+// that optionally ignores case, see addressPatternSource.
+func getRegex(pattern string, caseInsensitive bool) (*regexp.Regexp, error) {
+	src := "^" + addressPatternSource(pattern) + "$"
+	if caseInsensitive {
+		src = "(?i)" + src
+	}
+	return regexp.Compile(src)
+}
+
+// addressPatternSource translates an address pattern to the source of a regular expression.
+// '*' and '?' do not match '/', "[!...]" is a negated character class
+// and "{a,b}" is a capturing group of alternatives.
+// Characters escaped with a backslash match literally, see EscapeAddressSegment,
+// and so do all other characters.
+func addressPatternSource(pattern string) string {
+	var (
+		b      strings.Builder
+		braces int
+	)
+	for i := 0; i < len(pattern); i++ {
+		switch c := pattern[i]; {
+		case c == '\\':
+			if i+1 < len(pattern) {
+				i++
+			}
+			b.WriteString(regexp.QuoteMeta(pattern[i : i+1])) // Match the escaped character literally
+		case c == '*':
+			b.WriteString("[^/]*")
+		case c == '?':
+			b.WriteString("[^/]")
+		case c == '{':
+			braces++
+			b.WriteByte('(')
+		case c == '}' && braces > 0:
+			braces--
+			b.WriteByte(')')
+		case c == ',' && braces > 0:
+			b.WriteByte('|')
+		case c == '[':
+			end := strings.IndexByte(pattern[i:], ']')
+			if end < 0 {
+				b.WriteByte('[') // Let the regexp package report the error.
+				continue
+			}
+			class := pattern[i+1 : i+end]
+			if strings.HasPrefix(class, "!") {
+				class = "^" + class[1:]
+			}
+			b.WriteString("[" + class + "]")
+			i += end
+		default:
+			b.WriteString(regexp.QuoteMeta(pattern[i : i+1]))
+		}
+	}
+	return b.String()
 }
 
 // VerifyParts verifies that m1 and m2 have the same number of parts,
@@ -0,0 +1,52 @@
+package osc
+
+// The Add methods return a copy of the message with an argument of an exact Go type
+// appended, so calls can be chained:
+//
+//	msg := Message{Address: "/synth/1"}.AddString("freq").AddFloat32(440)
+//
+// Unlike building the Arguments slice by hand, passing a value of
+// the wrong type, e.g. a float64 to AddInt32, does not compile.
+
+// AddInt32 appends an int argument to the message.
+func (msg Message) AddInt32(i int32) Message {
+	msg.Arguments = append(msg.Arguments, Int(i))
+	return msg
+}
+
+// AddFloat32 appends a float argument to the message.
+func (msg Message) AddFloat32(f float32) Message {
+	msg.Arguments = append(msg.Arguments, Float(f))
+	return msg
+}
+
+// AddString appends a string argument to the message.
+func (msg Message) AddString(s string) Message {
+	msg.Arguments = append(msg.Arguments, String(s))
+	return msg
+}
+
+// AddBool appends a true or false argument to the message.
+func (msg Message) AddBool(b bool) Message {
+	msg.Arguments = append(msg.Arguments, Bool(b))
+	return msg
+}
+
+// AddBlob appends a blob argument to the message.
+// The blob refers to b, it is not copied.
+func (msg Message) AddBlob(b []byte) Message {
+	msg.Arguments = append(msg.Arguments, Blob(b))
+	return msg
+}
+
+// AddUInt32 appends an unsigned int argument to the message, see UInt32.
+func (msg Message) AddUInt32(u uint32) Message {
+	msg.Arguments = append(msg.Arguments, UInt32(u))
+	return msg
+}
+
+// AddColor appends a color argument to the message.
+func (msg Message) AddColor(c Color) Message {
+	msg.Arguments = append(msg.Arguments, c)
+	return msg
+}
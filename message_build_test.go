@@ -0,0 +1,39 @@
+package osc
+
+import (
+	"testing"
+)
+
+func TestMessageAdd(t *testing.T) {
+	for _, testcase := range []struct {
+		Build     func(msg Message) Message
+		Signature string
+		Expected  Argument
+	}{
+		{Build: func(msg Message) Message { return msg.AddInt32(-3) }, Signature: "i", Expected: Int(-3)},
+		{Build: func(msg Message) Message { return msg.AddFloat32(0.5) }, Signature: "f", Expected: Float(0.5)},
+		{Build: func(msg Message) Message { return msg.AddString("saw") }, Signature: "s", Expected: String("saw")},
+		{Build: func(msg Message) Message { return msg.AddBool(true) }, Signature: "T", Expected: Bool(true)},
+		{Build: func(msg Message) Message { return msg.AddBool(false) }, Signature: "F", Expected: Bool(false)},
+		{Build: func(msg Message) Message { return msg.AddBlob([]byte{1, 2}) }, Signature: "b", Expected: Blob{1, 2}},
+		{Build: func(msg Message) Message { return msg.AddUInt32(1 << 31) }, Signature: "u", Expected: UInt32(1 << 31)},
+		{Build: func(msg Message) Message { return msg.AddColor(Color{R: 1, A: 255}) }, Signature: "r", Expected: Color{R: 1, A: 255}},
+	} {
+		msg := testcase.Build(Message{Address: "/foo"})
+		if expected, got := ","+testcase.Signature, msg.TypetagString(); expected != got {
+			t.Fatalf("expected signature %s, got %s", expected, got)
+		}
+		if !testcase.Expected.Equal(msg.Arguments[0]) {
+			t.Fatalf("expected %s, got %s", testcase.Expected, msg.Arguments[0])
+		}
+	}
+}
+
+func TestMessageAddChain(t *testing.T) {
+	msg := Message{Address: "/synth/1"}.AddString("freq").AddFloat32(440).AddInt32(1)
+
+	expected := Message{Address: "/synth/1", Arguments: []Argument{String("freq"), Float(440), Int(1)}}
+	if !expected.Equal(msg) {
+		t.Fatalf("expected %v, got %v", expected, msg)
+	}
+}
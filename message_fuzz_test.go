@@ -0,0 +1,26 @@
+//go:build go1.18
+// +build go1.18
+
+package osc
+
+import (
+	"regexp/syntax"
+	"testing"
+)
+
+func FuzzGetRegex(f *testing.F) {
+	for _, seed := range []string{"", "/", "{", "[!", "**", "/foo/{bar,baz}/[a-c]?", "/foo.(bar)"} {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, pattern string) {
+		re, err := GetRegex(pattern)
+		if err != nil {
+			if _, ok := err.(*syntax.Error); !ok {
+				t.Fatalf("(%q) expected a *syntax.Error, got %T: %s", pattern, err, err)
+			}
+			return
+		}
+		_ = re.MatchString(pattern)
+		_ = re.MatchString("/foo/bar")
+	})
+}
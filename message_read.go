@@ -0,0 +1,241 @@
+package osc
+
+import (
+	"math"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Common errors.
+var (
+	ErrInvalidEnum  = errors.New("invalid enum value")
+	ErrInvalidRange = errors.New("invalid range")
+	ErrOutOfRange   = errors.New("value out of range")
+)
+
+// argAt returns the argument at index i.
+func (msg Message) argAt(i int) (Argument, error) {
+	if i < 0 || i >= len(msg.Arguments) {
+		return nil, errors.Wrapf(ErrIndexOutOfBounds, "argument %d", i)
+	}
+	return msg.Arguments[i], nil
+}
+
+// float32At reads the float argument at index i.
+func (msg Message) float32At(i int) (float32, error) {
+	arg, err := msg.argAt(i)
+	if err != nil {
+		return 0, err
+	}
+	f, err := arg.ReadFloat32()
+	if err != nil {
+		return 0, errors.Wrapf(err, "argument %d", i)
+	}
+	return f, nil
+}
+
+// int32At reads the int argument at index i.
+func (msg Message) int32At(i int) (int32, error) {
+	arg, err := msg.argAt(i)
+	if err != nil {
+		return 0, err
+	}
+	v, err := arg.ReadInt32()
+	if err != nil {
+		return 0, errors.Wrapf(err, "argument %d", i)
+	}
+	return v, nil
+}
+
+// boolAt reads the boolean argument at index i.
+func (msg Message) boolAt(i int) (bool, error) {
+	arg, err := msg.argAt(i)
+	if err != nil {
+		return false, err
+	}
+	b, err := arg.ReadBool()
+	if err != nil {
+		return false, errors.Wrapf(err, "argument %d", i)
+	}
+	return b, nil
+}
+
+// ColorFloatsAt reads the color argument at index i
+// and returns its channels normalized to [0, 1], see Color.Floats.
+func (msg Message) ColorFloatsAt(i int) (r, g, b, a float32, err error) {
+	arg, err := msg.argAt(i)
+	if err != nil {
+		return 0, 0, 0, 0, err
+	}
+	c, ok := arg.(Color)
+	if !ok {
+		return 0, 0, 0, 0, errors.Wrapf(ErrInvalidTypeTag, "argument %d", i)
+	}
+	r, g, b, a = c.Floats()
+	return r, g, b, a, nil
+}
+
+// Int32AtOr reads the int argument at index i.
+// It returns def if there is no argument at index i or it is not an int.
+func (msg Message) Int32AtOr(i int, def int32) int32 {
+	if v, err := msg.int32At(i); err == nil {
+		return v
+	}
+	return def
+}
+
+// Float32AtOr reads the float argument at index i.
+// It returns def if there is no argument at index i or it is not a float.
+func (msg Message) Float32AtOr(i int, def float32) float32 {
+	if v, err := msg.float32At(i); err == nil {
+		return v
+	}
+	return def
+}
+
+// StringAtOr reads the string argument at index i.
+// It returns def if there is no argument at index i or it is not a string.
+func (msg Message) StringAtOr(i int, def string) string {
+	if v, err := msg.stringAt(i); err == nil {
+		return v
+	}
+	return def
+}
+
+// BoolAtOr reads the boolean argument at index i.
+// It returns def if there is no argument at index i or it is not a boolean.
+func (msg Message) BoolAtOr(i int, def bool) bool {
+	if v, err := msg.boolAt(i); err == nil {
+		return v
+	}
+	return def
+}
+
+// EnumAt reads the int argument at index i and checks that it is one of the valid values.
+// If it is not, an error wrapping ErrInvalidEnum is returned.
+func (msg Message) EnumAt(i int, valid ...int32) (int32, error) {
+	v, err := msg.int32At(i)
+	if err != nil {
+		return 0, err
+	}
+	for _, x := range valid {
+		if v == x {
+			return v, nil
+		}
+	}
+	return 0, errors.Wrapf(ErrInvalidEnum, "argument %d: %d", i, v)
+}
+
+// stringAt reads the string argument at index i.
+func (msg Message) stringAt(i int) (string, error) {
+	arg, err := msg.argAt(i)
+	if err != nil {
+		return "", err
+	}
+	s, err := arg.ReadString()
+	if err != nil {
+		return "", errors.Wrapf(err, "argument %d", i)
+	}
+	return s, nil
+}
+
+// CSVAt reads the string argument at index i and splits it on commas.
+// Empty fields are preserved.
+func (msg Message) CSVAt(i int) ([]string, error) {
+	return msg.SplitAt(i, ",")
+}
+
+// SplitAt reads the string argument at index i and splits it on sep.
+// Empty fields are preserved.
+func (msg Message) SplitAt(i int, sep string) ([]string, error) {
+	s, err := msg.stringAt(i)
+	if err != nil {
+		return nil, err
+	}
+	return strings.Split(s, sep), nil
+}
+
+// Vec2At reads a 2D coordinate from the float arguments at index i and i+1.
+func (msg Message) Vec2At(i int) (x, y float32, err error) {
+	if x, err = msg.float32At(i); err != nil {
+		return 0, 0, err
+	}
+	if y, err = msg.float32At(i + 1); err != nil {
+		return 0, 0, err
+	}
+	return x, y, nil
+}
+
+// Vec3At reads a 3D coordinate from the float arguments at index i, i+1 and i+2.
+func (msg Message) Vec3At(i int) (x, y, z float32, err error) {
+	if x, y, err = msg.Vec2At(i); err != nil {
+		return 0, 0, 0, err
+	}
+	if z, err = msg.float32At(i + 2); err != nil {
+		return 0, 0, 0, err
+	}
+	return x, y, z, nil
+}
+
+// MapFloatAt reads the float argument at index i, which must be within [inMin, inMax],
+// and maps it linearly to [outMin, outMax], e.g. to map a fader from 0-1 to -60-0 dB.
+// If the argument is out of range an error wrapping ErrOutOfRange is returned.
+func (msg Message) MapFloatAt(i int, inMin, inMax, outMin, outMax float32) (float32, error) {
+	t, err := msg.normalizedFloatAt(i, inMin, inMax)
+	if err != nil {
+		return 0, err
+	}
+	return outMin + t*(outMax-outMin), nil
+}
+
+// MapFloatLogAt reads the float argument at index i, which must be within [inMin, inMax],
+// and maps it logarithmically to [outMin, outMax], e.g. to map a fader from 0-1 to 20-20000 Hz.
+// outMin and outMax must both be positive.
+// If the argument is out of range an error wrapping ErrOutOfRange is returned.
+func (msg Message) MapFloatLogAt(i int, inMin, inMax, outMin, outMax float32) (float32, error) {
+	if outMin <= 0 || outMax <= 0 {
+		return 0, errors.Wrapf(ErrInvalidRange, "logarithmic range [%f, %f]", outMin, outMax)
+	}
+	t, err := msg.normalizedFloatAt(i, inMin, inMax)
+	if err != nil {
+		return 0, err
+	}
+	return outMin * float32(math.Pow(float64(outMax/outMin), float64(t))), nil
+}
+
+// normalizedFloatAt reads the float argument at index i and maps it from [inMin, inMax] to [0, 1].
+func (msg Message) normalizedFloatAt(i int, inMin, inMax float32) (float32, error) {
+	if inMin == inMax {
+		return 0, errors.Wrapf(ErrInvalidRange, "input range [%f, %f]", inMin, inMax)
+	}
+	f, err := msg.float32At(i)
+	if err != nil {
+		return 0, err
+	}
+	if (f < inMin && f < inMax) || (f > inMin && f > inMax) {
+		return 0, errors.Wrapf(ErrOutOfRange, "argument %d: %f not in [%f, %f]", i, f, inMin, inMax)
+	}
+	return (f - inMin) / (inMax - inMin), nil
+}
+
+// ForEachArg calls fn for each argument in order until fn returns false.
+func (msg Message) ForEachArg(fn func(index int, a Argument) bool) {
+	for i, a := range msg.Arguments {
+		if !fn(i, a) {
+			return
+		}
+	}
+}
+
+// ArgsByType groups the message's arguments by their type tag.
+// The arguments of each type are in the order they appear in the message.
+// Note that true and false have different type tags.
+func (msg Message) ArgsByType() map[byte][]Argument {
+	m := map[byte][]Argument{}
+	for _, a := range msg.Arguments {
+		tt := a.Typetag()
+		m[tt] = append(m[tt], a)
+	}
+	return m
+}
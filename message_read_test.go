@@ -0,0 +1,295 @@
+package osc
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/pkg/errors"
+)
+
+func TestMessageVec2At(t *testing.T) {
+	msg := Message{Address: "/touch", Arguments: []Argument{Float(0.25), Float(0.75)}}
+
+	x, y, err := msg.Vec2At(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected, got := float32(0.25), x; expected != got {
+		t.Fatalf("expected %f, got %f", expected, got)
+	}
+	if expected, got := float32(0.75), y; expected != got {
+		t.Fatalf("expected %f, got %f", expected, got)
+	}
+	if _, _, err := msg.Vec2At(1); errors.Cause(err) != ErrIndexOutOfBounds {
+		t.Fatalf("expected ErrIndexOutOfBounds, got %v", err)
+	}
+	if _, _, err := msg.Vec2At(-1); errors.Cause(err) != ErrIndexOutOfBounds {
+		t.Fatalf("expected ErrIndexOutOfBounds, got %v", err)
+	}
+	bad := Message{Address: "/touch", Arguments: []Argument{Float(0.25), Int(1)}}
+	if _, _, err := bad.Vec2At(0); errors.Cause(err) != ErrInvalidTypeTag {
+		t.Fatalf("expected ErrInvalidTypeTag, got %v", err)
+	}
+}
+
+func TestMessageVec3At(t *testing.T) {
+	msg := Message{Address: "/pos", Arguments: []Argument{Int(7), Float(1), Float(2), Float(3)}}
+
+	x, y, z, err := msg.Vec3At(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if x != 1 || y != 2 || z != 3 {
+		t.Fatalf("expected (1, 2, 3), got (%f, %f, %f)", x, y, z)
+	}
+	if _, _, _, err := msg.Vec3At(2); errors.Cause(err) != ErrIndexOutOfBounds {
+		t.Fatalf("expected ErrIndexOutOfBounds, got %v", err)
+	}
+}
+
+func TestMessageEnumAt(t *testing.T) {
+	const (
+		modeOff int32 = iota
+		modeOn
+		modeAuto
+	)
+	msg := Message{Address: "/mode", Arguments: []Argument{Int(modeAuto), Int(7), Float(1)}}
+
+	v, err := msg.EnumAt(0, modeOff, modeOn, modeAuto)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected, got := modeAuto, v; expected != got {
+		t.Fatalf("expected %d, got %d", expected, got)
+	}
+	if _, err := msg.EnumAt(1, modeOff, modeOn, modeAuto); errors.Cause(err) != ErrInvalidEnum {
+		t.Fatalf("expected ErrInvalidEnum, got %v", err)
+	}
+	if _, err := msg.EnumAt(2, modeOff); errors.Cause(err) != ErrInvalidTypeTag {
+		t.Fatalf("expected ErrInvalidTypeTag, got %v", err)
+	}
+	if _, err := msg.EnumAt(3, modeOff); errors.Cause(err) != ErrIndexOutOfBounds {
+		t.Fatalf("expected ErrIndexOutOfBounds, got %v", err)
+	}
+}
+
+func TestMessageCSVAt(t *testing.T) {
+	msg := Message{Address: "/legacy", Arguments: []Argument{String("a,b,,c"), String("x;y"), Int(1)}}
+
+	fields, err := msg.CSVAt(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected, got := []string{"a", "b", "", "c"}, fields; !reflect.DeepEqual(expected, got) {
+		t.Fatalf("expected %q, got %q", expected, got)
+	}
+	fields, err = msg.SplitAt(1, ";")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected, got := []string{"x", "y"}, fields; !reflect.DeepEqual(expected, got) {
+		t.Fatalf("expected %q, got %q", expected, got)
+	}
+	if _, err := msg.CSVAt(2); errors.Cause(err) != ErrInvalidTypeTag {
+		t.Fatalf("expected ErrInvalidTypeTag, got %v", err)
+	}
+	if _, err := msg.CSVAt(3); errors.Cause(err) != ErrIndexOutOfBounds {
+		t.Fatalf("expected ErrIndexOutOfBounds, got %v", err)
+	}
+}
+
+func TestMessageAtOr(t *testing.T) {
+	msg := Message{Address: "/opt", Arguments: []Argument{Int(3), Float(0.5), String("saw"), Bool(true)}}
+
+	// Present.
+	if expected, got := int32(3), msg.Int32AtOr(0, 7); expected != got {
+		t.Fatalf("expected %d, got %d", expected, got)
+	}
+	if expected, got := float32(0.5), msg.Float32AtOr(1, 1); expected != got {
+		t.Fatalf("expected %f, got %f", expected, got)
+	}
+	if expected, got := "saw", msg.StringAtOr(2, "sine"); expected != got {
+		t.Fatalf("expected %s, got %s", expected, got)
+	}
+	if expected, got := true, msg.BoolAtOr(3, false); expected != got {
+		t.Fatalf("expected %t, got %t", expected, got)
+	}
+
+	// Absent.
+	if expected, got := int32(7), msg.Int32AtOr(4, 7); expected != got {
+		t.Fatalf("expected %d, got %d", expected, got)
+	}
+	if expected, got := float32(1), msg.Float32AtOr(-1, 1); expected != got {
+		t.Fatalf("expected %f, got %f", expected, got)
+	}
+	if expected, got := "sine", msg.StringAtOr(4, "sine"); expected != got {
+		t.Fatalf("expected %s, got %s", expected, got)
+	}
+	if expected, got := false, msg.BoolAtOr(4, false); expected != got {
+		t.Fatalf("expected %t, got %t", expected, got)
+	}
+
+	// Type mismatch.
+	if expected, got := int32(7), msg.Int32AtOr(1, 7); expected != got {
+		t.Fatalf("expected %d, got %d", expected, got)
+	}
+	if expected, got := float32(1), msg.Float32AtOr(0, 1); expected != got {
+		t.Fatalf("expected %f, got %f", expected, got)
+	}
+	if expected, got := "sine", msg.StringAtOr(3, "sine"); expected != got {
+		t.Fatalf("expected %s, got %s", expected, got)
+	}
+	if expected, got := true, msg.BoolAtOr(2, true); expected != got {
+		t.Fatalf("expected %t, got %t", expected, got)
+	}
+}
+
+func TestMessageMapFloatAt(t *testing.T) {
+	msg := Message{Address: "/fader", Arguments: []Argument{Float(0.5), Float(2), Int(1)}}
+
+	f, err := msg.MapFloatAt(0, 0, 1, 20, 20000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected, got := float32(10010), f; expected != got {
+		t.Fatalf("expected %f, got %f", expected, got)
+	}
+	// Inverted ranges.
+	if f, err = msg.MapFloatAt(0, 1, 0, 0, -60); err != nil {
+		t.Fatal(err)
+	}
+	if expected, got := float32(-30), f; expected != got {
+		t.Fatalf("expected %f, got %f", expected, got)
+	}
+	if _, err := msg.MapFloatAt(1, 0, 1, 20, 20000); errors.Cause(err) != ErrOutOfRange {
+		t.Fatalf("expected ErrOutOfRange, got %v", err)
+	}
+	if _, err := msg.MapFloatAt(2, 0, 1, 20, 20000); errors.Cause(err) != ErrInvalidTypeTag {
+		t.Fatalf("expected ErrInvalidTypeTag, got %v", err)
+	}
+	if _, err := msg.MapFloatAt(3, 0, 1, 20, 20000); errors.Cause(err) != ErrIndexOutOfBounds {
+		t.Fatalf("expected ErrIndexOutOfBounds, got %v", err)
+	}
+	if _, err := msg.MapFloatAt(0, 1, 1, 20, 20000); errors.Cause(err) != ErrInvalidRange {
+		t.Fatalf("expected ErrInvalidRange, got %v", err)
+	}
+}
+
+func TestMessageMapFloatLogAt(t *testing.T) {
+	for _, testcase := range []struct {
+		In       float32
+		Expected float32
+	}{
+		{In: 0, Expected: 20},
+		{In: 0.5, Expected: 632.4555},
+		{In: 1, Expected: 20000},
+	} {
+		msg := Message{Address: "/freq", Arguments: []Argument{Float(testcase.In)}}
+		f, err := msg.MapFloatLogAt(0, 0, 1, 20, 20000)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !Float(testcase.Expected).NearlyEqual(Float(f), 0.001) {
+			t.Fatalf("(%f) expected %f, got %f", testcase.In, testcase.Expected, f)
+		}
+	}
+	msg := Message{Address: "/freq", Arguments: []Argument{Float(0.5)}}
+	if _, err := msg.MapFloatLogAt(0, 0, 1, 0, 20000); errors.Cause(err) != ErrInvalidRange {
+		t.Fatalf("expected ErrInvalidRange, got %v", err)
+	}
+	if _, err := msg.MapFloatLogAt(0, 0.75, 1, 20, 20000); errors.Cause(err) != ErrOutOfRange {
+		t.Fatalf("expected ErrOutOfRange, got %v", err)
+	}
+}
+
+func TestMessageForEachArg(t *testing.T) {
+	msg := Message{Address: "/foo", Arguments: []Argument{Int(1), String("bar"), Float(2), String("baz")}}
+
+	// Find the first string.
+	found := -1
+	msg.ForEachArg(func(i int, a Argument) bool {
+		if a.Typetag() == TypetagString {
+			found = i
+			return false
+		}
+		return true
+	})
+	if expected, got := 1, found; expected != got {
+		t.Fatalf("expected %d, got %d", expected, got)
+	}
+
+	visited := 0
+	msg.ForEachArg(func(i int, a Argument) bool {
+		visited++
+		return true
+	})
+	if expected, got := 4, visited; expected != got {
+		t.Fatalf("expected %d, got %d", expected, got)
+	}
+}
+
+func TestMessageArgsByType(t *testing.T) {
+	msg := Message{
+		Address: "/mixed",
+		Arguments: []Argument{
+			Int(1),
+			String("a"),
+			Int(2),
+			Float(3),
+			Blob([]byte{4}),
+			String("b"),
+			Bool(true),
+		},
+	}
+	byType := msg.ArgsByType()
+
+	for tt, expected := range map[byte][]Argument{
+		TypetagInt:    {Int(1), Int(2)},
+		TypetagFloat:  {Float(3)},
+		TypetagString: {String("a"), String("b")},
+		TypetagBlob:   {Blob([]byte{4})},
+		TypetagTrue:   {Bool(true)},
+	} {
+		got := byType[tt]
+		if len(expected) != len(got) {
+			t.Fatalf("(typetag %c) expected %d arguments, got %d", tt, len(expected), len(got))
+		}
+		for i := range expected {
+			if !expected[i].Equal(got[i]) {
+				t.Fatalf("(typetag %c) expected %s, got %s", tt, expected[i], got[i])
+			}
+		}
+	}
+	if expected, got := 5, len(byType); expected != got {
+		t.Fatalf("expected %d type tags, got %d", expected, got)
+	}
+}
+
+func TestMessageColorFloatsAt(t *testing.T) {
+	msg := Message{Address: "/light", Arguments: []Argument{Color{R: 255, G: 51, B: 0, A: 102}, Int(1)}}
+
+	r, g, b, a, err := msg.ColorFloatsAt(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, testcase := range []struct {
+		Channel  string
+		Expected float32
+		Got      float32
+	}{
+		{Channel: "r", Expected: 1, Got: r},
+		{Channel: "g", Expected: 0.2, Got: g},
+		{Channel: "b", Expected: 0, Got: b},
+		{Channel: "a", Expected: 0.4, Got: a},
+	} {
+		if testcase.Expected != testcase.Got {
+			t.Fatalf("(%s) expected %f, got %f", testcase.Channel, testcase.Expected, testcase.Got)
+		}
+	}
+	if _, _, _, _, err := msg.ColorFloatsAt(1); errors.Cause(err) != ErrInvalidTypeTag {
+		t.Fatalf("expected ErrInvalidTypeTag, got %v", err)
+	}
+	if _, _, _, _, err := msg.ColorFloatsAt(2); errors.Cause(err) != ErrIndexOutOfBounds {
+		t.Fatalf("expected ErrIndexOutOfBounds, got %v", err)
+	}
+}
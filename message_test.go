@@ -2,9 +2,14 @@ package osc
 
 import (
 	"bytes"
+	"context"
+	"io"
 	"io/ioutil"
 	"net"
+	"runtime"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/pkg/errors"
 )
@@ -44,11 +49,11 @@ func TestMessageEqual(t *testing.T) {
 		m1, m2 := testcase.M1, testcase.M2
 		if testcase.Expected {
 			if !m1.Equal(m2) {
-				t.Fatalf("expected %s to equal %s", m1, m2)
+				t.Fatalf("expected %v to equal %v", m1, m2)
 			}
 		} else {
 			if m1.Equal(m2) {
-				t.Fatalf("expected %s to not equal %s", m1, m2)
+				t.Fatalf("expected %v to not equal %v", m1, m2)
 			}
 		}
 	}
@@ -151,10 +156,64 @@ func TestMatchExactly(t *testing.T) {
 	}
 }
 
+func TestMatchCaseInsensitive(t *testing.T) {
+	for _, testcase := range []struct {
+		Pattern  string
+		Address  string
+		Opts     MatchOptions
+		Expected bool
+	}{
+		{Pattern: "/Synth/Freq", Address: "/synth/freq", Opts: MatchOptions{}, Expected: false},
+		{Pattern: "/Synth/Freq", Address: "/synth/freq", Opts: MatchOptions{CaseInsensitive: true}, Expected: true},
+		{Pattern: "/Synth/F*", Address: "/synth/freq", Opts: MatchOptions{CaseInsensitive: true}, Expected: true},
+		{Pattern: "/Synth/Freq", Address: "/synth/freq", Opts: MatchOptions{Exact: true}, Expected: false},
+		{Pattern: "/Synth/Freq", Address: "/synth/freq", Opts: MatchOptions{Exact: true, CaseInsensitive: true}, Expected: true},
+	} {
+		msg := Message{Address: testcase.Pattern}
+		matched, err := msg.MatchWith(testcase.Address, testcase.Opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if expected, got := testcase.Expected, matched; expected != got {
+			t.Fatalf("(%s %s %+v) expected %t, got %t", testcase.Pattern, testcase.Address, testcase.Opts, expected, got)
+		}
+	}
+}
+
 func TestGetRegex(t *testing.T) {
 	if _, err := GetRegex(`[`); err == nil {
 		t.Fatalf("expected error, got nil")
 	}
+	for _, testcase := range []struct {
+		Pattern  string
+		Address  string
+		Expected bool
+	}{
+		{Pattern: "/synth/*", Address: "/synth/1", Expected: true},
+		{Pattern: "/synth/*", Address: "/synth/1/freq", Expected: false},
+		{Pattern: "/mixer/ch[!0-9]", Address: "/mixer/chx", Expected: true},
+		{Pattern: "/mixer/ch[!0-9]", Address: "/mixer/ch4", Expected: false},
+		{Pattern: "/a|b", Address: "/a|b", Expected: true},
+		{Pattern: "/a|b", Address: "/a", Expected: false},
+		{Pattern: "/a,b", Address: "/a,b", Expected: true},
+	} {
+		re, err := GetRegex(testcase.Pattern)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if expected, got := testcase.Expected, re.MatchString(testcase.Address); expected != got {
+			t.Fatalf("(%s %s) expected %t, got %t", testcase.Pattern, testcase.Address, expected, got)
+		}
+
+		// Pattern sets translate patterns the same way.
+		ps, err := CompilePatternSet([]string{testcase.Pattern})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, got := ps.Match(testcase.Address); testcase.Expected != got {
+			t.Fatalf("(%s %s) expected pattern set match %t, got %t", testcase.Pattern, testcase.Address, testcase.Expected, got)
+		}
+	}
 }
 
 func TestMesssageBytes(t *testing.T) {
@@ -185,6 +244,18 @@ func TestMesssageBytes(t *testing.T) {
 	}
 }
 
+func TestMessageEncodedLen(t *testing.T) {
+	for _, msg := range []Message{
+		{Address: "/foo"},
+		{Address: "/foo/bar", Arguments: []Argument{Int(1), Float(2), Bool(true)}},
+		{Address: "/foo", Arguments: []Argument{String("bar"), Blob([]byte("bazqux"))}},
+	} {
+		if expected, got := len(msg.Bytes()), msg.EncodedLen(); expected != got {
+			t.Fatalf("(%s) expected %d, got %d", msg.Address, expected, got)
+		}
+	}
+}
+
 type errWriter struct {
 	erridx int
 	curr   int
@@ -215,6 +286,41 @@ func TestMessageWriteTo(t *testing.T) {
 	}
 }
 
+func TestMessageMarshalTo(t *testing.T) {
+	msg := Message{
+		Address: "/foo/bar",
+		Arguments: []Argument{
+			Int(-2),
+			Float(3.5),
+			Bool(true),
+			String("baz"),
+			String(""),
+			Blob{1, 2, 3, 4, 5},
+			UInt32(7),
+			Color{R: 1, G: 2, B: 3, A: 4},
+			Int64(-3),
+			Timetag(42),
+		},
+	}
+	expected := msg.Bytes()
+
+	// Fill the buffer with garbage to check that padding is zeroed.
+	buf := bytes.Repeat([]byte{0xff}, len(expected))
+	n, err := msg.MarshalTo(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := buf[:n]; !bytes.Equal(expected, got) {
+		t.Fatalf("expected %q, got %q", expected, got)
+	}
+	if _, err := msg.MarshalTo(buf[:len(buf)-1]); err != io.ErrShortBuffer {
+		t.Fatalf("expected io.ErrShortBuffer, got %v", err)
+	}
+	if allocs := testing.AllocsPerRun(10, func() { _, _ = msg.MarshalTo(buf) }); allocs != 0 {
+		t.Fatalf("expected no allocations, got %f", allocs)
+	}
+}
+
 func TestParseMessage(t *testing.T) {
 	type Input struct {
 		data   []byte
@@ -245,7 +351,7 @@ func TestParseMessage(t *testing.T) {
 					Address: "/foo",
 					Arguments: []Argument{
 						Int(1),
-						Blob([]byte{'b', 'a', 'r', 0}),
+						Blob([]byte{'b', 'a', 'r'}),
 					},
 				},
 			},
@@ -260,7 +366,7 @@ func TestParseMessage(t *testing.T) {
 					[]byte{},
 				),
 			},
-			Expected: Output{Err: errors.New(`read argument 0: typetag "Q": invalid typetag`)},
+			Expected: Output{Err: errors.New(`parsing argument 0 (typetag 'Q'): invalid type tag`)},
 		},
 	} {
 		msg, err := ParseMessage(testcase.Input.data, testcase.Input.sender)
@@ -269,9 +375,444 @@ func TestParseMessage(t *testing.T) {
 				t.Fatalf("(testcase %d) %s", i, err)
 			}
 			if expected, got := testcase.Expected.Message, msg; !expected.Equal(got) {
-				t.Fatalf("(testcase %d) expected %s, got %s", i, expected, got)
+				t.Fatalf("(testcase %d) expected %v, got %v", i, expected, got)
 			}
 		} else {
+			if err == nil {
+				t.Fatalf("(testcase %d) expected error, got nil", i)
+			}
+			if expected, got := testcase.Expected.Err.Error(), err.Error(); expected != got {
+				t.Fatalf("(testcase %d) expected %s, got %s", i, expected, got)
+			}
+		}
+	}
+}
+
+func TestParseMessageArgumentError(t *testing.T) {
+	// The message is truncated after its second argument.
+	data := Message{Address: "/foo", Arguments: []Argument{Int(1), Int(2), Float(3), Int(4), Int(5)}}.Bytes()
+	data = data[:len(data)-12]
+
+	_, err := ParseMessage(data, nil)
+	if errors.Cause(err) != io.ErrShortBuffer {
+		t.Fatalf("expected io.ErrShortBuffer, got %v", err)
+	}
+	if expected, got := "parsing argument 2 (typetag 'f'): short buffer", err.Error(); expected != got {
+		t.Fatalf("expected %s, got %s", expected, got)
+	}
+}
+
+func TestParseMessageN(t *testing.T) {
+	msgs := []Message{
+		{Address: "/foo"},
+		{Address: "/foo/bar", Arguments: []Argument{Int(1), Float(2), Bool(true)}},
+		{Address: "/baz", Arguments: []Argument{String("bar"), Blob([]byte("bazqux"))}},
+	}
+	var data []byte
+	for _, msg := range msgs {
+		data = append(data, msg.Bytes()...)
+	}
+	for i, expected := range msgs {
+		got, n, err := ParseMessageN(data, nil)
+		if err != nil {
+			t.Fatalf("(message %d) %s", i, err)
+		}
+		if expected, got := len(expected.Bytes()), n; expected != got {
+			t.Fatalf("(message %d) expected %d bytes consumed, got %d", i, expected, got)
+		}
+		if !expected.Equal(got) {
+			t.Fatalf("(message %d) expected %v, got %v", i, expected, got)
+		}
+		data = data[n:]
+	}
+	if expected, got := 0, len(data); expected != got {
+		t.Fatalf("expected %d bytes left, got %d", expected, got)
+	}
+}
+
+func TestMessageWireLength(t *testing.T) {
+	for i, msg := range []Message{
+		{Address: "/foo"},
+		{Address: "/foo/bar", Arguments: []Argument{Int(1), Float(2), Bool(true)}},
+		{Address: "/baz", Arguments: []Argument{String("bar"), Blob([]byte("bazqux"))}},
+	} {
+		if expected, got := msg.EncodedLen(), msg.WireLength(); expected != got {
+			t.Fatalf("(message %d) expected wire length %d, got %d", i, expected, got)
+		}
+		data := msg.Bytes()
+
+		parsed, err := ParseMessage(data, nil)
+		if err != nil {
+			t.Fatalf("(message %d) %s", i, err)
+		}
+		if expected, got := len(data), parsed.WireLength(); expected != got {
+			t.Fatalf("(message %d) expected wire length %d, got %d", i, expected, got)
+		}
+
+		// The wire length does not change with the message.
+		parsed.Arguments = append(parsed.Arguments, String("more"))
+		if expected, got := len(data), parsed.WireLength(); expected != got {
+			t.Fatalf("(message %d) expected wire length %d, got %d", i, expected, got)
+		}
+	}
+}
+
+func TestParseMessageInto(t *testing.T) {
+	var (
+		msg    = Message{Arguments: make([]Argument, 0, 4)}
+		first  = Message{Address: "/foo", Arguments: []Argument{Int(1), Float(2), String("three")}}
+		second = Message{Address: "/bar", Arguments: []Argument{Int(4)}}
+	)
+	if err := ParseMessageInto(first.Bytes(), nil, &msg); err != nil {
+		t.Fatal(err)
+	}
+	if !first.Equal(msg) {
+		t.Fatalf("expected %v, got %v", first, msg)
+	}
+	backing := &msg.Arguments[:1][0]
+
+	if err := ParseMessageInto(second.Bytes(), nil, &msg); err != nil {
+		t.Fatal(err)
+	}
+	if !second.Equal(msg) {
+		t.Fatalf("expected %v, got %v", second, msg)
+	}
+	if &msg.Arguments[0] != backing {
+		t.Fatal("expected the arguments slice to be reused")
+	}
+
+	// State from dispatching the previous message is reset.
+	msg.timetag = FromTime(time.Unix(0, 0))
+	msg.handlerContext = &HandlerContext{}
+	msg = msg.WithContext(context.TODO())
+	if err := ParseMessageInto(second.Bytes(), nil, &msg); err != nil {
+		t.Fatal(err)
+	}
+	if msg.timetag != 0 || msg.HandlerContext() != nil {
+		t.Fatalf("expected timetag and handler context to be reset, got %s and %v", msg.timetag, msg.HandlerContext())
+	}
+	if msg.Context() != context.Background() {
+		t.Fatalf("expected the context to be reset, got %v", msg.Context())
+	}
+	if err := ParseMessageInto(badPacket{}.Bytes(), nil, &msg); err == nil {
+		t.Fatal("expected error, got nil")
+	}
+}
+
+func TestParseMessageWithTrace(t *testing.T) {
+	type traced struct {
+		tag    byte
+		offset int
+		arg    Argument
+	}
+	var (
+		got []traced
+		msg = Message{
+			Address:   "/foo",
+			Arguments: []Argument{Int(1), String("bar"), Bool(true), Float(2)},
+		}
+		opts = ParseOptions{
+			Trace: func(tag byte, offset int, arg Argument) {
+				got = append(got, traced{tag: tag, offset: offset, arg: arg})
+			},
+		}
+	)
+	if _, err := ParseMessageWith(msg.Bytes(), nil, opts); err != nil {
+		t.Fatal(err)
+	}
+	// The address takes 8 bytes and the type tags take 8 bytes.
+	expected := []traced{
+		{tag: TypetagInt, offset: 16, arg: Int(1)},
+		{tag: TypetagString, offset: 20, arg: String("bar")},
+		{tag: TypetagTrue, offset: 24, arg: Bool(true)},
+		{tag: TypetagFloat, offset: 24, arg: Float(2)},
+	}
+	if len(expected) != len(got) {
+		t.Fatalf("expected %d traced arguments, got %d", len(expected), len(got))
+	}
+	for i := range expected {
+		if expected[i].tag != got[i].tag || expected[i].offset != got[i].offset || !expected[i].arg.Equal(got[i].arg) {
+			t.Fatalf("(argument %d) expected %+v, got %+v", i, expected[i], got[i])
+		}
+	}
+}
+
+func TestMatchScore(t *testing.T) {
+	for _, testcase := range []struct {
+		Pattern  string
+		Address  string
+		Expected int
+	}{
+		{Pattern: "/synth/1/freq", Address: "/synth/1/freq", Expected: 1000},
+		{Pattern: "/synth/?/freq", Address: "/synth/1/freq", Expected: 210},
+		{Pattern: "/synth/{1,2}/freq", Address: "/synth/1/freq", Expected: 205},
+		{Pattern: "/synth/*/freq", Address: "/synth/1/freq", Expected: 201},
+		{Pattern: "/*/*/*", Address: "/synth/1/freq", Expected: 3},
+		{Pattern: "/synth/*", Address: "/synth/1/freq", Expected: 0},
+		{Pattern: "/synth/2/freq", Address: "/synth/1/freq", Expected: 0},
+	} {
+		score, err := MatchScore(testcase.Pattern, testcase.Address)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if expected, got := testcase.Expected, score; expected != got {
+			t.Fatalf("(%s) expected %d, got %d", testcase.Pattern, expected, got)
+		}
+	}
+	if _, err := MatchScore("/synth/[1/freq", "/synth/1/freq"); err == nil {
+		t.Fatal("expected error, got nil")
+	}
+}
+
+func TestMessageArgumentBytes(t *testing.T) {
+	msg := Message{
+		Address:   "/foo",
+		Arguments: []Argument{Int(1), String("bar"), Bool(false), Float(2.5), Blob{1, 2, 3, 4}},
+	}
+	data, err := msg.ArgumentBytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected, got := msg.Bytes()[len(ToBytes(msg.Address))+len(msg.Typetags()):], data; !bytes.Equal(expected, got) {
+		t.Fatalf("expected %q, got %q", expected, got)
+	}
+	args, err := ReadArguments([]byte(msg.TypetagString()), data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := (Message{Address: msg.Address, Arguments: args}); !msg.Equal(got) {
+		t.Fatalf("expected %v, got %v", msg, got)
+	}
+	if _, err := (Message{Address: "/foo", Arguments: []Argument{nil}}).ArgumentBytes(); err == nil {
+		t.Fatal("expected error, got nil")
+	}
+}
+
+func TestParseMessageWithMaxLen(t *testing.T) {
+	var (
+		long = strings.Repeat("x", DefaultMaxStringLen+1)
+		blob = make(Blob, 100)
+	)
+	// The default string limit also applies to ParseMessage.
+	if _, err := ParseMessage(Message{Address: "/foo", Arguments: []Argument{String(long)}}.Bytes(), nil); errors.Cause(err) != ErrStringTooLarge {
+		t.Fatalf("expected ErrStringTooLarge, got %v", err)
+	}
+	for i, testcase := range []struct {
+		Opts     ParseOptions
+		Msg      Message
+		Expected error
+	}{
+		{Opts: ParseOptions{}, Msg: Message{Address: "/foo", Arguments: []Argument{String(long[1:])}}},
+		{Opts: ParseOptions{MaxStringLen: -1}, Msg: Message{Address: "/foo", Arguments: []Argument{String(long)}}},
+		{Opts: ParseOptions{MaxStringLen: 3}, Msg: Message{Address: "/foo", Arguments: []Argument{String("bar")}}},
+		{Opts: ParseOptions{MaxStringLen: 3}, Msg: Message{Address: "/foo", Arguments: []Argument{String("barr")}}, Expected: ErrStringTooLarge},
+		// Addresses are not limited.
+		{Opts: ParseOptions{MaxStringLen: 3}, Msg: Message{Address: "/" + long}},
+		{Opts: ParseOptions{}, Msg: Message{Address: "/foo", Arguments: []Argument{blob}}},
+		{Opts: ParseOptions{MaxBlobLen: 100}, Msg: Message{Address: "/foo", Arguments: []Argument{blob}}},
+		{Opts: ParseOptions{MaxBlobLen: 99}, Msg: Message{Address: "/foo", Arguments: []Argument{Int(1), blob}}, Expected: ErrBlobTooLarge},
+	} {
+		msg, err := ParseMessageWith(testcase.Msg.Bytes(), nil, testcase.Opts)
+		if expected, got := testcase.Expected, errors.Cause(err); expected != got {
+			t.Fatalf("(testcase %d) expected %v, got %v", i, expected, got)
+		}
+		if err == nil && !testcase.Msg.Equal(msg) {
+			t.Fatalf("(testcase %d) expected %v, got %v", i, testcase.Msg, msg)
+		}
+	}
+}
+
+func TestParseMessageWithMaxArguments(t *testing.T) {
+	args := func(n int) []Argument {
+		args := make([]Argument, n)
+		for i := range args {
+			args[i] = Float(i)
+		}
+		return args
+	}
+	for i, testcase := range []struct {
+		Opts     ParseOptions
+		Msg      Message
+		Expected error
+	}{
+		{Msg: Message{Address: "/foo", Arguments: args(DefaultMaxArguments)}},
+		{Msg: Message{Address: "/foo", Arguments: args(DefaultMaxArguments + 1)}, Expected: ErrTooManyArguments},
+		{Opts: ParseOptions{MaxArguments: 2}, Msg: Message{Address: "/foo", Arguments: args(2)}},
+		{Opts: ParseOptions{MaxArguments: 2}, Msg: Message{Address: "/foo", Arguments: args(3)}, Expected: ErrTooManyArguments},
+		{Opts: ParseOptions{MaxArguments: -1}, Msg: Message{Address: "/foo", Arguments: args(1000)}},
+	} {
+		if _, err := ParseMessageWith(testcase.Msg.Bytes(), nil, testcase.Opts); errors.Cause(err) != testcase.Expected {
+			t.Fatalf("(case %d) expected %v, got %v", i, testcase.Expected, err)
+		}
+	}
+
+	// An array counts as a single argument.
+	for _, testcase := range []struct {
+		Typetags string
+		Expected error
+	}{
+		{Typetags: "i[fff]", Expected: nil},
+		{Typetags: "i[f[ff]]", Expected: nil},
+		{Typetags: "i[ff]i", Expected: ErrTooManyArguments},
+	} {
+		err := ParseOptions{MaxArguments: 2}.checkArgumentCount([]byte(testcase.Typetags))
+		if errors.Cause(err) != testcase.Expected {
+			t.Fatalf("(%s) expected %v, got %v", testcase.Typetags, testcase.Expected, err)
+		}
+	}
+}
+
+func TestParseMessageWithPreallocLimit(t *testing.T) {
+	// Lots of int type tags without any argument data.
+	data := append(ToBytes("/foo"), ToBytes(","+strings.Repeat("i", 1<<16))...)
+
+	allocated := func(opts ParseOptions) uint64 {
+		var before, after runtime.MemStats
+		runtime.ReadMemStats(&before)
+		if _, err := ParseMessageWith(data, nil, opts); err == nil {
+			t.Fatal("expected error, got nil")
+		}
+		runtime.ReadMemStats(&after)
+		return after.TotalAlloc - before.TotalAlloc
+	}
+	if got := allocated(ParseOptions{MaxArguments: -1}); got < 1<<20 {
+		t.Fatalf("expected at least 1MB to be allocated without a limit, got %d bytes", got)
+	}
+	if got := allocated(ParseOptions{MaxArguments: -1, PreallocLimit: 8}); got > 1<<19 {
+		t.Fatalf("expected at most 512KB to be allocated with a limit, got %d bytes", got)
+	}
+
+	// The limit does not limit the number of arguments.
+	msg := Message{Address: "/foo", Arguments: []Argument{Int(1), Int(2), Int(3)}}
+	got, err := ParseMessageWith(msg.Bytes(), nil, ParseOptions{PreallocLimit: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !msg.Equal(got) {
+		t.Fatalf("expected %v, got %v", msg, got)
+	}
+}
+
+func TestParseMessageWithReverseArgs(t *testing.T) {
+	for _, c := range []struct {
+		Sent     []Argument
+		Expected []Argument
+	}{
+		{Sent: []Argument{}, Expected: []Argument{}},
+		{Sent: []Argument{Int(1)}, Expected: []Argument{Int(1)}},
+		{
+			Sent:     []Argument{String("gain"), Float(0.5), Int(3)},
+			Expected: []Argument{Int(3), Float(0.5), String("gain")},
+		},
+		{
+			Sent:     []Argument{Bool(true), Blob{1, 2}, Int(1), Bool(false)},
+			Expected: []Argument{Bool(false), Int(1), Blob{1, 2}, Bool(true)},
+		},
+	} {
+		sent := Message{Address: "/device", Arguments: c.Sent}
+		got, err := ParseMessageWith(sent.Bytes(), nil, ParseOptions{ReverseArgs: true})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if expected := (Message{Address: "/device", Arguments: c.Expected}); !expected.Equal(got) {
+			t.Fatalf("expected %v, got %v", expected, got)
+		}
+	}
+}
+
+func TestMessageRebase(t *testing.T) {
+	sender := &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1), Port: 57120}
+	msg := Message{Address: "/synth/1/freq", Arguments: []Argument{Float(440)}, Sender: sender, Seq: 3}
+	for _, c := range []struct {
+		oldPrefix, newPrefix, expected string
+	}{
+		{"/synth", "/rack/a", "/rack/a/1/freq"},
+		{"/synth/", "/rack/a/", "/rack/a/1/freq"},
+		{"/synth/1", "", "/freq"},
+		{"", "/rack", "/rack/synth/1/freq"},
+		{"/", "/rack", "/rack/synth/1/freq"},
+		{"/synth/1/freq", "/gain", "/gain"},
+		{"/synth/1/freq", "", "/"},
+	} {
+		got, err := msg.Rebase(c.oldPrefix, c.newPrefix)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if expected := (Message{Address: c.expected, Arguments: msg.Arguments}); !expected.Equal(got) {
+			t.Fatalf("rebase %q to %q: expected %v, got %v", c.oldPrefix, c.newPrefix, expected, got)
+		}
+		if got.Sender != sender || got.Seq != msg.Seq {
+			t.Fatalf("rebase %q to %q: expected sender %v and seq %d, got %v and %d", c.oldPrefix, c.newPrefix, sender, msg.Seq, got.Sender, got.Seq)
+		}
+	}
+	for _, oldPrefix := range []string{"/rack", "/syn", "/synth/1/freq/x"} {
+		if _, err := msg.Rebase(oldPrefix, "/rack"); errors.Cause(err) != ErrPrefixMismatch {
+			t.Fatalf("prefix %q: expected ErrPrefixMismatch, got %v", oldPrefix, err)
+		}
+	}
+}
+
+func TestEqualWithTolerance(t *testing.T) {
+	msg := Message{Address: "/foo", Arguments: []Argument{Float(0.3), Int(1), String("bar")}}
+	for _, testcase := range []struct {
+		Other    Message
+		Expected bool
+	}{
+		{Other: Message{Address: "/foo", Arguments: []Argument{Float(0.1) + Float(0.2), Int(1), String("bar")}}, Expected: true},
+		{Other: Message{Address: "/foo", Arguments: []Argument{Float(0.3001), Int(1), String("bar")}}, Expected: true},
+		{Other: Message{Address: "/foo", Arguments: []Argument{Float(0.31), Int(1), String("bar")}}},
+		{Other: Message{Address: "/foo", Arguments: []Argument{Int(0), Int(1), String("bar")}}},
+		{Other: Message{Address: "/foo", Arguments: []Argument{Float(0.3), Int(2), String("bar")}}},
+		{Other: Message{Address: "/foo", Arguments: []Argument{Float(0.3), Int(1)}}},
+		{Other: Message{Address: "/bar", Arguments: []Argument{Float(0.3), Int(1), String("bar")}}},
+	} {
+		if expected, got := testcase.Expected, EqualWithTolerance(msg, testcase.Other, 0.001); expected != got {
+			t.Fatalf("(%v) expected %t, got %t", testcase.Other, expected, got)
+		}
+	}
+}
+
+func TestMessageSignatureBytes(t *testing.T) {
+	msg := Message{Address: "/foo", Arguments: []Argument{Int(1), Float(2), String("bar"), Blob{1}, Bool(true)}}
+	if expected, got := strings.TrimPrefix(msg.TypetagString(), ","), string(msg.SignatureBytes()); expected != got {
+		t.Fatalf("expected %s, got %s", expected, got)
+	}
+	parsed, err := ParseMessage(msg.Bytes(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected, got := "ifsbT", string(parsed.SignatureBytes()); expected != got {
+		t.Fatalf("expected %s, got %s", expected, got)
+	}
+	if allocs := testing.AllocsPerRun(10, func() { _ = parsed.SignatureBytes() }); allocs != 0 {
+		t.Fatalf("expected no allocations, got %f", allocs)
+	}
+	// Changing the arguments after parsing is reflected.
+	parsed.Arguments[0] = Float(1)
+	if expected, got := "ffsbT", string(parsed.SignatureBytes()); expected != got {
+		t.Fatalf("expected %s, got %s", expected, got)
+	}
+	parsed.Arguments = parsed.Arguments[:2]
+	if expected, got := "ff", string(parsed.SignatureBytes()); expected != got {
+		t.Fatalf("expected %s, got %s", expected, got)
+	}
+	if expected, got := "", string((Message{Address: "/foo"}).SignatureBytes()); expected != got {
+		t.Fatalf("expected %q, got %q", expected, got)
+	}
+}
+
+func BenchmarkMessageSignatureBytes(b *testing.B) {
+	msg := Message{Address: "/foo", Arguments: []Argument{Int(1), Float(2), String("bar")}}
+	parsed, err := ParseMessage(msg.Bytes(), nil)
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		if len(parsed.SignatureBytes()) != 3 {
+			b.Fatal("expected 3 type tags")
 		}
 	}
 }
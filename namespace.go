@@ -0,0 +1,105 @@
+package osc
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// TypedHandler is a handler that documents the type tags of the messages it handles.
+type TypedHandler interface {
+	MessageHandler
+
+	// Typetags returns the type tag string of the messages the handler expects, e.g. ",ff".
+	Typetags() string
+}
+
+// namespaceNode is a node in the tree of a dispatcher's addresses.
+type namespaceNode struct {
+	children map[string]*namespaceNode
+	handler  MessageHandler // Nil if the node is not a method.
+}
+
+// PrintNamespaceTree writes a tree of the dispatcher's addresses to w, e.g.
+//
+//	/
+//	└── synth/
+//	    ├── 1/
+//	    │   ├── freq
+//	    │   └── gain
+//	    └── 2/
+//	        └── freq
+//
+// Handlers that implement TypedHandler have their type tags printed next to their address.
+// Handlers added with AddHandlerForRegex do not have an address, so they are not printed.
+func PrintNamespaceTree(w io.Writer, d Dispatcher) error {
+	root := &namespaceNode{children: map[string]*namespaceNode{}}
+	for address, handler := range d {
+		if isRegexHandler(handler) {
+			continue
+		}
+		node := root
+		for _, segment := range strings.Split(strings.Trim(address, "/"), "/") {
+			child, ok := node.children[segment]
+			if !ok {
+				child = &namespaceNode{children: map[string]*namespaceNode{}}
+				node.children[segment] = child
+			}
+			node = child
+		}
+		node.handler = handler
+	}
+	if _, err := fmt.Fprintln(w, "/"); err != nil {
+		return err
+	}
+	return root.print(w, "")
+}
+
+// namespaceEntry is a line in the namespace tree.
+type namespaceEntry struct {
+	name string
+	node *namespaceNode
+	dir  bool
+}
+
+// print writes the node's children to w.
+func (n *namespaceNode) print(w io.Writer, prefix string) error {
+	entries := []namespaceEntry{}
+	for name, child := range n.children {
+		// A method can also be the parent of other methods.
+		if child.handler != nil {
+			entries = append(entries, namespaceEntry{name: name, node: child})
+		}
+		if len(child.children) > 0 {
+			entries = append(entries, namespaceEntry{name: name, node: child, dir: true})
+		}
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].name != entries[j].name {
+			return entries[i].name < entries[j].name
+		}
+		return !entries[i].dir
+	})
+	for i, e := range entries {
+		branch, indent := "├── ", "│   "
+		if i == len(entries)-1 {
+			branch, indent = "└── ", "    "
+		}
+		line := prefix + branch + e.name
+		if e.dir {
+			line += "/"
+		} else if th, ok := e.node.handler.(TypedHandler); ok {
+			line += " " + th.Typetags()
+		}
+		if _, err := fmt.Fprintln(w, line); err != nil {
+			return err
+		}
+		if e.dir {
+			if err := e.node.print(w, prefix+indent); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
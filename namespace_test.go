@@ -0,0 +1,55 @@
+package osc
+
+import (
+	"bytes"
+	"regexp"
+	"testing"
+)
+
+type typedMethod struct {
+	Method
+	typetags string
+}
+
+func (tm typedMethod) Typetags() string { return tm.typetags }
+
+func TestPrintNamespaceTree(t *testing.T) {
+	var (
+		buf     = &bytes.Buffer{}
+		handler = Method(func(msg Message) error { return nil })
+		d       = Dispatcher{
+			"/synth/1/freq": typedMethod{Method: handler, typetags: ",f"},
+			"/synth/1/gain": handler,
+			"/synth/2/freq": handler,
+			"/mixer":        handler,
+			"/mixer/mute":   typedMethod{Method: handler, typetags: ",T"},
+		}
+	)
+	d.AddHandlerForRegex(regexp.MustCompile(`^/synth/[0-9]+/pan$`), handler)
+	if err := PrintNamespaceTree(buf, d); err != nil {
+		t.Fatal(err)
+	}
+	expected := `/
+├── mixer
+├── mixer/
+│   └── mute ,T
+└── synth/
+    ├── 1/
+    │   ├── freq ,f
+    │   └── gain
+    └── 2/
+        └── freq
+`
+	if got := buf.String(); expected != got {
+		t.Fatalf("expected\n%s\ngot\n%s", expected, got)
+	}
+}
+
+func TestPrintNamespaceTreeError(t *testing.T) {
+	d := Dispatcher{"/foo/bar": Method(func(msg Message) error { return nil })}
+	for i := 1; i <= 3; i++ {
+		if err := PrintNamespaceTree(&errWriter{erridx: i}, d); err == nil {
+			t.Fatalf("(write %d) expected error, got nil", i)
+		}
+	}
+}
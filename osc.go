@@ -4,8 +4,11 @@ import (
 	"bytes"
 	"context"
 	"encoding/binary"
+	"io"
 	"net"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/pkg/errors"
 )
@@ -58,6 +61,11 @@ func Pad(b []byte) []byte {
 	return b
 }
 
+// paddedLen returns n rounded up to the next multiple of 4.
+func paddedLen(n int) int {
+	return (n + 3) &^ 3
+}
+
 // ReadString reads a string from a byte slice.
 // If the byte slice does not have any null bytes,
 // then one is appended to the end.
@@ -82,6 +90,8 @@ func ReadString(data []byte) (string, int64) {
 }
 
 // ReadBlob reads a blob of the given length from the given slice of bytes.
+// The returned bytes include the padding that follows the blob,
+// and the returned length is the number of bytes consumed.
 func ReadBlob(length int32, data []byte) ([]byte, int64) {
 	l := length
 	if length > int32(len(data)) {
@@ -101,6 +111,20 @@ func ReadBlob(length int32, data []byte) ([]byte, int64) {
 type Incoming struct {
 	Data   []byte
 	Sender net.Addr
+	Seq    uint64
+
+	// packet is the packet parsed from Data, if it was parsed already.
+	packet Packet
+
+	// done is called when the data has been handled.
+	done func()
+}
+
+// finish marks the data as handled.
+func (incoming Incoming) finish() {
+	if incoming.done != nil {
+		incoming.done()
+	}
 }
 
 type netWriter interface {
@@ -112,7 +136,10 @@ func checkDispatcher(dispatcher Dispatcher) error {
 	if dispatcher == nil {
 		return ErrNilDispatcher
 	}
-	for addr := range dispatcher {
+	for addr, handler := range dispatcher {
+		if isRegexHandler(handler) {
+			continue
+		}
 		if err := ValidateAddress(addr); err != nil {
 			return err
 		}
@@ -120,15 +147,85 @@ func checkDispatcher(dispatcher Dispatcher) error {
 	return nil
 }
 
-// readSender knows how to read bytes and return the net.Addr
-// of the sender of the bytes.
-type readSender interface {
-	CloseChan() <-chan struct{}
-	Context() context.Context
-	read([]byte) (int, net.Addr, error)
+// inflight keeps track of the packets that are being handled by workers
+// so that a connection can be shut down gracefully.
+// The zero value is ready to use.
+type inflight struct {
+	mu       sync.Mutex
+	active   int
+	draining bool
+	idle     chan struct{}
+}
+
+// start registers a packet that is about to be handled.
+// It returns false if the connection is being shut down.
+func (f *inflight) start() bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.draining {
+		return false
+	}
+	f.active++
+	return true
+}
+
+// done marks a packet as handled.
+func (f *inflight) done() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.active--
+	if f.draining && f.active == 0 {
+		close(f.idle)
+	}
+}
+
+// drain stops new packets from being handled and returns a channel
+// that is closed once all the packets that are being handled are done.
+func (f *inflight) drain() <-chan struct{} {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if !f.draining {
+		f.draining = true
+		f.idle = make(chan struct{})
+		if f.active == 0 {
+			close(f.idle)
+		}
+	}
+	return f.idle
+}
+
+// isDraining returns true if the connection is being shut down.
+func (f *inflight) isDraining() bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.draining
+}
+
+// ParseErrorHandler handles a packet that could not be parsed.
+type ParseErrorHandler func(data []byte, sender net.Addr, err error)
+
+// DispatchErrorHandler handles an error returned by the handlers of a packet,
+// or an error wrapping ErrHandlerTimeout if they took too long.
+type DispatchErrorHandler func(p Packet, err error)
+
+// serveOptions control how a connection serves packets.
+type serveOptions struct {
+	match           MatchOptions
+	transform       Transformer
+	parse           ParseOptions
+	onParseError    ParseErrorHandler
+	handlerTimeout  time.Duration
+	onDispatchError DispatchErrorHandler
 }
 
-func serve(r readSender, numWorkers int, exactMatch bool, dispatcher Dispatcher) error {
+// serve reads packets from t and dispatches them with numWorkers workers
+// until t returns io.EOF, the context is done, or there is an error.
+// handlers keeps track of the packets that are being handled,
+// serve waits for them before it returns after io.EOF.
+func serve(ctx context.Context, t Transport, handlers *inflight, numWorkers int, opts serveOptions, dispatcher Dispatcher) error {
 	/*
 		if err := checkDispatcher(dispatcher); err != nil {
 			return err
@@ -137,34 +234,67 @@ func serve(r readSender, numWorkers int, exactMatch bool, dispatcher Dispatcher)
 	var (
 		errChan = make(chan error)
 		ready   = make(chan Worker, numWorkers)
+		stopped = make(chan struct{})
 	)
 	for i := 0; i < numWorkers; i++ {
 		go Worker{
-			DataChan:   make(chan Incoming),
-			Dispatcher: dispatcher,
-			ErrChan:    errChan,
-			Ready:      ready,
-			ExactMatch: exactMatch,
+			DataChan:        make(chan Incoming),
+			Dispatcher:      dispatcher,
+			ErrChan:         errChan,
+			Ready:           ready,
+			ExactMatch:      opts.match.Exact,
+			CaseInsensitive: opts.match.CaseInsensitive,
+			Transform:       opts.transform,
+			ParseOptions:    opts.parse,
+			OnParseError:    opts.onParseError,
+			HandlerTimeout:  opts.handlerTimeout,
+			OnDispatchError: opts.onDispatchError,
 		}.Run()
 	}
-	go workerLoop(r, ready, errChan)
+	go func() {
+		readLoop(t, handlers, ready, errChan, opts.onParseError)
+		close(stopped)
+	}()
 
-	// If the connection is closed or the context is canceled then stop serving.
+	// If the transport is closed or the context is canceled then stop serving.
 	select {
 	case err := <-errChan:
-		return errors.Wrap(err, "error serving udp")
-	case <-r.CloseChan():
-	case <-r.Context().Done():
-		return r.Context().Err()
+		return errors.Wrap(err, "error serving "+transportName(t))
+	case <-stopped:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	// Wait for the packets that are being handled,
+	// their handlers may still return an error.
+	select {
+	case err := <-errChan:
+		return errors.Wrap(err, "error serving "+transportName(t))
+	case <-handlers.drain():
+	case <-ctx.Done():
+		return ctx.Err()
 	}
 	return nil
 }
 
-func workerLoop(r readSender, ready chan Worker, errChan chan error) {
+// readLoop reads packets from t and hands them to the workers until t returns io.EOF.
+// Packets that can not be parsed are passed to onParseError if it is not nil.
+func readLoop(t Transport, handlers *inflight, ready chan Worker, errChan chan error, onParseError ParseErrorHandler) {
+	var seq uint64
+
 	for {
-		data := make([]byte, bufSize)
-		_, sender, err := r.read(data)
+		p, sender, err := t.ReadPacket()
+		if err == io.EOF {
+			return
+		}
+		if pe, ok := err.(*packetError); ok && onParseError != nil {
+			onParseError(pe.data, pe.sender, pe.err)
+			continue
+		}
 		if err != nil {
+			// Shutdown unblocks the read with a deadline.
+			if handlers.isDraining() {
+				return
+			}
 			// Tried non-blocking select on closeChan right before ReadFromUDP
 			// but that didn't stop us from reading a closed connection. [briansorahan]
 			if strings.Contains(err.Error(), "use of closed network connection") {
@@ -174,10 +304,30 @@ func workerLoop(r readSender, ready chan Worker, errChan chan error) {
 			return
 		}
 
+		// Don't start handling anything new if we are shutting down.
+		if !handlers.start() {
+			return
+		}
+
+		seq++
+
 		// Get the next worker.
 		worker := <-ready
 
-		// Assign them the data we just read.
-		worker.DataChan <- Incoming{Data: data, Sender: sender}
+		// Assign them the packet we just read.
+		worker.DataChan <- Incoming{
+			Sender: sender,
+			Seq:    seq,
+			packet: p,
+			done:   handlers.done,
+		}
+	}
+}
+
+// transportName names the transport in the errors returned by serve.
+func transportName(t Transport) string {
+	if la, ok := t.(interface{ LocalAddr() net.Addr }); ok && la.LocalAddr() != nil {
+		return la.LocalAddr().Network()
 	}
+	return "transport"
 }
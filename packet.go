@@ -0,0 +1,72 @@
+package osc
+
+import (
+	"net"
+
+	"github.com/pkg/errors"
+)
+
+// ParsePacketN parses a message or a bundle from a slice of bytes
+// and returns the number of bytes the packet occupied.
+// Bundles do not encode their own size, so a bundle occupies the rest of data.
+func ParsePacketN(data []byte, sender net.Addr) (Packet, int, error) {
+	if len(data) == 0 {
+		return nil, 0, ErrParse
+	}
+	switch data[0] {
+	case BundleTag[0]:
+		b, err := ParseBundle(data, sender)
+		if err != nil {
+			return nil, 0, err
+		}
+		return b, len(data), nil
+	case MessageChar:
+		return ParseMessageN(data, sender)
+	default:
+		return nil, 0, ErrParse
+	}
+}
+
+// ParsePacketAt parses a message or a bundle that starts at offset in data,
+// and returns the offset right after the packet.
+// This helps with iterating over packets embedded in a larger container.
+// See ParsePacketN for how much of data a bundle occupies.
+func ParsePacketAt(data []byte, offset int, sender net.Addr) (Packet, int, error) {
+	if offset < 0 || offset > len(data) {
+		return nil, offset, errors.Wrapf(ErrIndexOutOfBounds, "offset %d", offset)
+	}
+	p, n, err := ParsePacketN(data[offset:], sender)
+	if err != nil {
+		return nil, offset, errors.Wrapf(err, "parse packet at offset %d", offset)
+	}
+	return p, offset + n, nil
+}
+
+// parsePacket parses a message or a bundle.
+func parsePacket(data []byte, sender net.Addr) (Packet, error) {
+	p, _, err := ParsePacketN(data, sender)
+	return p, err
+}
+
+// parsePacketWith parses a message or a bundle using the given options.
+func parsePacketWith(data []byte, sender net.Addr, opts ParseOptions) (Packet, error) {
+	if len(data) == 0 {
+		return nil, ErrParse
+	}
+	switch data[0] {
+	case BundleTag[0]:
+		b, err := ParseBundleWith(data, sender, opts)
+		if err != nil {
+			return nil, err
+		}
+		return b, nil
+	case MessageChar:
+		msg, err := ParseMessageWith(data, sender, opts)
+		if err != nil {
+			return nil, err
+		}
+		return msg, nil
+	default:
+		return nil, ErrParse
+	}
+}
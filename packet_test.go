@@ -0,0 +1,54 @@
+package osc
+
+import (
+	"testing"
+
+	"github.com/pkg/errors"
+)
+
+func TestParsePacketAt(t *testing.T) {
+	var (
+		header  = []byte{0xca, 0xfe, 0xba, 0xbe, 0, 0}
+		packets = []Packet{
+			Message{Address: "/foo", Arguments: []Argument{Int(1)}},
+			Message{Address: "/bar", Arguments: []Argument{String("baz")}},
+			Bundle{Timetag: Immediately, Packets: []Packet{Message{Address: "/qux"}}},
+		}
+		data = header
+	)
+	for _, p := range packets {
+		data = append(data, p.Bytes()...)
+	}
+	offset := len(header)
+	for i, expected := range packets {
+		p, next, err := ParsePacketAt(data, offset, nil)
+		if err != nil {
+			t.Fatalf("(packet %d) %s", i, err)
+		}
+		if !expected.Equal(p) {
+			t.Fatalf("(packet %d) expected %v, got %v", i, expected, p)
+		}
+		if expected, got := offset+len(expected.Bytes()), next; expected != got {
+			t.Fatalf("(packet %d) expected offset %d, got %d", i, expected, got)
+		}
+		offset = next
+	}
+	if expected, got := len(data), offset; expected != got {
+		t.Fatalf("expected offset %d, got %d", expected, got)
+	}
+}
+
+func TestParsePacketAtErrors(t *testing.T) {
+	data := Message{Address: "/foo"}.Bytes()
+
+	for _, offset := range []int{-1, len(data) + 1} {
+		if _, _, err := ParsePacketAt(data, offset, nil); errors.Cause(err) != ErrIndexOutOfBounds {
+			t.Fatalf("(offset %d) expected ErrIndexOutOfBounds, got %v", offset, err)
+		}
+	}
+	for _, offset := range []int{1, len(data)} {
+		if _, _, err := ParsePacketAt(data, offset, nil); errors.Cause(err) != ErrParse {
+			t.Fatalf("(offset %d) expected ErrParse, got %v", offset, err)
+		}
+	}
+}
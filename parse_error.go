@@ -0,0 +1,69 @@
+package osc
+
+import (
+	"encoding/hex"
+	"fmt"
+
+	"github.com/pkg/errors"
+)
+
+// parseErrorInputLen is the number of input bytes kept by a ParseError.
+const parseErrorInputLen = 16
+
+// ParseError is returned when an argument of a message can not be parsed.
+// It tells where in the input parsing failed, which helps with debugging malformed messages.
+type ParseError struct {
+	// Offset is the position in the input of the argument that could not be parsed.
+	Offset int
+
+	// Typetag is the type tag of the argument that could not be parsed.
+	Typetag byte
+
+	// Len is the length of the input.
+	Len int
+
+	// Input holds up to the first 16 bytes of the input.
+	Input []byte
+
+	Err error
+}
+
+// newParseError returns a parse error for the given input.
+func newParseError(input []byte, offset int, tt byte, err error) ParseError {
+	head := input
+	if len(head) > parseErrorInputLen {
+		head = head[:parseErrorInputLen]
+	}
+	return ParseError{
+		Offset:  offset,
+		Typetag: tt,
+		Len:     len(input),
+		Input:   append([]byte{}, head...),
+		Err:     err,
+	}
+}
+
+// Error returns the error message.
+func (e ParseError) Error() string {
+	return e.Err.Error()
+}
+
+// Cause returns the underlying error, so errors.Cause works with parse errors.
+func (e ParseError) Cause() error {
+	return e.Err
+}
+
+// Unwrap returns the underlying error, so errors.As can find parse errors.
+func (e ParseError) Unwrap() error {
+	return e.Err
+}
+
+// Context describes the error along with where it happened, e.g.
+// "parse error at byte 12 decoding typetag 'f': short buffer (input: 2f666f6f000000002c660000...)".
+func (e ParseError) Context() string {
+	input := hex.EncodeToString(e.Input)
+	if e.Len > len(e.Input) {
+		input += "..."
+	}
+	return fmt.Sprintf("parse error at byte %d decoding typetag '%c': %s (input: %s)", e.Offset, e.Typetag, errors.Cause(e.Err), input)
+}
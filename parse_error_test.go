@@ -0,0 +1,61 @@
+package osc
+
+import (
+	"testing"
+
+	"github.com/pkg/errors"
+)
+
+func TestParseError(t *testing.T) {
+	// The float argument is missing.
+	data := append(ToBytes("/foo"), ToBytes(",if")...)
+	data = append(data, Int(1).Bytes()...)
+
+	_, err := ParseMessage(data, nil)
+
+	var pe ParseError
+	if !errors.As(err, &pe) {
+		t.Fatalf("expected a ParseError, got %v", err)
+	}
+	if expected, got := 16, pe.Offset; expected != got {
+		t.Fatalf("expected offset %d, got %d", expected, got)
+	}
+	if expected, got := TypetagFloat, pe.Typetag; expected != got {
+		t.Fatalf("expected typetag %c, got %c", expected, got)
+	}
+	if expected, got := 16, pe.Len; expected != got {
+		t.Fatalf("expected length %d, got %d", expected, got)
+	}
+	expected := "parse error at byte 16 decoding typetag 'f': short buffer (input: 2f666f6f000000002c69660000000001)"
+	if got := pe.Context(); expected != got {
+		t.Fatalf("expected %s, got %s", expected, got)
+	}
+	if expected, got := "parsing argument 1 (typetag 'f'): short buffer", pe.Error(); expected != got {
+		t.Fatalf("expected %s, got %s", expected, got)
+	}
+
+	// Longer input is truncated.
+	data = append(ToBytes("/foo/bar"), ToBytes(",s")...)
+	data = append(data, "baz"...)
+
+	_, err = ParseMessageWith(data, nil, ParseOptions{MaxStringLen: 2})
+	if !errors.As(err, &pe) {
+		t.Fatalf("expected a ParseError, got %v", err)
+	}
+	expected = "parse error at byte 16 decoding typetag 's': string argument too large (input: 2f666f6f2f626172000000002c730000...)"
+	if got := pe.Context(); expected != got {
+		t.Fatalf("expected %s, got %s", expected, got)
+	}
+}
+
+func TestParseErrorShortInput(t *testing.T) {
+	_, err := ReadArguments([]byte(",i"), []byte{0, 1})
+
+	var pe ParseError
+	if !errors.As(err, &pe) {
+		t.Fatalf("expected a ParseError, got %v", err)
+	}
+	if expected, got := "parse error at byte 0 decoding typetag 'i': short buffer (input: 0001)", pe.Context(); expected != got {
+		t.Fatalf("expected %s, got %s", expected, got)
+	}
+}
@@ -0,0 +1,72 @@
+package osc
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// PatternSet matches addresses against many address patterns at once.
+type PatternSet struct {
+	patterns []string
+	groups   []int
+	re       *regexp.Regexp
+}
+
+// CompilePatternSet compiles the patterns into a single regular expression.
+// Patterns are translated like they are when messages are dispatched, see GetRegex.
+func CompilePatternSet(patterns []string) (*PatternSet, error) {
+	ps := &PatternSet{
+		patterns: append([]string(nil), patterns...),
+		groups:   make([]int, len(patterns)),
+	}
+	if len(patterns) == 0 {
+		return ps, nil
+	}
+	var (
+		alternatives = make([]string, len(patterns))
+		group        = 1
+	)
+	for i, p := range patterns {
+		src := "(" + addressPatternSource(p) + ")"
+		re, err := regexp.Compile(src)
+		if err != nil {
+			return nil, errors.Wrapf(err, "pattern %d (%s)", i, p)
+		}
+		alternatives[i] = src
+
+		// Braces in the pattern add groups after the pattern's own group.
+		ps.groups[i] = group
+		group += re.NumSubexp()
+	}
+	re, err := regexp.Compile("^(?:" + strings.Join(alternatives, "|") + ")$")
+	if err != nil {
+		return nil, errors.Wrap(err, "compile pattern set")
+	}
+	ps.re = re
+	return ps, nil
+}
+
+// Match returns the index of the first pattern that matches the address.
+func (ps *PatternSet) Match(address string) (index int, ok bool) {
+	if ps.re == nil {
+		return -1, false
+	}
+	m := ps.re.FindStringSubmatchIndex(address)
+	if m == nil {
+		return -1, false
+	}
+	// Each pattern is the first capturing group of its alternative.
+	for i, group := range ps.groups {
+		if m[2*group] >= 0 {
+			return i, true
+		}
+	}
+	return -1, false
+}
+
+// Patterns returns the patterns in the set.
+func (ps *PatternSet) Patterns() []string {
+	return append([]string(nil), ps.patterns...)
+}
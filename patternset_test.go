@@ -0,0 +1,76 @@
+package osc
+
+import (
+	"testing"
+)
+
+func TestPatternSet(t *testing.T) {
+	ps, err := CompilePatternSet([]string{
+		"/synth/1/freq",
+		"/synth/*/freq",
+		"/synth/{1,2}/gain",
+		"/mixer/ch[0-9]",
+		"/mixer/ch[!0-9]",
+		"/fx/?",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, testcase := range []struct {
+		Address string
+		Index   int
+		OK      bool
+	}{
+		{Address: "/synth/1/freq", Index: 0, OK: true},
+		{Address: "/synth/2/freq", Index: 1, OK: true},
+		{Address: "/synth/2/gain", Index: 2, OK: true},
+		{Address: "/synth/3/gain", Index: -1, OK: false},
+		{Address: "/synth/1/2/freq", Index: -1, OK: false},
+		{Address: "/mixer/ch4", Index: 3, OK: true},
+		{Address: "/mixer/chx", Index: 4, OK: true},
+		{Address: "/fx/a", Index: 5, OK: true},
+		{Address: "/fx/ab", Index: -1, OK: false},
+		{Address: "/fx//", Index: -1, OK: false},
+	} {
+		index, ok := ps.Match(testcase.Address)
+		if index != testcase.Index || ok != testcase.OK {
+			t.Fatalf("(%s) expected %d %t, got %d %t", testcase.Address, testcase.Index, testcase.OK, index, ok)
+		}
+	}
+	if expected, got := 6, len(ps.Patterns()); expected != got {
+		t.Fatalf("expected %d patterns, got %d", expected, got)
+	}
+}
+
+func TestPatternSetEmpty(t *testing.T) {
+	ps, err := CompilePatternSet(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := ps.Match("/foo"); ok {
+		t.Fatal("expected no match")
+	}
+}
+
+func TestPatternSetInvalid(t *testing.T) {
+	if _, err := CompilePatternSet([]string{"/foo", "/bar/[a"}); err == nil {
+		t.Fatal("expected error, got nil")
+	}
+}
+
+func BenchmarkPatternSet(b *testing.B) {
+	patterns := make([]string, 0, 50)
+	for _, synth := range []string{"a", "b", "c", "d", "e"} {
+		for _, param := range []string{"freq", "gain", "pan", "attack", "release", "cutoff", "res", "mix", "drive", "tone"} {
+			patterns = append(patterns, "/"+synth+"/*/"+param)
+		}
+	}
+	ps, err := CompilePatternSet(patterns)
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _ = ps.Match("/e/1/tone")
+	}
+}
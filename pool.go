@@ -0,0 +1,37 @@
+package osc
+
+import (
+	"sync"
+)
+
+// MessagePool reuses messages to reduce allocations in hot paths.
+// The zero value is ready to use, and a MessagePool is safe for concurrent use.
+type MessagePool struct {
+	pool sync.Pool
+}
+
+// Get returns a message from the pool, or a new one if the pool is empty.
+// The message has no address, and its arguments slice is empty but
+// keeps the capacity it had when it was put back.
+func (p *MessagePool) Get() *Message {
+	msg, ok := p.pool.Get().(*Message)
+	if !ok {
+		return &Message{}
+	}
+	return msg
+}
+
+// Put puts a message back in the pool.
+// Neither the message nor its arguments may be used after Put,
+// since the message will be handed out again by Get.
+func (p *MessagePool) Put(msg *Message) {
+	if msg == nil {
+		return
+	}
+	// Clear the arguments so the pool does not keep them alive.
+	for i := range msg.Arguments {
+		msg.Arguments[i] = nil
+	}
+	*msg = Message{Arguments: msg.Arguments[:0]}
+	p.pool.Put(msg)
+}
@@ -0,0 +1,53 @@
+package osc
+
+import (
+	"testing"
+)
+
+func TestMessagePool(t *testing.T) {
+	var p MessagePool
+
+	msg := p.Get()
+	if expected, got := "", msg.Address; expected != got {
+		t.Fatalf("expected %q, got %q", expected, got)
+	}
+	msg.Address = "/foo"
+	msg.Arguments = append(msg.Arguments, Int(1), Int(2))
+	msg.Seq = 3
+	p.Put(msg)
+	p.Put(nil)
+
+	msg = p.Get()
+	if msg.Address != "" || len(msg.Arguments) != 0 || msg.Seq != 0 {
+		t.Fatalf("expected an empty message, got %v", msg)
+	}
+}
+
+func BenchmarkMessagePool(b *testing.B) {
+	var (
+		p   MessagePool
+		buf = make([]byte, 64)
+	)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		msg := p.Get()
+		msg.Address = "/synth/freq"
+		msg.Arguments = append(msg.Arguments, Float(440), Int(1))
+		if _, err := msg.MarshalTo(buf); err != nil {
+			b.Fatal(err)
+		}
+		p.Put(msg)
+	}
+}
+
+func BenchmarkMessageNoPool(b *testing.B) {
+	buf := make([]byte, 64)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		msg := &Message{Address: "/synth/freq"}
+		msg.Arguments = append(msg.Arguments, Float(440), Int(1))
+		if _, err := msg.MarshalTo(buf); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
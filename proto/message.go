@@ -0,0 +1,157 @@
+// Package proto converts OSC messages to and from protocol buffers
+// without a custom .proto file, using the well-known Struct type.
+//
+// A message is represented as a Struct like
+//
+//	{"address": "/foo", "arguments": [{"type": "i", "value": 1}, {"type": "b", "value": "AQID"}]}
+//
+// Ints and floats are numbers, strings are strings and bools are bools.
+// Blobs and arguments of custom types are base64 encoded strings of their OSC encoding
+// (without the length prefix for blobs).
+package proto
+
+import (
+	"encoding/base64"
+
+	"github.com/pkg/errors"
+	"github.com/scgolang/osc"
+	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// Common errors.
+var (
+	ErrInvalidStruct = errors.New("struct does not represent an OSC message")
+)
+
+// Struct field names.
+const (
+	fieldAddress   = "address"
+	fieldArguments = "arguments"
+	fieldType      = "type"
+	fieldValue     = "value"
+)
+
+// MessageToStruct converts a message to a Struct.
+func MessageToStruct(msg osc.Message) (*structpb.Struct, error) {
+	args := make([]*structpb.Value, len(msg.Arguments))
+	for i, a := range msg.Arguments {
+		if a == nil {
+			return nil, errors.Errorf("argument %d is nil", i)
+		}
+		args[i] = structpb.NewStructValue(&structpb.Struct{
+			Fields: map[string]*structpb.Value{
+				fieldType:  structpb.NewStringValue(string(a.Typetag())),
+				fieldValue: argumentValue(a),
+			},
+		})
+	}
+	return &structpb.Struct{
+		Fields: map[string]*structpb.Value{
+			fieldAddress:   structpb.NewStringValue(msg.Address),
+			fieldArguments: structpb.NewListValue(&structpb.ListValue{Values: args}),
+		},
+	}, nil
+}
+
+// MessageFromStruct converts a Struct created by MessageToStruct back to a message.
+func MessageFromStruct(s *structpb.Struct) (osc.Message, error) {
+	address, ok := s.GetFields()[fieldAddress].GetKind().(*structpb.Value_StringValue)
+	if !ok {
+		return osc.Message{}, errors.Wrap(ErrInvalidStruct, "missing address")
+	}
+	msg := osc.Message{Address: address.StringValue, Arguments: []osc.Argument{}}
+
+	for i, v := range s.GetFields()[fieldArguments].GetListValue().GetValues() {
+		a, err := argumentFromValue(v.GetStructValue())
+		if err != nil {
+			return osc.Message{}, errors.Wrapf(err, "argument %d", i)
+		}
+		msg.Arguments = append(msg.Arguments, a)
+	}
+	return msg, nil
+}
+
+// MessageToProtobuf wraps a message in an Any holding a Struct, see MessageToStruct.
+func MessageToProtobuf(msg osc.Message) (*anypb.Any, error) {
+	s, err := MessageToStruct(msg)
+	if err != nil {
+		return nil, err
+	}
+	a, err := anypb.New(s)
+	return a, errors.Wrap(err, "create any")
+}
+
+// MessageFromProtobuf unwraps a message created by MessageToProtobuf.
+func MessageFromProtobuf(a *anypb.Any) (osc.Message, error) {
+	s := &structpb.Struct{}
+	if err := a.UnmarshalTo(s); err != nil {
+		return osc.Message{}, errors.Wrap(err, "unmarshal struct")
+	}
+	return MessageFromStruct(s)
+}
+
+// argumentValue returns the value of an argument.
+func argumentValue(a osc.Argument) *structpb.Value {
+	switch x := a.(type) {
+	case osc.Int:
+		return structpb.NewNumberValue(float64(x))
+	case osc.Float:
+		return structpb.NewNumberValue(float64(x))
+	case osc.String:
+		return structpb.NewStringValue(string(x))
+	case osc.Bool:
+		return structpb.NewBoolValue(bool(x))
+	case osc.Blob:
+		return structpb.NewStringValue(x.Base64())
+	default:
+		return structpb.NewStringValue(base64.StdEncoding.EncodeToString(osc.EncodeArgument(a)))
+	}
+}
+
+// argumentFromValue returns the argument represented by a Struct created by argumentValue.
+func argumentFromValue(s *structpb.Struct) (osc.Argument, error) {
+	tt, v := s.GetFields()[fieldType].GetStringValue(), s.GetFields()[fieldValue]
+	if len(tt) != 1 || v == nil {
+		return nil, errors.Wrap(ErrInvalidStruct, "missing type or value")
+	}
+	switch tt[0] {
+	case osc.TypetagInt:
+		n, ok := v.GetKind().(*structpb.Value_NumberValue)
+		if !ok {
+			return nil, errors.Wrap(ErrInvalidStruct, "int must be a number")
+		}
+		return osc.Int(n.NumberValue), nil
+	case osc.TypetagFloat:
+		n, ok := v.GetKind().(*structpb.Value_NumberValue)
+		if !ok {
+			return nil, errors.Wrap(ErrInvalidStruct, "float must be a number")
+		}
+		return osc.Float(n.NumberValue), nil
+	case osc.TypetagString:
+		str, ok := v.GetKind().(*structpb.Value_StringValue)
+		if !ok {
+			return nil, errors.Wrap(ErrInvalidStruct, "string must be a string")
+		}
+		return osc.String(str.StringValue), nil
+	case osc.TypetagTrue, osc.TypetagFalse:
+		return osc.Bool(tt[0] == osc.TypetagTrue), nil
+	}
+	encoded, ok := v.GetKind().(*structpb.Value_StringValue)
+	if !ok {
+		return nil, errors.Wrapf(ErrInvalidStruct, "type %q must be a base64 string", tt)
+	}
+	if tt[0] == osc.TypetagBlob {
+		b, err := osc.BlobFromBase64(encoded.StringValue)
+		if err != nil {
+			return nil, errors.Wrapf(ErrInvalidStruct, "type %q: %s", tt, err)
+		}
+		return b, nil
+	}
+	data, err := base64.StdEncoding.DecodeString(encoded.StringValue)
+	if err != nil {
+		return nil, errors.Wrapf(ErrInvalidStruct, "type %q: %s", tt, err)
+	}
+	a, _, err := osc.ReadArgument(tt[0], data)
+	return a, err
+}
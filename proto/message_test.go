@@ -0,0 +1,90 @@
+package proto
+
+import (
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/scgolang/osc"
+	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+func TestMessageProtobuf(t *testing.T) {
+	for _, msg := range []osc.Message{
+		{Address: "/foo"},
+		{
+			Address: "/synth/1",
+			Arguments: []osc.Argument{
+				osc.Int(-7),
+				osc.Float(0.125),
+				osc.String("saw"),
+				osc.Bool(true),
+				osc.Bool(false),
+				osc.Blob{1, 2, 3},
+			},
+		},
+	} {
+		a, err := MessageToProtobuf(msg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := MessageFromProtobuf(a)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !msg.Equal(got) {
+			t.Fatalf("expected %v, got %v", msg, got)
+		}
+	}
+}
+
+func TestMessageToStruct(t *testing.T) {
+	s, err := MessageToStruct(osc.Message{Address: "/foo", Arguments: []osc.Argument{osc.Int(1), osc.Blob{1, 2, 3}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected, err := structpb.NewStruct(map[string]interface{}{
+		"address": "/foo",
+		"arguments": []interface{}{
+			map[string]interface{}{"type": "i", "value": 1},
+			map[string]interface{}{"type": "b", "value": "AQID"},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected, got := expected.String(), s.String(); expected != got {
+		t.Fatalf("expected %s, got %s", expected, got)
+	}
+	if _, err := MessageToStruct(osc.Message{Address: "/foo", Arguments: []osc.Argument{nil}}); err == nil {
+		t.Fatal("expected error, got nil")
+	}
+}
+
+func TestMessageFromStructErrors(t *testing.T) {
+	for _, fields := range []map[string]interface{}{
+		{},
+		{"address": 1},
+		{"address": "/foo", "arguments": []interface{}{map[string]interface{}{"value": 1}}},
+		{"address": "/foo", "arguments": []interface{}{map[string]interface{}{"type": "i"}}},
+		{"address": "/foo", "arguments": []interface{}{map[string]interface{}{"type": "i", "value": "1"}}},
+		{"address": "/foo", "arguments": []interface{}{map[string]interface{}{"type": "f", "value": true}}},
+		{"address": "/foo", "arguments": []interface{}{map[string]interface{}{"type": "s", "value": 1}}},
+		{"address": "/foo", "arguments": []interface{}{map[string]interface{}{"type": "b", "value": "!"}}},
+	} {
+		s, err := structpb.NewStruct(fields)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := MessageFromStruct(s); errors.Cause(err) != ErrInvalidStruct {
+			t.Fatalf("(%v) expected ErrInvalidStruct, got %v", fields, err)
+		}
+	}
+	a, err := anypb.New(structpb.NewStringValue("foo"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := MessageFromProtobuf(a); err == nil {
+		t.Fatal("expected error, got nil")
+	}
+}
@@ -0,0 +1,71 @@
+package osc
+
+import (
+	"sort"
+	"strings"
+	"sync"
+)
+
+// SortedQueue collects messages and hands them out sorted by address.
+// Messages with the same address keep the order they were enqueued in.
+// It is safe for concurrent use.
+type SortedQueue struct {
+	mu   sync.Mutex
+	msgs []Message
+}
+
+// Enqueue adds a message to the queue.
+func (q *SortedQueue) Enqueue(msg Message) {
+	q.mu.Lock()
+	q.msgs = append(q.msgs, msg)
+	q.mu.Unlock()
+}
+
+// Len returns the number of messages in the queue.
+func (q *SortedQueue) Len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return len(q.msgs)
+}
+
+// Drain removes all the messages from the queue and returns them sorted by address.
+func (q *SortedQueue) Drain() []Message {
+	q.mu.Lock()
+	msgs := q.msgs
+	q.msgs = nil
+	q.mu.Unlock()
+
+	sortByAddress(msgs)
+	return msgs
+}
+
+// DrainByPrefix removes the messages whose address is prefix or is below prefix
+// (e.g. "/a" and "/a/b" are below "/a", but "/ab" is not)
+// and returns them sorted by address.
+func (q *SortedQueue) DrainByPrefix(prefix string) []Message {
+	var (
+		drained []Message
+		kept    []Message
+		below   = strings.TrimSuffix(prefix, string(MessageChar)) + string(MessageChar)
+	)
+	q.mu.Lock()
+	for _, msg := range q.msgs {
+		if msg.Address == prefix || strings.HasPrefix(msg.Address, below) {
+			drained = append(drained, msg)
+		} else {
+			kept = append(kept, msg)
+		}
+	}
+	q.msgs = kept
+	q.mu.Unlock()
+
+	sortByAddress(drained)
+	return drained
+}
+
+// sortByAddress sorts messages lexicographically by address.
+func sortByAddress(msgs []Message) {
+	sort.SliceStable(msgs, func(i, j int) bool {
+		return msgs[i].Address < msgs[j].Address
+	})
+}
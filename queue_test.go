@@ -0,0 +1,63 @@
+package osc
+
+import (
+	"testing"
+)
+
+func addresses(msgs []Message) []string {
+	addrs := make([]string, len(msgs))
+	for i, msg := range msgs {
+		addrs[i] = msg.Address
+	}
+	return addrs
+}
+
+func expectAddresses(t *testing.T, expected []string, msgs []Message) {
+	got := addresses(msgs)
+	if len(expected) != len(got) {
+		t.Fatalf("expected %v, got %v", expected, got)
+	}
+	for i := range expected {
+		if expected[i] != got[i] {
+			t.Fatalf("expected %v, got %v", expected, got)
+		}
+	}
+}
+
+func TestSortedQueueDrain(t *testing.T) {
+	q := &SortedQueue{}
+	for _, addr := range []string{"/b/1", "/a/2", "/c", "/a/1"} {
+		q.Enqueue(Message{Address: addr})
+	}
+	if expected, got := 4, q.Len(); expected != got {
+		t.Fatalf("expected %d, got %d", expected, got)
+	}
+	expectAddresses(t, []string{"/a/1", "/a/2", "/b/1", "/c"}, q.Drain())
+
+	if expected, got := 0, q.Len(); expected != got {
+		t.Fatalf("expected %d, got %d", expected, got)
+	}
+}
+
+func TestSortedQueueDrainStable(t *testing.T) {
+	q := &SortedQueue{}
+	q.Enqueue(Message{Address: "/b"})
+	q.Enqueue(Message{Address: "/a", Arguments: []Argument{Int(1)}})
+	q.Enqueue(Message{Address: "/a", Arguments: []Argument{Int(2)}})
+
+	msgs := q.Drain()
+	for i, expected := range []Int{1, 2} {
+		if got := msgs[i].Arguments[0]; !expected.Equal(got) {
+			t.Fatalf("expected %s, got %s", expected, got)
+		}
+	}
+}
+
+func TestSortedQueueDrainByPrefix(t *testing.T) {
+	q := &SortedQueue{}
+	for _, addr := range []string{"/ab", "/a/2", "/b", "/a", "/a/1"} {
+		q.Enqueue(Message{Address: addr})
+	}
+	expectAddresses(t, []string{"/a", "/a/1", "/a/2"}, q.DrainByPrefix("/a"))
+	expectAddresses(t, []string{"/ab", "/b"}, q.Drain())
+}
@@ -0,0 +1,128 @@
+package quic
+
+import (
+	"bytes"
+	"context"
+	"sync"
+
+	"github.com/pkg/errors"
+	quicgo "github.com/quic-go/quic-go"
+	"github.com/scgolang/osc"
+)
+
+// Client sends OSC over QUIC.
+type Client struct {
+	config config
+
+	mu   sync.Mutex
+	conn *quicgo.Conn
+}
+
+// NewClient creates a new QUIC client.
+// A TLS configuration that is able to verify the server is required.
+func NewClient(opts ...Option) (*Client, error) {
+	c, err := newConfig(opts)
+	if err != nil {
+		return nil, err
+	}
+	return &Client{config: c}, nil
+}
+
+// Dial connects the client to the server at the given address.
+func (c *Client) Dial(addr string) error {
+	return c.DialContext(context.Background(), addr)
+}
+
+// DialContext connects the client to the server at the given address
+// using the provided context for the handshake.
+func (c *Client) DialContext(ctx context.Context, addr string) error {
+	conn, err := quicgo.DialAddr(ctx, addr, c.config.tlsConfig, quicConfig())
+	if err != nil {
+		return errors.Wrap(err, "dial")
+	}
+	c.mu.Lock()
+	c.conn = conn
+	c.mu.Unlock()
+	return nil
+}
+
+// Close closes the connection to the server.
+func (c *Client) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.conn == nil {
+		return ErrNotDialed
+	}
+	return c.conn.CloseWithError(0, "")
+}
+
+// Send sends an OSC packet.
+// Messages are sent as unreliable datagrams, so they may be lost.
+// Messages that are too large for a datagram and bundles are sent on a stream
+// so they arrive reliably and in order.
+func (c *Client) Send(p osc.Packet) error {
+	if p == nil {
+		return osc.ErrNilPacket
+	}
+	c.mu.Lock()
+	conn := c.conn
+	c.mu.Unlock()
+
+	if conn == nil {
+		return ErrNotDialed
+	}
+	if _, ok := p.(osc.Message); ok {
+		err := conn.SendDatagram(p.Bytes())
+		var tooLarge *quicgo.DatagramTooLargeError
+		if !errors.As(err, &tooLarge) {
+			return errors.Wrap(err, "send datagram")
+		}
+	}
+	return c.sendStream(conn, p)
+}
+
+// SendReliable sends an OSC packet on a stream, even if it is a message.
+func (c *Client) SendReliable(p osc.Packet) error {
+	if p == nil {
+		return osc.ErrNilPacket
+	}
+	c.mu.Lock()
+	conn := c.conn
+	c.mu.Unlock()
+
+	if conn == nil {
+		return ErrNotDialed
+	}
+	return c.sendStream(conn, p)
+}
+
+// Encode returns the bytes Send would transmit for the packet without sending them,
+// e.g. to assert on them in tests. Messages are encoded as datagrams,
+// bundles are framed by an osc.Encoder like on a stream.
+// The client does not need to be dialed.
+func (c *Client) Encode(p osc.Packet) ([]byte, error) {
+	if p == nil {
+		return nil, osc.ErrNilPacket
+	}
+	if _, ok := p.(osc.Message); ok {
+		return p.Bytes(), nil
+	}
+	var buf bytes.Buffer
+	if err := osc.NewEncoder(&buf).Encode(p); err != nil {
+		return nil, errors.Wrap(err, "encode packet")
+	}
+	return buf.Bytes(), nil
+}
+
+// sendStream sends a packet on a new unidirectional stream.
+func (c *Client) sendStream(conn *quicgo.Conn, p osc.Packet) error {
+	stream, err := conn.OpenUniStream()
+	if err != nil {
+		return errors.Wrap(err, "open stream")
+	}
+	if err := osc.NewEncoder(stream).Encode(p); err != nil {
+		_ = stream.Close() // Best effort.
+		return errors.Wrap(err, "encode packet")
+	}
+	return stream.Close()
+}
@@ -0,0 +1,56 @@
+package quic
+
+import (
+	"bytes"
+	"crypto/tls"
+	"testing"
+
+	"github.com/scgolang/osc"
+)
+
+func TestClientEncode(t *testing.T) {
+	c, err := NewClient(WithTLSConfig(&tls.Config{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	msg := osc.Message{Address: "/foo", Arguments: []osc.Argument{osc.Int(1)}}
+
+	got, err := c.Encode(msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := msg.Bytes(); !bytes.Equal(expected, got) {
+		t.Fatalf("expected %x, got %x", expected, got)
+	}
+
+	// Bundles are framed like on a stream.
+	bundle := osc.Bundle{Timetag: osc.Immediately, Packets: []osc.Packet{msg}}
+	var expected bytes.Buffer
+	if err := osc.NewEncoder(&expected).Encode(bundle); err != nil {
+		t.Fatal(err)
+	}
+	if got, err = c.Encode(bundle); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(expected.Bytes(), got) {
+		t.Fatalf("expected %x, got %x", expected.Bytes(), got)
+	}
+	if _, err := c.Encode(nil); err != osc.ErrNilPacket {
+		t.Fatalf("expected ErrNilPacket, got %v", err)
+	}
+}
+
+func TestClientSendNilPacket(t *testing.T) {
+	server, client, _ := testServerClient(t, osc.Dispatcher{
+		"/foo": osc.Method(func(msg osc.Message) error { return nil }),
+	})
+	defer func() { _ = server.Close() }() // Best effort.
+	defer func() { _ = client.Close() }() // Best effort.
+
+	if err := client.Send(nil); err != osc.ErrNilPacket {
+		t.Fatalf("expected ErrNilPacket, got %v", err)
+	}
+	if err := client.SendReliable(nil); err != osc.ErrNilPacket {
+		t.Fatalf("expected ErrNilPacket, got %v", err)
+	}
+}
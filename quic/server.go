@@ -0,0 +1,278 @@
+// Package quic implements an experimental OSC transport over QUIC.
+//
+// Messages are sent as unreliable QUIC datagrams (RFC 9221) for the lowest
+// possible latency. Bundles are sent on unidirectional streams so that
+// their contents arrive reliably and in order.
+package quic
+
+import (
+	"context"
+	"crypto/tls"
+	"io"
+	"log"
+	"net"
+	"sync"
+
+	"github.com/pkg/errors"
+	quicgo "github.com/quic-go/quic-go"
+	"github.com/scgolang/osc"
+)
+
+// NextProto is the ALPN protocol identifier used for OSC over QUIC.
+const NextProto = "osc"
+
+// Common errors.
+var (
+	ErrNilTLSConfig = errors.New("tls config must not be nil")
+	ErrNotDialed    = errors.New("client is not connected")
+	ErrStarted      = errors.New("server has already been started")
+)
+
+// Option configures a Server or a Client.
+type Option func(*config) error
+
+type config struct {
+	exactMatch    bool
+	maxPacketSize int
+	onError       osc.ParseErrorHandler
+	tlsConfig     *tls.Config
+}
+
+// WithTLSConfig sets the TLS configuration.
+// If NextProtos is empty it will be set to NextProto.
+func WithTLSConfig(tlsConfig *tls.Config) Option {
+	return func(c *config) error {
+		if tlsConfig == nil {
+			return ErrNilTLSConfig
+		}
+		c.tlsConfig = tlsConfig.Clone()
+		return nil
+	}
+}
+
+// WithCertificate loads a PEM encoded certificate and key from the given files.
+func WithCertificate(certFile, keyFile string) Option {
+	return func(c *config) error {
+		cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+		if err != nil {
+			return errors.Wrap(err, "load certificate")
+		}
+		if c.tlsConfig == nil {
+			c.tlsConfig = &tls.Config{}
+		}
+		c.tlsConfig.Certificates = append(c.tlsConfig.Certificates, cert)
+		return nil
+	}
+}
+
+// WithExactMatch makes the server only dispatch messages to methods
+// whose addresses match the message's address exactly.
+func WithExactMatch(value bool) Option {
+	return func(c *config) error {
+		c.exactMatch = value
+		return nil
+	}
+}
+
+// WithMaxPacketSize sets the size of the largest packet the server accepts on a stream.
+// It defaults to osc.DefaultMaxPacketSize, see osc.Decoder.SetMaxPacketSize.
+func WithMaxPacketSize(n int) Option {
+	return func(c *config) error {
+		c.maxPacketSize = n
+		return nil
+	}
+}
+
+// WithErrorHandler sets a function that the server calls for every packet
+// that can not be parsed or whose handlers return an error.
+// By default a warning is logged and the packet is dropped, so a misbehaving peer
+// does not stop the server. A stream that can not be decoded is abandoned,
+// since the packets that follow can not be framed.
+func WithErrorHandler(fn osc.ParseErrorHandler) Option {
+	return func(c *config) error {
+		c.onError = fn
+		return nil
+	}
+}
+
+// newConfig applies the options and fills in defaults.
+func newConfig(opts []Option) (config, error) {
+	c := config{maxPacketSize: osc.DefaultMaxPacketSize}
+	for _, opt := range opts {
+		if err := opt(&c); err != nil {
+			return c, err
+		}
+	}
+	if c.onError == nil {
+		c.onError = logError
+	}
+	if c.tlsConfig == nil {
+		return c, ErrNilTLSConfig
+	}
+	if len(c.tlsConfig.NextProtos) == 0 {
+		c.tlsConfig.NextProtos = []string{NextProto}
+	}
+	return c, nil
+}
+
+// logError is the default error handler of the server.
+// It logs a warning and drops the packet.
+func logError(data []byte, sender net.Addr, err error) {
+	log.Printf("WARN osc/quic: dropping %d byte packet from %v: %s", len(data), sender, err)
+}
+
+// quicConfig returns the QUIC configuration shared by servers and clients.
+func quicConfig() *quicgo.Config {
+	return &quicgo.Config{EnableDatagrams: true}
+}
+
+// Server serves OSC over QUIC.
+type Server struct {
+	config config
+
+	mu       sync.Mutex
+	listener *quicgo.Listener
+	ready    chan struct{}
+}
+
+// NewServer creates a new QUIC server.
+// A TLS configuration containing a certificate is required.
+func NewServer(opts ...Option) (*Server, error) {
+	c, err := newConfig(opts)
+	if err != nil {
+		return nil, err
+	}
+	return &Server{config: c, ready: make(chan struct{})}, nil
+}
+
+// Addr returns the address the server is listening on.
+// It blocks until ListenAndServe has started listening.
+func (s *Server) Addr() net.Addr {
+	<-s.ready
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.listener.Addr()
+}
+
+// Close stops the server.
+func (s *Server) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.listener == nil {
+		return osc.ErrPrematureClose
+	}
+	return s.listener.Close()
+}
+
+// ListenAndServe listens on the given address and dispatches OSC packets
+// received from every connection to the dispatcher.
+// Packets that can not be parsed or dispatched are passed to the error handler,
+// see WithErrorHandler. ListenAndServe only returns if listening or accepting
+// a connection fails, or nil if the server is closed.
+// A server can only be started once, later calls return ErrStarted.
+func (s *Server) ListenAndServe(addr string, d osc.Dispatcher) error {
+	if d == nil {
+		return osc.ErrNilDispatcher
+	}
+	s.mu.Lock()
+	if s.listener != nil {
+		s.mu.Unlock()
+		return ErrStarted
+	}
+	listener, err := quicgo.ListenAddr(addr, s.config.tlsConfig, quicConfig())
+	if err != nil {
+		s.mu.Unlock()
+		return errors.Wrap(err, "listen")
+	}
+	s.listener = listener
+	s.mu.Unlock()
+	close(s.ready)
+
+	var (
+		ctx, cancel = context.WithCancel(context.Background())
+		errChan     = make(chan error, 1)
+	)
+	defer cancel()
+
+	go func() {
+		for {
+			conn, err := listener.Accept(ctx)
+			if err != nil {
+				if err == quicgo.ErrServerClosed || ctx.Err() != nil {
+					errChan <- nil
+				} else {
+					errChan <- errors.Wrap(err, "accept")
+				}
+				return
+			}
+			go s.serveDatagrams(ctx, conn, d)
+			go s.serveStreams(ctx, conn, d)
+		}
+	}()
+	err = <-errChan
+	_ = listener.Close() // Best effort.
+	return err
+}
+
+// serveDatagrams dispatches packets received as QUIC datagrams.
+func (s *Server) serveDatagrams(ctx context.Context, conn *quicgo.Conn, d osc.Dispatcher) {
+	for {
+		data, err := conn.ReceiveDatagram(ctx)
+		if err != nil {
+			return // The connection went away.
+		}
+		if err := dispatch(data, conn.RemoteAddr(), d, s.config.exactMatch); err != nil {
+			s.config.onError(data, conn.RemoteAddr(), err)
+		}
+	}
+}
+
+// serveStreams dispatches packets received on unidirectional streams.
+func (s *Server) serveStreams(ctx context.Context, conn *quicgo.Conn, d osc.Dispatcher) {
+	for {
+		stream, err := conn.AcceptUniStream(ctx)
+		if err != nil {
+			return // The connection went away.
+		}
+		go func() {
+			dec := osc.NewDecoder(stream)
+			dec.SetMaxPacketSize(s.config.maxPacketSize)
+			dec.SetSender(conn.RemoteAddr())
+			for {
+				p, err := dec.Decode()
+				if err == io.EOF {
+					return
+				}
+				if err != nil {
+					stream.CancelRead(0)
+					s.config.onError(nil, conn.RemoteAddr(), errors.Wrap(err, "decode packet"))
+					return
+				}
+				if err := dispatchPacket(p, d, s.config.exactMatch); err != nil {
+					s.config.onError(p.Bytes(), conn.RemoteAddr(), err)
+				}
+			}
+		}()
+	}
+}
+
+// dispatch parses an OSC packet and dispatches it.
+func dispatch(data []byte, sender net.Addr, d osc.Dispatcher, exactMatch bool) error {
+	p, _, err := osc.ParsePacketN(data, sender)
+	if err != nil {
+		return err
+	}
+	return dispatchPacket(p, d, exactMatch)
+}
+
+// dispatchPacket dispatches a parsed OSC packet.
+func dispatchPacket(p osc.Packet, d osc.Dispatcher, exactMatch bool) error {
+	switch x := p.(type) {
+	case osc.Bundle:
+		return errors.Wrap(d.Dispatch(x, exactMatch), "dispatch bundle")
+	case osc.Message:
+		return errors.Wrap(d.Invoke(x, exactMatch), "dispatch message")
+	default:
+		return osc.ErrParse
+	}
+}
@@ -0,0 +1,333 @@
+package quic
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/scgolang/osc"
+)
+
+// testTLSConfigs returns a server and a client TLS config
+// using a freshly generated self-signed certificate.
+func testTLSConfigs(t *testing.T) (*tls.Config, *tls.Config) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "localhost"},
+		DNSNames:     []string{"localhost"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pool := x509.NewCertPool()
+	pool.AddCert(cert)
+
+	serverConfig := &tls.Config{
+		Certificates: []tls.Certificate{{Certificate: [][]byte{der}, PrivateKey: key}},
+	}
+	clientConfig := &tls.Config{RootCAs: pool, ServerName: "localhost"}
+	return serverConfig, clientConfig
+}
+
+func testServerClient(t *testing.T, d osc.Dispatcher, opts ...Option) (*Server, *Client, chan error) {
+	serverConfig, clientConfig := testTLSConfigs(t)
+
+	server, err := NewServer(append([]Option{WithTLSConfig(serverConfig)}, opts...)...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	errChan := make(chan error, 1)
+	go func() {
+		errChan <- server.ListenAndServe("127.0.0.1:0", d)
+	}()
+	client, err := NewClient(WithTLSConfig(clientConfig))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := client.Dial(server.Addr().String()); err != nil {
+		t.Fatal(err)
+	}
+	return server, client, errChan
+}
+
+func TestNewServerNoTLSConfig(t *testing.T) {
+	if _, err := NewServer(); err != ErrNilTLSConfig {
+		t.Fatalf("expected ErrNilTLSConfig, got %v", err)
+	}
+	if _, err := NewServer(WithTLSConfig(nil)); err != ErrNilTLSConfig {
+		t.Fatalf("expected ErrNilTLSConfig, got %v", err)
+	}
+}
+
+func TestClientSendNotDialed(t *testing.T) {
+	_, clientConfig := testTLSConfigs(t)
+	client, err := NewClient(WithTLSConfig(clientConfig))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := client.Send(osc.Message{Address: "/foo"}); err != ErrNotDialed {
+		t.Fatalf("expected ErrNotDialed, got %v", err)
+	}
+}
+
+func TestServerStarted(t *testing.T) {
+	server, client, errChan := testServerClient(t, osc.Dispatcher{
+		"/foo": osc.Method(func(msg osc.Message) error { return nil }),
+	})
+	defer func() { _ = client.Close() }() // Best effort.
+
+	if err := server.ListenAndServe("127.0.0.1:0", osc.Dispatcher{}); err != ErrStarted {
+		t.Fatalf("expected ErrStarted, got %v", err)
+	}
+	if err := server.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := <-errChan; err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestServerMessage(t *testing.T) {
+	received := make(chan osc.Message, 1)
+
+	server, client, errChan := testServerClient(t, osc.Dispatcher{
+		"/foo": osc.Method(func(msg osc.Message) error {
+			received <- msg
+			return nil
+		}),
+	})
+	defer func() { _ = client.Close() }() // Best effort.
+
+	expected := osc.Message{Address: "/foo", Arguments: []osc.Argument{osc.Int(3)}}
+
+	// Datagrams are unreliable so we retry until one gets through.
+	ticker := time.NewTicker(20 * time.Millisecond)
+	defer ticker.Stop()
+	timeout := time.After(2 * time.Second)
+
+ReadLoop:
+	for {
+		if err := client.Send(expected); err != nil {
+			t.Fatal(err)
+		}
+		select {
+		case got := <-received:
+			if !expected.Equal(got) {
+				t.Fatalf("expected %v, got %v", expected, got)
+			}
+			break ReadLoop
+		case <-ticker.C:
+		case <-timeout:
+			t.Fatal("timeout")
+		}
+	}
+	if err := server.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := <-errChan; err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestServerLargeMessage(t *testing.T) {
+	received := make(chan osc.Message, 1)
+
+	server, client, errChan := testServerClient(t, osc.Dispatcher{
+		"/foo": osc.Method(func(msg osc.Message) error {
+			received <- msg
+			return nil
+		}),
+	})
+	defer func() { _ = client.Close() }() // Best effort.
+
+	// Too large for a datagram, so it is sent on a stream.
+	expected := osc.Message{Address: "/foo", Arguments: []osc.Argument{osc.Blob(make([]byte, 4096))}}
+	if err := client.Send(expected); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case got := <-received:
+		if !expected.Equal(got) {
+			t.Fatalf("expected %v, got %v", expected, got)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("timeout")
+	}
+	if err := server.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := <-errChan; err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestServerBundle(t *testing.T) {
+	received := make(chan osc.Message, 2)
+
+	server, client, errChan := testServerClient(t, osc.Dispatcher{
+		"/foo": osc.Method(func(msg osc.Message) error {
+			received <- msg
+			return nil
+		}),
+	})
+	defer func() { _ = client.Close() }() // Best effort.
+
+	bundle := osc.Bundle{
+		Timetag: osc.Immediately,
+		Packets: []osc.Packet{
+			osc.Message{Address: "/foo", Arguments: []osc.Argument{osc.Int(1)}},
+			osc.Message{Address: "/foo", Arguments: []osc.Argument{osc.Int(2)}},
+		},
+	}
+	if err := client.Send(bundle); err != nil {
+		t.Fatal(err)
+	}
+	for i, expected := range bundle.Packets {
+		select {
+		case got := <-received:
+			if !expected.Equal(got) {
+				t.Fatalf("(message %d) expected %v, got %v", i, expected, got)
+			}
+		case <-time.After(2 * time.Second):
+			t.Fatal("timeout")
+		}
+	}
+	if err := server.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := <-errChan; err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestServerMaxPacketSize(t *testing.T) {
+	errs := make(chan error, 1)
+
+	server, client, _ := testServerClient(t, osc.Dispatcher{
+		"/foo": osc.Method(func(msg osc.Message) error { return nil }),
+	}, WithMaxPacketSize(32), WithErrorHandler(func(data []byte, sender net.Addr, err error) {
+		errs <- err
+	}))
+	defer func() { _ = server.Close() }() // Best effort.
+	defer func() { _ = client.Close() }() // Best effort.
+
+	bundle := osc.Bundle{
+		Timetag: osc.Immediately,
+		Packets: []osc.Packet{osc.Message{Address: "/foo", Arguments: []osc.Argument{osc.String("this is too long")}}},
+	}
+	if err := client.SendReliable(bundle); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case err := <-errs:
+		if errors.Cause(err) != osc.ErrPacketTooLarge {
+			t.Fatalf("expected ErrPacketTooLarge, got %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("timeout")
+	}
+}
+
+func TestServerParseError(t *testing.T) {
+	var (
+		errs     = make(chan error, 1)
+		received = make(chan osc.Message, 1)
+	)
+	serverConfig, clientConfig := testTLSConfigs(t)
+
+	server, err := NewServer(WithTLSConfig(serverConfig), WithErrorHandler(func(data []byte, sender net.Addr, err error) {
+		errs <- err
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	errChan := make(chan error, 1)
+	go func() {
+		errChan <- server.ListenAndServe("127.0.0.1:0", osc.Dispatcher{
+			"/foo": osc.Method(func(msg osc.Message) error {
+				received <- msg
+				return nil
+			}),
+		})
+	}()
+	dial := func() *Client {
+		client, err := NewClient(WithTLSConfig(clientConfig))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := client.Dial(server.Addr().String()); err != nil {
+			t.Fatal(err)
+		}
+		return client
+	}
+
+	// One peer sends garbage.
+	bad := dial()
+	defer func() { _ = bad.Close() }() // Best effort.
+
+	// Datagrams are unreliable so we retry until one gets through.
+	ticker := time.NewTicker(20 * time.Millisecond)
+	defer ticker.Stop()
+	timeout := time.After(2 * time.Second)
+
+ErrLoop:
+	for {
+		if err := bad.conn.SendDatagram([]byte("garbage")); err != nil {
+			t.Fatal(err)
+		}
+		select {
+		case err := <-errs:
+			if err == nil {
+				t.Fatal("expected an error")
+			}
+			break ErrLoop
+		case err := <-errChan:
+			t.Fatalf("expected the server to keep serving, got %v", err)
+		case <-ticker.C:
+		case <-timeout:
+			t.Fatal("timeout")
+		}
+	}
+
+	// Another peer is still served.
+	good := dial()
+	defer func() { _ = good.Close() }() // Best effort.
+
+	expected := osc.Message{Address: "/foo", Arguments: []osc.Argument{osc.Int(1)}}
+	if err := good.SendReliable(expected); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case got := <-received:
+		if !expected.Equal(got) {
+			t.Fatalf("expected %v, got %v", expected, got)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("timeout")
+	}
+	if err := server.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := <-errChan; err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,149 @@
+package osc
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// DefaultMaxRateAddresses is the default number of addresses a RateCounter keeps track of.
+const DefaultMaxRateAddresses = 10000
+
+// RateCounter counts the messages received per address over a sliding window,
+// e.g. for monitoring or throttling.
+// It is safe for concurrent use.
+type RateCounter struct {
+	maxWindow time.Duration
+	now       func() time.Time
+
+	mu           sync.Mutex
+	maxAddresses int
+	observed     map[string]*timestamps
+}
+
+// timestamps is a queue of the times at which messages were observed, oldest first.
+type timestamps struct {
+	times []time.Time
+	head  int
+}
+
+// NewRateCounter creates a rate counter that remembers messages for maxWindow.
+// Rates can be computed for windows up to maxWindow.
+func NewRateCounter(maxWindow time.Duration) *RateCounter {
+	return &RateCounter{
+		maxWindow:    maxWindow,
+		now:          time.Now,
+		maxAddresses: DefaultMaxRateAddresses,
+		observed:     map[string]*timestamps{},
+	}
+}
+
+// SetMaxAddresses sets the number of addresses the counter keeps track of.
+// Messages for new addresses are not counted while that many addresses
+// have been observed within the counter's window.
+func (rc *RateCounter) SetMaxAddresses(n int) {
+	rc.mu.Lock()
+	rc.maxAddresses = n
+	rc.mu.Unlock()
+}
+
+// Observe counts a message.
+func (rc *RateCounter) Observe(msg Message) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	now := rc.now()
+	ts, ok := rc.observed[msg.Address]
+	if !ok {
+		if len(rc.observed) >= rc.maxAddresses {
+			rc.expireAll(now)
+		}
+		if len(rc.observed) >= rc.maxAddresses {
+			return
+		}
+		ts = &timestamps{}
+		rc.observed[msg.Address] = ts
+	}
+	ts.expire(now.Add(-rc.maxWindow))
+	ts.times = append(ts.times, now)
+}
+
+// Rate returns the number of messages per second observed for address within window.
+// Windows longer than the counter's maximum window are shortened to it.
+func (rc *RateCounter) Rate(address string, window time.Duration) float64 {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	ts, ok := rc.observed[address]
+	if !ok {
+		return 0
+	}
+	return rc.rate(ts, rc.now(), window)
+}
+
+// AllRates returns the number of messages per second observed within window
+// for every address that was observed within the counter's window.
+func (rc *RateCounter) AllRates(window time.Duration) map[string]float64 {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	now := rc.now()
+	rc.expireAll(now)
+
+	rates := make(map[string]float64, len(rc.observed))
+	for address, ts := range rc.observed {
+		rates[address] = rc.rate(ts, now, window)
+	}
+	return rates
+}
+
+// rate returns the number of messages per second in ts within window before now.
+func (rc *RateCounter) rate(ts *timestamps, now time.Time, window time.Duration) float64 {
+	if window > rc.maxWindow {
+		window = rc.maxWindow
+	}
+	if window <= 0 {
+		return 0
+	}
+	return float64(ts.countAfter(now.Add(-window))) / window.Seconds()
+}
+
+// expireAll forgets the messages observed longer than the counter's window ago,
+// and the addresses that have no messages left.
+func (rc *RateCounter) expireAll(now time.Time) {
+	for address, ts := range rc.observed {
+		if ts.expire(now.Add(-rc.maxWindow)); ts.len() == 0 {
+			delete(rc.observed, address)
+		}
+	}
+}
+
+// expire removes the timestamps that are not after t.
+func (ts *timestamps) expire(t time.Time) {
+	ts.head += ts.countNotAfter(t)
+
+	// Reuse the slice once most of it has expired.
+	if ts.head > len(ts.times)/2 {
+		n := copy(ts.times, ts.times[ts.head:])
+		ts.times = ts.times[:n]
+		ts.head = 0
+	}
+}
+
+// countAfter returns the number of timestamps after t.
+func (ts *timestamps) countAfter(t time.Time) int {
+	return ts.len() - ts.countNotAfter(t)
+}
+
+// countNotAfter returns the number of timestamps that are not after t.
+func (ts *timestamps) countNotAfter(t time.Time) int {
+	live := ts.times[ts.head:]
+	return sort.Search(len(live), func(i int) bool {
+		return live[i].After(t)
+	})
+}
+
+// len returns the number of timestamps in the queue.
+func (ts *timestamps) len() int {
+	return len(ts.times) - ts.head
+}
@@ -0,0 +1,80 @@
+package osc
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRateCounter(t *testing.T) {
+	var (
+		now = time.Now()
+		rc  = NewRateCounter(10 * time.Second)
+		foo = Message{Address: "/foo"}
+		bar = Message{Address: "/bar"}
+	)
+	rc.now = func() time.Time { return now }
+
+	for i := 0; i < 20; i++ {
+		now = now.Add(500 * time.Millisecond)
+		rc.Observe(foo)
+	}
+	rc.Observe(bar)
+
+	// Observed 20 /foo messages over the last 10 seconds.
+	if expected, got := 2.0, rc.Rate("/foo", 10*time.Second); expected != got {
+		t.Fatalf("expected %f, got %f", expected, got)
+	}
+	if expected, got := 2.0, rc.Rate("/foo", time.Second); expected != got {
+		t.Fatalf("expected %f, got %f", expected, got)
+	}
+	// Windows are capped at the maximum window.
+	if expected, got := 2.0, rc.Rate("/foo", time.Minute); expected != got {
+		t.Fatalf("expected %f, got %f", expected, got)
+	}
+	if expected, got := 0.0, rc.Rate("/baz", time.Second); expected != got {
+		t.Fatalf("expected %f, got %f", expected, got)
+	}
+	rates := rc.AllRates(time.Second)
+	if expected, got := 2, len(rates); expected != got {
+		t.Fatalf("expected %d rates, got %d", expected, got)
+	}
+	if expected, got := 1.0, rates["/bar"]; expected != got {
+		t.Fatalf("expected %f, got %f", expected, got)
+	}
+
+	// Half of the /foo messages have left the window.
+	now = now.Add(5 * time.Second)
+	if expected, got := 1.0, rc.Rate("/foo", 10*time.Second); expected != got {
+		t.Fatalf("expected %f, got %f", expected, got)
+	}
+	if expected, got := 0.1, rc.Rate("/bar", 10*time.Second); expected != got {
+		t.Fatalf("expected %f, got %f", expected, got)
+	}
+	now = now.Add(5 * time.Second)
+	if expected, got := 0, len(rc.AllRates(time.Second)); expected != got {
+		t.Fatalf("expected all addresses to be forgotten, got %d", got)
+	}
+}
+
+func TestRateCounterMaxAddresses(t *testing.T) {
+	var (
+		now = time.Now()
+		rc  = NewRateCounter(time.Second)
+	)
+	rc.now = func() time.Time { return now }
+	rc.SetMaxAddresses(1)
+
+	rc.Observe(Message{Address: "/foo"})
+	rc.Observe(Message{Address: "/bar"})
+
+	if expected, got := 0.0, rc.Rate("/bar", time.Second); expected != got {
+		t.Fatalf("expected %f, got %f", expected, got)
+	}
+	// /bar is counted once /foo has expired.
+	now = now.Add(time.Second)
+	rc.Observe(Message{Address: "/bar"})
+
+	if expected, got := 1.0, rc.Rate("/bar", time.Second); expected != got {
+		t.Fatalf("expected %f, got %f", expected, got)
+	}
+}
@@ -0,0 +1,119 @@
+package osc
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+
+	"github.com/pkg/errors"
+)
+
+// ReaderBlob is a blob argument whose bytes are read from an io.Reader
+// when the message is written, so large blobs don't have to be held in memory.
+// The length has to be known up front because it precedes the bytes on the wire.
+// The reader is consumed the first time the blob is written or read,
+// so a ReaderBlob can only be sent once.
+type ReaderBlob struct {
+	r   io.Reader
+	len int
+}
+
+// NewReaderBlob creates a blob argument that reads n bytes from r.
+func NewReaderBlob(r io.Reader, n int) *ReaderBlob {
+	return &ReaderBlob{r: r, len: n}
+}
+
+// Len returns the length of the blob in bytes, without the length prefix and padding.
+func (rb *ReaderBlob) Len() int {
+	return rb.len
+}
+
+// PaddedLen returns the number of bytes the blob occupies when encoded,
+// including the length prefix.
+func (rb *ReaderBlob) PaddedLen() int {
+	return 4 + paddedLen(rb.len)
+}
+
+// Bytes reads the blob from the reader and returns its OSC encoding.
+// If the reader fails or has fewer than Len bytes the missing bytes are zero,
+// so the encoding always has the declared length. Use MarshalTo or WriteBinary
+// to encode a message with a ReaderBlob if read errors have to be detected.
+func (rb *ReaderBlob) Bytes() []byte {
+	b := make([]byte, rb.len)
+	_, _ = io.ReadFull(rb.r, b) // Missing bytes stay zero.
+	return Blob(b).Bytes()
+}
+
+// WriteBinary copies the blob's OSC encoding to w without materializing it:
+// its int32 length, followed by its bytes padded with null bytes to a multiple of 4.
+func (rb *ReaderBlob) WriteBinary(w io.Writer) (int64, error) {
+	var (
+		padding [3]byte
+		n       int64
+	)
+	if err := binary.Write(w, byteOrder, int32(rb.len)); err != nil {
+		return 0, errors.Wrap(err, "write blob length")
+	}
+	n += 4
+
+	nw, err := rb.WriteTo(w)
+	n += nw
+	if err != nil {
+		return n, err
+	}
+	np, err := w.Write(padding[:paddedLen(rb.len)-rb.len])
+	n += int64(np)
+	if err != nil {
+		return n, errors.Wrap(err, "write blob padding")
+	}
+	return n, nil
+}
+
+// Equal returns true if other is the same reader blob.
+// Reader blobs are never equal to other blobs since comparing them would consume the reader.
+func (rb *ReaderBlob) Equal(other Argument) bool {
+	rb2, ok := other.(*ReaderBlob)
+	return ok && rb == rb2
+}
+
+// ReadInt32 reads a 32-bit integer from the arg.
+func (rb *ReaderBlob) ReadInt32() (int32, error) { return 0, ErrInvalidTypeTag }
+
+// ReadInt64 reads a 64-bit integer from the arg.
+func (rb *ReaderBlob) ReadInt64() (int64, error) { return 0, ErrInvalidTypeTag }
+
+// ReadFloat32 reads a 32-bit float from the arg.
+func (rb *ReaderBlob) ReadFloat32() (float32, error) { return 0, ErrInvalidTypeTag }
+
+// ReadBool bool reads a boolean from the arg.
+func (rb *ReaderBlob) ReadBool() (bool, error) { return false, ErrInvalidTypeTag }
+
+// ReadString string reads a string from the arg.
+func (rb *ReaderBlob) ReadString() (string, error) { return "", ErrInvalidTypeTag }
+
+// ReadBlob reads all the bytes of the blob from the reader.
+func (rb *ReaderBlob) ReadBlob() ([]byte, error) {
+	b := make([]byte, rb.len)
+	if _, err := io.ReadFull(rb.r, b); err != nil {
+		return nil, errors.Wrap(err, "read blob")
+	}
+	return b, nil
+}
+
+// String converts the arg to a string.
+func (rb *ReaderBlob) String() string { return fmt.Sprintf("ReaderBlob(%d bytes)", rb.len) }
+
+// Typetag returns the argument's type tag.
+func (rb *ReaderBlob) Typetag() byte { return TypetagBlob }
+
+// Size returns the number of bytes of the encoded blob, see PaddedLen.
+func (rb *ReaderBlob) Size() int { return rb.PaddedLen() }
+
+// WriteTo copies the bytes of the blob from the reader to w.
+func (rb *ReaderBlob) WriteTo(w io.Writer) (int64, error) {
+	n, err := io.CopyN(w, rb.r, int64(rb.len))
+	if err == io.EOF {
+		err = io.ErrUnexpectedEOF
+	}
+	return n, errors.Wrap(err, "copy blob")
+}
@@ -0,0 +1,110 @@
+package osc
+
+import (
+	"bytes"
+	"io"
+	"math/rand"
+	"testing"
+
+	"github.com/pkg/errors"
+)
+
+func TestReaderBlobWriteBinary(t *testing.T) {
+	data := make([]byte, 3<<20+3)
+	rand.New(rand.NewSource(1)).Read(data)
+
+	var (
+		streamed = Message{
+			Address:   "/audio/chunk",
+			Arguments: []Argument{Int(7), NewReaderBlob(bytes.NewReader(data), len(data)), String("end")},
+		}
+		materialized = Message{
+			Address:   "/audio/chunk",
+			Arguments: []Argument{Int(7), Blob(data), String("end")},
+		}
+		buf = &bytes.Buffer{}
+	)
+	n, err := streamed.WriteBinary(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := materialized.Bytes()
+	if got := buf.Bytes(); !bytes.Equal(expected, got) {
+		t.Fatal("streamed message does not match materialized message")
+	}
+	if expected, got := int64(len(expected)), n; expected != got {
+		t.Fatalf("expected %d bytes written, got %d", expected, got)
+	}
+	if expected, got := materialized.EncodedLen(), streamed.EncodedLen(); expected != got {
+		t.Fatalf("expected encoded length %d, got %d", expected, got)
+	}
+}
+
+func TestReaderBlobMarshalTo(t *testing.T) {
+	data := []byte{1, 2, 3, 4, 5}
+	msg := Message{
+		Address:   "/blob",
+		Arguments: []Argument{NewReaderBlob(bytes.NewReader(data), len(data))},
+	}
+	buf := make([]byte, msg.EncodedLen())
+	n, err := msg.MarshalTo(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := Message{Address: "/blob", Arguments: []Argument{Blob(data)}}.Bytes()
+	if got := buf[:n]; !bytes.Equal(expected, got) {
+		t.Fatalf("expected %v, got %v", expected, got)
+	}
+}
+
+func TestReaderBlobShortReader(t *testing.T) {
+	newMsg := func() Message {
+		return Message{
+			Address:   "/blob",
+			Arguments: []Argument{NewReaderBlob(bytes.NewReader([]byte{1, 2}), 4)},
+		}
+	}
+	if _, err := newMsg().WriteBinary(&bytes.Buffer{}); errors.Cause(err) != io.ErrUnexpectedEOF {
+		t.Fatalf("expected io.ErrUnexpectedEOF, got %v", err)
+	}
+	msg := newMsg()
+	if _, err := msg.MarshalTo(make([]byte, msg.EncodedLen())); errors.Cause(err) != io.ErrUnexpectedEOF {
+		t.Fatalf("expected io.ErrUnexpectedEOF, got %v", err)
+	}
+	if expected, got := Blob([]byte{1, 2, 0, 0}).Bytes(), newMsg().Arguments[0].Bytes(); !bytes.Equal(expected, got) {
+		t.Fatalf("expected %v, got %v", expected, got)
+	}
+	if expected, got := Blob([]byte{1, 2, 0, 0}).Bytes(), newMsg().Bytes()[12:]; !bytes.Equal(expected, got) {
+		t.Fatalf("expected %v, got %v", expected, got)
+	}
+}
+
+func TestReaderBlobBytes(t *testing.T) {
+	data := []byte{1, 2, 3}
+	rb := NewReaderBlob(bytes.NewReader(data), len(data))
+	if expected, got := Blob(data).Bytes(), rb.Bytes(); !bytes.Equal(expected, got) {
+		t.Fatalf("expected %v, got %v", expected, got)
+	}
+	if expected, got := TypetagBlob, rb.Typetag(); expected != got {
+		t.Fatalf("expected %c, got %c", expected, got)
+	}
+	if !rb.Equal(rb) {
+		t.Fatal("expected reader blob to equal itself")
+	}
+	if rb.Equal(Blob(data)) {
+		t.Fatal("expected reader blob not to equal a blob")
+	}
+}
+
+func TestMessageWriteBinaryError(t *testing.T) {
+	data := []byte{1, 2, 3}
+	for i := 1; i <= 5; i++ {
+		msg := Message{
+			Address:   "/foo",
+			Arguments: []Argument{Int(1), NewReaderBlob(bytes.NewReader(data), len(data))},
+		}
+		if _, err := msg.WriteBinary(&errWriter{erridx: i}); err == nil {
+			t.Fatalf("(write %d) expected error, got nil", i)
+		}
+	}
+}
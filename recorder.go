@@ -0,0 +1,61 @@
+package osc
+
+import (
+	"sync"
+	"time"
+)
+
+// RecordedMessage is a message recorded by a Recorder.
+type RecordedMessage struct {
+	Time    time.Time
+	Message Message
+}
+
+// Recorder records messages along with the time they were received,
+// e.g. to capture a session for later analysis or playback.
+// It is safe for concurrent use.
+type Recorder struct {
+	now func() time.Time
+
+	mu       sync.Mutex
+	messages []RecordedMessage
+}
+
+// NewRecorder creates an empty recorder.
+func NewRecorder() *Recorder {
+	return &Recorder{now: time.Now}
+}
+
+// Record records a message at the current time.
+func (r *Recorder) Record(msg Message) {
+	r.add(RecordedMessage{Time: r.now(), Message: msg})
+}
+
+// Transform is a Transformer that records every message and passes it on unchanged.
+func (r *Recorder) Transform(msg Message) (Message, bool, error) {
+	r.Record(msg)
+	return msg, true, nil
+}
+
+// Messages returns the recorded messages in the order they were recorded.
+func (r *Recorder) Messages() []RecordedMessage {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return append([]RecordedMessage{}, r.messages...)
+}
+
+// Len returns the number of recorded messages.
+func (r *Recorder) Len() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return len(r.messages)
+}
+
+// add adds a recorded message.
+func (r *Recorder) add(rm RecordedMessage) {
+	r.mu.Lock()
+	r.messages = append(r.messages, rm)
+	r.mu.Unlock()
+}
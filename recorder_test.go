@@ -0,0 +1,39 @@
+package osc
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRecorder(t *testing.T) {
+	var (
+		now = time.Now()
+		r   = NewRecorder()
+		msg = Message{Address: "/foo", Arguments: []Argument{Int(1)}}
+	)
+	r.now = func() time.Time { return now }
+
+	r.Record(msg)
+	now = now.Add(time.Second)
+
+	transformed, ok, err := r.Transform(Message{Address: "/bar"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ok {
+		t.Fatal("expected message not to be dropped")
+	}
+	if expected, got := "/bar", transformed.Address; expected != got {
+		t.Fatalf("expected %s, got %s", expected, got)
+	}
+	messages := r.Messages()
+	if expected, got := 2, len(messages); expected != got {
+		t.Fatalf("expected %d messages, got %d", expected, got)
+	}
+	if !messages[0].Message.Equal(msg) {
+		t.Fatalf("expected %v, got %v", msg, messages[0].Message)
+	}
+	if expected, got := now, messages[1].Time; !expected.Equal(got) {
+		t.Fatalf("expected %s, got %s", expected, got)
+	}
+}
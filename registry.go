@@ -0,0 +1,103 @@
+package osc
+
+import (
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// Common errors.
+var (
+	ErrBuiltinTypeTag = errors.New("type tag is built in")
+)
+
+// ArgumentDecoder decodes an argument from data and returns
+// the number of bytes that were consumed.
+type ArgumentDecoder func(data []byte) (Argument, int64, error)
+
+// ArgumentEncoder returns the OSC encoding of an argument.
+type ArgumentEncoder func(a Argument) []byte
+
+// customType is a registered custom type.
+type customType struct {
+	decode ArgumentDecoder
+	encode ArgumentEncoder
+}
+
+var (
+	registryMu sync.RWMutex
+	registry   = map[byte]customType{}
+)
+
+// RegisterType registers a decoder and an encoder for a custom type tag.
+// ReadArgument uses the decoder for arguments with that type tag,
+// and messages use the encoder to encode them, see EncodeArgument.
+// If encode is nil, arguments are encoded with their Bytes method.
+// Either way the decoder must be able to read what is encoded.
+// Built-in type tags can not be registered.
+// Registering a type tag again replaces its decoder and encoder.
+func RegisterType(tag byte, decode ArgumentDecoder, encode ArgumentEncoder) error {
+	if isBuiltinTypeTag(tag) {
+		return errors.Wrapf(ErrBuiltinTypeTag, "typetag %q", string(tag))
+	}
+	registryMu.Lock()
+	registry[tag] = customType{decode: decode, encode: encode}
+	registryMu.Unlock()
+	return nil
+}
+
+// EncodeArgument returns the OSC encoding of an argument.
+// Arguments of custom types are encoded with the encoder registered
+// for their type tag if there is one, see RegisterType,
+// all other arguments with their Bytes method.
+func EncodeArgument(a Argument) []byte {
+	if encode, ok := lookupEncoder(a.Typetag()); ok {
+		return encode(a)
+	}
+	return a.Bytes()
+}
+
+// argumentSize returns the length of the encoding of an argument, see EncodeArgument.
+// It only allocates for arguments of custom types with a registered encoder.
+func argumentSize(a Argument) int {
+	if encode, ok := lookupEncoder(a.Typetag()); ok {
+		return len(encode(a))
+	}
+	return a.Size()
+}
+
+// UnregisterType removes the decoder and encoder for a custom type tag.
+func UnregisterType(tag byte) {
+	registryMu.Lock()
+	delete(registry, tag)
+	registryMu.Unlock()
+}
+
+// lookupType returns the decoder for a custom type tag.
+func lookupType(tag byte) (ArgumentDecoder, bool) {
+	registryMu.RLock()
+	ct, ok := registry[tag]
+	registryMu.RUnlock()
+	return ct.decode, ok
+}
+
+// lookupEncoder returns the encoder for a custom type tag.
+// Built-in type tags never have one.
+func lookupEncoder(tag byte) (ArgumentEncoder, bool) {
+	if isBuiltinTypeTag(tag) {
+		return nil, false
+	}
+	registryMu.RLock()
+	ct := registry[tag]
+	registryMu.RUnlock()
+	return ct.encode, ct.encode != nil
+}
+
+// isBuiltinTypeTag returns true if the package knows how to read the type tag.
+func isBuiltinTypeTag(tag byte) bool {
+	switch tag {
+	case TypetagInt, TypetagFloat, TypetagString, TypetagBlob, TypetagFalse, TypetagTrue, TypetagUInt32, TypetagColor, TypetagTimetag, TypetagInt64:
+		return true
+	}
+	return false
+}
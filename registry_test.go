@@ -0,0 +1,126 @@
+package osc
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/pkg/errors"
+)
+
+// rgba is a custom argument type used to test the type registry.
+type rgba uint32
+
+func (c rgba) Bytes() []byte {
+	b := make([]byte, 4)
+	binary.BigEndian.PutUint32(b, uint32(c))
+	return b
+}
+func (c rgba) Equal(other Argument) bool {
+	c2, ok := other.(rgba)
+	return ok && c == c2
+}
+func (c rgba) Size() int                     { return 4 }
+func (c rgba) ReadInt32() (int32, error)     { return 0, ErrInvalidTypeTag }
+func (c rgba) ReadInt64() (int64, error)     { return 0, ErrInvalidTypeTag }
+func (c rgba) ReadFloat32() (float32, error) { return 0, ErrInvalidTypeTag }
+func (c rgba) ReadBool() (bool, error)       { return false, ErrInvalidTypeTag }
+func (c rgba) ReadString() (string, error)   { return "", ErrInvalidTypeTag }
+func (c rgba) ReadBlob() ([]byte, error)     { return nil, ErrInvalidTypeTag }
+func (c rgba) String() string                { return fmt.Sprintf("rgba(%08x)", uint32(c)) }
+func (c rgba) Typetag() byte                 { return 'x' }
+func (c rgba) WriteTo(w io.Writer) (int64, error) {
+	written, err := w.Write(c.Bytes())
+	return int64(written), err
+}
+
+func decodeRGBA(data []byte) (Argument, int64, error) {
+	if len(data) < 4 {
+		return nil, 0, errors.New("read rgba argument: EOF")
+	}
+	return rgba(binary.BigEndian.Uint32(data)), 4, nil
+}
+
+func TestRegisterType(t *testing.T) {
+	if err := RegisterType('x', decodeRGBA, nil); err != nil {
+		t.Fatal(err)
+	}
+	defer UnregisterType('x')
+
+	expected := Message{Address: "/color", Arguments: []Argument{Int(1), rgba(0xff8000ff)}}
+
+	got, err := ParseMessage(expected.Bytes(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !expected.Equal(got) {
+		t.Fatalf("expected %v, got %v", expected, got)
+	}
+}
+
+func TestRegisterTypeEncoder(t *testing.T) {
+	// Encode rgba little-endian, unlike its Bytes method.
+	var (
+		decode = func(data []byte) (Argument, int64, error) {
+			if len(data) < 4 {
+				return nil, 0, errors.New("read rgba argument: EOF")
+			}
+			return rgba(binary.LittleEndian.Uint32(data)), 4, nil
+		}
+		encode = func(a Argument) []byte {
+			b := make([]byte, 4)
+			binary.LittleEndian.PutUint32(b, uint32(a.(rgba)))
+			return b
+		}
+	)
+	if err := RegisterType('x', decode, encode); err != nil {
+		t.Fatal(err)
+	}
+	defer UnregisterType('x')
+
+	msg := Message{Address: "/color", Arguments: []Argument{rgba(0xff8000ff)}}
+	data := msg.Bytes()
+	if expected, got := []byte{0xff, 0x00, 0x80, 0xff}, data[len(data)-4:]; !bytes.Equal(expected, got) {
+		t.Fatalf("expected %x, got %x", expected, got)
+	}
+	buf := make([]byte, msg.EncodedLen())
+	if _, err := msg.MarshalTo(buf); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(data, buf) {
+		t.Fatalf("expected %x, got %x", data, buf)
+	}
+	var w bytes.Buffer
+	if _, err := msg.WriteBinary(&w); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(data, w.Bytes()) {
+		t.Fatalf("expected %x, got %x", data, w.Bytes())
+	}
+	got, err := ParseMessage(data, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !msg.Equal(got) {
+		t.Fatalf("expected %v, got %v", msg, got)
+	}
+}
+
+func TestRegisterTypeBuiltin(t *testing.T) {
+	if err := RegisterType(TypetagInt, decodeRGBA, nil); errors.Cause(err) != ErrBuiltinTypeTag {
+		t.Fatalf("expected ErrBuiltinTypeTag, got %v", err)
+	}
+}
+
+func TestUnregisterType(t *testing.T) {
+	if err := RegisterType('x', decodeRGBA, nil); err != nil {
+		t.Fatal(err)
+	}
+	UnregisterType('x')
+
+	if _, _, err := ReadArgument('x', []byte{0, 0, 0, 1}); errors.Cause(err) != ErrInvalidTypeTag {
+		t.Fatalf("expected ErrInvalidTypeTag, got %v", err)
+	}
+}
@@ -0,0 +1,55 @@
+package osc
+
+import (
+	"context"
+	"net"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// SendWithRetry sends a packet, retrying up to attempts times in total if sending fails
+// with a temporary error or a timeout, see net.Error. It waits backoff before the first retry
+// and doubles the wait before every following retry. Other errors are returned right away,
+// and the last error is returned if all attempts fail. Fewer than one attempt means one.
+func (conn *UDPConn) SendWithRetry(p Packet, attempts int, backoff time.Duration) error {
+	return sendWithRetry(conn.ctx, conn.Send, p, attempts, backoff)
+}
+
+// SendWithRetry sends a packet, retrying up to attempts times in total if sending fails
+// with a temporary error or a timeout, see net.Error. It waits backoff before the first retry
+// and doubles the wait before every following retry. Other errors are returned right away,
+// and the last error is returned if all attempts fail. Fewer than one attempt means one.
+func (conn *UnixConn) SendWithRetry(p Packet, attempts int, backoff time.Duration) error {
+	return sendWithRetry(conn.ctx, conn.Send, p, attempts, backoff)
+}
+
+// sendWithRetry calls send until it succeeds, fails with an error that is not transient,
+// attempts have been made, or ctx is done. It makes at least one attempt.
+func sendWithRetry(ctx context.Context, send func(Packet) error, p Packet, attempts int, backoff time.Duration) error {
+	if attempts < 1 {
+		attempts = 1
+	}
+	var err error
+	for i := 0; i < attempts; i++ {
+		if i > 0 {
+			timer := time.NewTimer(backoff << uint(i-1))
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return errors.Wrapf(ctx.Err(), "send attempt %d", i+1)
+			case <-timer.C:
+			}
+		}
+		if err = send(p); err == nil || !isTransient(err) {
+			return err
+		}
+	}
+	return errors.Wrapf(err, "send failed after %d attempts", attempts)
+}
+
+// isTransient returns true if err is a temporary network error or a timeout.
+func isTransient(err error) bool {
+	ne, ok := errors.Cause(err).(net.Error)
+	return ok && (ne.Timeout() || ne.Temporary())
+}
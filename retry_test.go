@@ -0,0 +1,117 @@
+package osc
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// transientError is a temporary net.Error.
+type transientError struct{}
+
+func (transientError) Error() string   { return "transient" }
+func (transientError) Timeout() bool   { return false }
+func (transientError) Temporary() bool { return true }
+
+var _ net.Error = transientError{}
+
+// failingSender fails the first n sends with err.
+type failingSender struct {
+	n     int
+	err   error
+	sends int
+}
+
+func (fs *failingSender) Send(p Packet) error {
+	fs.sends++
+	if fs.sends <= fs.n {
+		return fs.err
+	}
+	return nil
+}
+
+func TestSendWithRetry(t *testing.T) {
+	fs := &failingSender{n: 2, err: transientError{}}
+	if err := sendWithRetry(context.Background(), fs.Send, Message{Address: "/foo"}, 3, time.Millisecond); err != nil {
+		t.Fatal(err)
+	}
+	if expected, got := 3, fs.sends; expected != got {
+		t.Fatalf("expected %d sends, got %d", expected, got)
+	}
+}
+
+func TestSendWithRetryNoAttempts(t *testing.T) {
+	for _, attempts := range []int{0, -1} {
+		fs := &failingSender{}
+		if err := sendWithRetry(context.Background(), fs.Send, Message{Address: "/foo"}, attempts, time.Millisecond); err != nil {
+			t.Fatalf("(%d attempts) %s", attempts, err)
+		}
+		if expected, got := 1, fs.sends; expected != got {
+			t.Fatalf("(%d attempts) expected %d sends, got %d", attempts, expected, got)
+		}
+	}
+}
+
+func TestSendWithRetryExhausted(t *testing.T) {
+	fs := &failingSender{n: 3, err: transientError{}}
+	err := sendWithRetry(context.Background(), fs.Send, Message{Address: "/foo"}, 3, time.Millisecond)
+	if expected, got := error(transientError{}), errors.Cause(err); expected != got {
+		t.Fatalf("expected %v, got %v", expected, got)
+	}
+	if expected, got := 3, fs.sends; expected != got {
+		t.Fatalf("expected %d sends, got %d", expected, got)
+	}
+}
+
+func TestSendWithRetryPermanent(t *testing.T) {
+	permanent := errors.New("permanent")
+	fs := &failingSender{n: 2, err: permanent}
+	if err := sendWithRetry(context.Background(), fs.Send, Message{Address: "/foo"}, 3, time.Millisecond); err != permanent {
+		t.Fatalf("expected %v, got %v", permanent, err)
+	}
+	if expected, got := 1, fs.sends; expected != got {
+		t.Fatalf("expected %d sends, got %d", expected, got)
+	}
+}
+
+func TestSendWithRetryCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	fs := &failingSender{n: 2, err: transientError{}}
+	if err := sendWithRetry(ctx, fs.Send, Message{Address: "/foo"}, 3, time.Minute); errors.Cause(err) != context.Canceled {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+	if expected, got := 1, fs.sends; expected != got {
+		t.Fatalf("expected %d sends, got %d", expected, got)
+	}
+}
+
+func TestUDPConnSendWithRetry(t *testing.T) {
+	laddr, err := net.ResolveUDPAddr("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	server, err := ListenUDP("udp", laddr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = server.Close() }() // Best effort.
+
+	raddr, err := net.ResolveUDPAddr("udp", server.LocalAddr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	conn, err := DialUDP("udp", nil, raddr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = conn.Close() }() // Best effort.
+
+	if err := conn.SendWithRetry(Message{Address: "/foo"}, 3, time.Millisecond); err != nil {
+		t.Fatal(err)
+	}
+}
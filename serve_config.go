@@ -0,0 +1,144 @@
+package osc
+
+import (
+	"log"
+	"net"
+	"time"
+)
+
+// serveConfig holds the options of the Serve method of a connection.
+// It is embedded in UDPConn and UnixConn so they share the setters.
+type serveConfig struct {
+	exactMatch      bool
+	caseInsensitive bool
+	interceptor     Transformer
+	transformers    []Transformer
+	maxArguments    int
+	onParseError    ParseErrorHandler
+	handlerTimeout  time.Duration
+	onDispatchError DispatchErrorHandler
+}
+
+// SetExactMatch changes the behavior of the Serve method so that
+// messages will only be dispatched to methods whose addresses
+// match the message's address exactly.
+// This should provide some performance improvement.
+func (c *serveConfig) SetExactMatch(value bool) {
+	c.exactMatch = value
+}
+
+// SetCaseInsensitive changes the behavior of the Serve method so that
+// case is ignored when matching message addresses to methods.
+// Note that OSC addresses are normally case-sensitive.
+func (c *serveConfig) SetCaseInsensitive(value bool) {
+	c.caseInsensitive = value
+}
+
+// matchOptions returns the options used to match message addresses to methods.
+func (c *serveConfig) matchOptions() MatchOptions {
+	return MatchOptions{Exact: c.exactMatch, CaseInsensitive: c.caseInsensitive}
+}
+
+// AddTransformer adds a transformer that the Serve method applies to every
+// message before it is dispatched. Transformers are applied in the order they were added,
+// and a message dropped by a transformer is not dispatched.
+// An error returned by a transformer is returned from Serve.
+// Transformers must be added before calling Serve.
+func (c *serveConfig) AddTransformer(t Transformer) {
+	c.transformers = append(c.transformers, t)
+}
+
+// SetInterceptor sets a transformer that the Serve method applies to every message
+// before any other transformer and before the message is matched against the dispatcher,
+// e.g. to strip authentication tokens or rewrite addresses.
+// Setting an interceptor replaces the previous one, and nil removes it.
+// It must be called before calling Serve.
+func (c *serveConfig) SetInterceptor(t Transformer) {
+	c.interceptor = t
+}
+
+// SetMaxArguments sets the maximum number of arguments of the messages the Serve method
+// accepts, which protects the server from messages that are slow to parse.
+// Messages with more arguments are treated as parse errors, see SetErrorHandler.
+// Zero means DefaultMaxArguments and a negative value means no limit, see ParseOptions.
+// It must be called before calling Serve.
+func (c *serveConfig) SetMaxArguments(n int) {
+	c.maxArguments = n
+}
+
+// SetErrorHandler sets a function that the Serve method calls for every
+// packet that can not be parsed. By default a warning is logged and the packet is dropped,
+// so a malformed packet does not stop serving. It must be called before calling Serve.
+func (c *serveConfig) SetErrorHandler(fn ParseErrorHandler) {
+	c.onParseError = fn
+}
+
+// SetHandlerTimeout limits how long the Serve method waits for the handlers of a packet.
+// The handlers are passed a context that is done after d, see Message.Context.
+// Handlers that take longer keep running in the background while serving continues
+// with an error wrapping ErrHandlerTimeout, which is passed to the dispatch error handler
+// or returned from Serve, see SetDispatchErrorHandler. Shutdown waits for them to return.
+// Zero means no timeout. It must be called before calling Serve.
+func (c *serveConfig) SetHandlerTimeout(d time.Duration) {
+	c.handlerTimeout = d
+}
+
+// SetDispatchErrorHandler sets a function that the Serve method calls for errors returned
+// by handlers and for handler timeouts. By default these errors are returned from Serve,
+// which stops serving. It must be called before calling Serve.
+func (c *serveConfig) SetDispatchErrorHandler(fn DispatchErrorHandler) {
+	c.onDispatchError = fn
+}
+
+// SetDeduplication makes the Serve method drop messages that are equal
+// to a message received less than window ago, see DeduplicateFilter.
+// It must be called before calling Serve.
+func (c *serveConfig) SetDeduplication(window time.Duration) {
+	c.AddTransformer(NewDeduplicateFilter(window).Transform)
+}
+
+// SetSenderState makes the Serve method attach a HandlerContext to every message,
+// so handlers can keep state per sender, see Message.HandlerContext.
+// The state of a sender is forgotten when nothing was received from it for idleTimeout,
+// zero means it is never forgotten. It must be called before calling Serve.
+func (c *serveConfig) SetSenderState(idleTimeout time.Duration) {
+	c.AddTransformer(NewSenderState(idleTimeout).Transform)
+}
+
+// serveOptions returns the options used by the Serve method.
+func (c *serveConfig) serveOptions() serveOptions {
+	return serveOptions{
+		match:           c.matchOptions(),
+		transform:       c.transformer(),
+		parse:           ParseOptions{MaxArguments: c.maxArguments},
+		onParseError:    c.parseErrorHandler(),
+		handlerTimeout:  c.handlerTimeout,
+		onDispatchError: c.onDispatchError,
+	}
+}
+
+// transformer returns the transformer used by the Serve method.
+func (c *serveConfig) transformer() Transformer {
+	transformers := c.transformers
+	if c.interceptor != nil {
+		transformers = append([]Transformer{c.interceptor}, transformers...)
+	}
+	if len(transformers) == 0 {
+		return nil
+	}
+	return Chain(transformers...)
+}
+
+// parseErrorHandler returns the parse error handler used by the Serve method.
+func (c *serveConfig) parseErrorHandler() ParseErrorHandler {
+	if c.onParseError == nil {
+		return logParseError
+	}
+	return c.onParseError
+}
+
+// logParseError is the default parse error handler of the Serve method.
+// It logs a warning and drops the packet.
+func logParseError(data []byte, sender net.Addr, err error) {
+	log.Printf("WARN osc: dropping %d byte packet from %v: %s", len(data), sender, err)
+}
@@ -0,0 +1,155 @@
+package osc
+
+import (
+	"bufio"
+	"encoding/binary"
+	"io"
+	"net"
+
+	"github.com/pkg/errors"
+)
+
+// Encoder writes packets to a stream.
+// Each packet is prefixed with its int32 size, which is the framing
+// the OSC 1.0 spec describes for stream-oriented protocols.
+type Encoder struct {
+	w io.Writer
+}
+
+// NewEncoder returns an encoder that writes to w.
+func NewEncoder(w io.Writer) *Encoder {
+	return &Encoder{w: w}
+}
+
+// Encode writes a packet to the stream.
+func (e *Encoder) Encode(p Packet) error {
+	data := p.Bytes()
+	if err := binary.Write(e.w, byteOrder, int32(len(data))); err != nil {
+		return errors.Wrap(err, "write packet size")
+	}
+	if _, err := e.w.Write(data); err != nil {
+		return errors.Wrap(err, "write packet")
+	}
+	return nil
+}
+
+// Decoder reads packets that were written by an Encoder from a stream.
+type Decoder struct {
+	r       io.Reader
+	maxSize int
+	sender  net.Addr
+}
+
+// NewDecoder returns a decoder that reads from r.
+// Packets larger than DefaultMaxPacketSize are rejected, see SetMaxPacketSize.
+func NewDecoder(r io.Reader) *Decoder {
+	return &Decoder{r: bufio.NewReader(r), maxSize: DefaultMaxPacketSize}
+}
+
+// SetMaxPacketSize sets the size of the largest packet the decoder accepts.
+// The size is read from the stream before the packet, so it is checked
+// before memory is allocated for the packet.
+// A size of 0 or less means no limit, which lets the peer make the decoder
+// allocate up to 2 GiB.
+func (d *Decoder) SetMaxPacketSize(n int) {
+	d.maxSize = n
+}
+
+// SetSender sets the sender of the packets that are decoded,
+// e.g. the remote address of a connection.
+func (d *Decoder) SetSender(addr net.Addr) {
+	d.sender = addr
+}
+
+// Decode reads the next packet from the stream.
+// It returns io.EOF if the stream ended before the packet.
+func (d *Decoder) Decode() (Packet, error) {
+	data, err := readFrame(d.r, d.maxSize)
+	if err != nil {
+		return nil, err
+	}
+	return parsePacket(data, d.sender)
+}
+
+// readFrame reads a packet prefixed with its int32 size from r without reading past it.
+// Packets larger than maxSize return an error wrapping ErrPacketTooLarge,
+// unless maxSize is 0 or less.
+// It returns io.EOF if r ended before the packet.
+func readFrame(r io.Reader, maxSize int) ([]byte, error) {
+	var size int32
+	if err := binary.Read(r, byteOrder, &size); err != nil {
+		if err == io.EOF {
+			return nil, err
+		}
+		return nil, errors.Wrap(err, "read packet size")
+	}
+	if size <= 0 {
+		return nil, errors.Wrapf(ErrParse, "invalid packet size %d", size)
+	}
+	if maxSize > 0 && int(size) > maxSize {
+		return nil, errors.Wrapf(ErrPacketTooLarge, "%d bytes, max %d", size, maxSize)
+	}
+	data := make([]byte, size)
+	if _, err := io.ReadFull(r, data); err != nil {
+		return nil, errors.Wrap(err, "read packet")
+	}
+	return data, nil
+}
+
+// ServeReader reads packets that were written by an Encoder from r
+// and dispatches them until the end of the stream, see ServeTransport.
+// This allows serving OSC over transports that are not sockets, e.g. named pipes.
+// Any error reading or dispatching a packet is returned.
+func (d Dispatcher) ServeReader(r io.Reader, opts MatchOptions) error {
+	return d.ServeTransport(decoderTransport{dec: NewDecoder(r)}, opts)
+}
+
+// decoderTransport is a transport that only reads packets from a Decoder, see ServeReader.
+type decoderTransport struct {
+	dec *Decoder
+}
+
+// ReadPacket reads the next packet from the decoder.
+func (dt decoderTransport) ReadPacket() (Packet, net.Addr, error) {
+	p, err := dt.dec.Decode()
+	return p, dt.dec.sender, err
+}
+
+// WritePacket returns an error since packets can not be written to a reader.
+func (dt decoderTransport) WritePacket(p Packet, addr net.Addr) error {
+	return errors.New("can not write packets to a reader")
+}
+
+// Close does nothing.
+func (dt decoderTransport) Close() error {
+	return nil
+}
+
+// ReadMessageFromPipe reads a message that was written by WriteMessageToPipe from r,
+// e.g. os.Stdin of a subprocess. Unlike a Decoder it does not buffer,
+// so it never reads past the message.
+// Messages larger than DefaultMaxPacketSize are rejected like a Decoder does.
+// It returns io.EOF if r ended before the message.
+func ReadMessageFromPipe(r io.Reader) (Message, error) {
+	data, err := readFrame(r, DefaultMaxPacketSize)
+	if err != nil {
+		return Message{}, err
+	}
+	if data[0] != MessageChar {
+		return Message{}, errors.Wrap(ErrParse, "not a message")
+	}
+	return ParseMessage(data, nil)
+}
+
+// WriteMessageToPipe writes a message prefixed with its int32 size to w, e.g. os.Stdout,
+// using the same framing as an Encoder.
+// The message is written with a single call to w.Write.
+func WriteMessageToPipe(msg Message, w io.Writer) error {
+	data := msg.Bytes()
+	frame := make([]byte, 4+len(data))
+	byteOrder.PutUint32(frame, uint32(len(data)))
+	copy(frame[4:], data)
+
+	_, err := w.Write(frame)
+	return errors.Wrap(err, "write message")
+}
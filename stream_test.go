@@ -0,0 +1,165 @@
+package osc
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/pkg/errors"
+)
+
+func TestEncoderDecoder(t *testing.T) {
+	var (
+		buf     = &bytes.Buffer{}
+		enc     = NewEncoder(buf)
+		packets = []Packet{
+			Message{Address: "/foo", Arguments: []Argument{Int(1)}},
+			Bundle{Timetag: Immediately, Packets: []Packet{Message{Address: "/bar"}}},
+		}
+	)
+	for _, p := range packets {
+		if err := enc.Encode(p); err != nil {
+			t.Fatal(err)
+		}
+	}
+	dec := NewDecoder(buf)
+	for _, expected := range packets {
+		got, err := dec.Decode()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !expected.Equal(got) {
+			t.Fatalf("expected %v, got %v", expected, got)
+		}
+	}
+	if _, err := dec.Decode(); err != io.EOF {
+		t.Fatalf("expected io.EOF, got %v", err)
+	}
+}
+
+func TestDecoderErrors(t *testing.T) {
+	for _, data := range [][]byte{
+		{0, 0},
+		{0xff, 0xff, 0xff, 0xff},
+		{0, 0, 0, 8, '/', 'f'},
+		{0, 0, 0, 4, 'f', 'o', 'o', 0},
+	} {
+		if _, err := NewDecoder(bytes.NewReader(data)).Decode(); err == nil || err == io.EOF {
+			t.Fatalf("(%v) expected error, got %v", data, err)
+		}
+	}
+}
+
+func TestDecoderMaxPacketSize(t *testing.T) {
+	// The size is checked before the packet is read.
+	huge := []byte{0x7f, 0xff, 0xff, 0xff}
+	if _, err := NewDecoder(bytes.NewReader(huge)).Decode(); errors.Cause(err) != ErrPacketTooLarge {
+		t.Fatalf("expected ErrPacketTooLarge, got %v", err)
+	}
+	if _, err := ReadMessageFromPipe(bytes.NewReader(huge)); errors.Cause(err) != ErrPacketTooLarge {
+		t.Fatalf("expected ErrPacketTooLarge, got %v", err)
+	}
+
+	buf := &bytes.Buffer{}
+	msg := Message{Address: "/foo", Arguments: []Argument{Int(1)}}
+	if err := NewEncoder(buf).Encode(msg); err != nil {
+		t.Fatal(err)
+	}
+	data := buf.Bytes()
+
+	dec := NewDecoder(bytes.NewReader(data))
+	dec.SetMaxPacketSize(len(msg.Bytes()) - 1)
+	if _, err := dec.Decode(); errors.Cause(err) != ErrPacketTooLarge {
+		t.Fatalf("expected ErrPacketTooLarge, got %v", err)
+	}
+	dec = NewDecoder(bytes.NewReader(data))
+	dec.SetMaxPacketSize(len(msg.Bytes()))
+	got, err := dec.Decode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !msg.Equal(got) {
+		t.Fatalf("expected %v, got %v", msg, got)
+	}
+}
+
+func TestDispatcherServeReader(t *testing.T) {
+	var (
+		buf      = &bytes.Buffer{}
+		enc      = NewEncoder(buf)
+		received = []string{}
+		handler  = Method(func(msg Message) error {
+			received = append(received, msg.Address)
+			return nil
+		})
+		d = Dispatcher{"/foo": handler, "/bar": handler}
+	)
+	for _, addr := range []string{"/foo", "/bar"} {
+		if err := enc.Encode(Message{Address: addr}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := d.ServeReader(bytes.NewReader(buf.Bytes()), MatchOptions{Exact: true}); err != nil {
+		t.Fatal(err)
+	}
+	if expected, got := 2, len(received); expected != got {
+		t.Fatalf("expected %d messages, got %d", expected, got)
+	}
+	if received[0] != "/foo" || received[1] != "/bar" {
+		t.Fatalf("expected [/foo /bar], got %v", received)
+	}
+	if err := d.ServeReader(bytes.NewReader([]byte{0, 0, 0, 4, 'f', 'o', 'o', 0}), MatchOptions{}); errors.Cause(err) != ErrParse {
+		t.Fatalf("expected ErrParse, got %v", err)
+	}
+}
+
+func TestPipe(t *testing.T) {
+	var (
+		buf      = &bytes.Buffer{}
+		messages = []Message{
+			{Address: "/foo", Arguments: []Argument{Int(1), String("bar")}},
+			{Address: "/baz"},
+		}
+	)
+	for _, msg := range messages {
+		if err := WriteMessageToPipe(msg, buf); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Pipe framing is compatible with Encoder.
+	enc := NewEncoder(buf)
+	if err := enc.Encode(Message{Address: "/qux"}); err != nil {
+		t.Fatal(err)
+	}
+	messages = append(messages, Message{Address: "/qux"})
+
+	for _, expected := range messages {
+		got, err := ReadMessageFromPipe(buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !expected.Equal(got) {
+			t.Fatalf("expected %v, got %v", expected, got)
+		}
+	}
+	if _, err := ReadMessageFromPipe(buf); err != io.EOF {
+		t.Fatalf("expected io.EOF, got %v", err)
+	}
+	if err := WriteMessageToPipe(Message{Address: "/foo"}, &errWriter{erridx: 1}); err == nil {
+		t.Fatal("expected error, got nil")
+	}
+}
+
+func TestReadMessageFromPipeErrors(t *testing.T) {
+	bundle := Bundle{Timetag: Immediately}.Bytes()
+	for _, data := range [][]byte{
+		{0, 0},
+		{0xff, 0xff, 0xff, 0xff},
+		{0, 0, 0, 8, '/', 'f'},
+		append([]byte{0, 0, 0, byte(len(bundle))}, bundle...),
+	} {
+		if _, err := ReadMessageFromPipe(bytes.NewReader(data)); err == nil || err == io.EOF {
+			t.Fatalf("(%v) expected error, got %v", data, err)
+		}
+	}
+}
@@ -0,0 +1,188 @@
+package osc
+
+import (
+	"bufio"
+	"encoding/base64"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// ParseText parses a message from its text form, e.g.
+//
+//	/synth/freq ,fs 440.0 "sine wave"
+//
+// The text form is the address, followed by the type tag string and the arguments,
+// separated by whitespace. The type tag string may be omitted if there are no arguments.
+// Ints and floats are written in decimal, strings may be quoted like Go strings,
+// and blobs are written in standard base64. True and false arguments have no value.
+// Arguments of custom types are written as their Bytes in standard base64.
+func ParseText(line string) (Message, error) {
+	tokens, err := splitText(line)
+	if err != nil {
+		return Message{}, err
+	}
+	if len(tokens) == 0 {
+		return Message{}, errors.Wrap(ErrParse, "empty line")
+	}
+	msg := Message{Address: tokens[0]}
+	if len(msg.Address) == 0 || msg.Address[0] != MessageChar {
+		return Message{}, errors.Wrapf(ErrInvalidAddress, "address %s", msg.Address)
+	}
+	if len(tokens) == 1 {
+		return msg, nil
+	}
+	typetags, err := ParseTypetags(tokens[1])
+	if err != nil {
+		return Message{}, err
+	}
+	values := tokens[2:]
+	for _, tt := range typetags {
+		arg, err := parseTextArgument(tt, values)
+		if err != nil {
+			return Message{}, errors.Wrapf(err, "argument %d", len(msg.Arguments))
+		}
+		if tt != TypetagTrue && tt != TypetagFalse {
+			values = values[1:]
+		}
+		msg.Arguments = append(msg.Arguments, arg)
+	}
+	if len(values) > 0 {
+		return Message{}, errors.Wrapf(ErrParse, "%d values left over", len(values))
+	}
+	return msg, nil
+}
+
+// ReadText reads messages in text form from r, one per line.
+// Blank lines and lines starting with '#' are skipped.
+func ReadText(r io.Reader) ([]Message, error) {
+	var (
+		msgs    = []Message{}
+		scanner = bufio.NewScanner(r)
+	)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || line[0] == '#' {
+			continue
+		}
+		msg, err := ParseText(line)
+		if err != nil {
+			return nil, errors.Wrapf(err, "line %d", n)
+		}
+		msgs = append(msgs, msg)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, errors.Wrap(err, "read text")
+	}
+	return msgs, nil
+}
+
+// Text returns the text form of the message, see ParseText.
+func (msg Message) Text() string {
+	var b strings.Builder
+	b.WriteString(msg.Address)
+	if len(msg.Arguments) == 0 {
+		return b.String()
+	}
+	b.WriteByte(' ')
+	b.WriteString(msg.TypetagString())
+
+	for _, a := range msg.Arguments {
+		switch x := a.(type) {
+		case Int:
+			b.WriteByte(' ')
+			b.WriteString(strconv.FormatInt(int64(x), 10))
+		case Float:
+			b.WriteByte(' ')
+			b.WriteString(strconv.FormatFloat(float64(x), 'g', -1, 32))
+		case Bool:
+		case String:
+			b.WriteByte(' ')
+			b.WriteString(strconv.Quote(string(x)))
+		case Blob:
+			b.WriteByte(' ')
+			b.WriteString(x.Base64())
+		default:
+			b.WriteByte(' ')
+			b.WriteString(base64.StdEncoding.EncodeToString(EncodeArgument(a)))
+		}
+	}
+	return b.String()
+}
+
+// parseTextArgument parses an argument from the first of values.
+func parseTextArgument(tt byte, values []string) (Argument, error) {
+	switch tt {
+	case TypetagTrue:
+		return Bool(true), nil
+	case TypetagFalse:
+		return Bool(false), nil
+	}
+	if len(values) == 0 {
+		return nil, errors.Wrap(ErrParse, "missing value")
+	}
+	v := values[0]
+
+	switch tt {
+	case TypetagInt:
+		i, err := strconv.ParseInt(v, 10, 32)
+		if err != nil {
+			return nil, errors.Wrap(err, "parse int")
+		}
+		return Int(i), nil
+	case TypetagFloat:
+		f, err := strconv.ParseFloat(v, 32)
+		if err != nil {
+			return nil, errors.Wrap(err, "parse float")
+		}
+		return Float(f), nil
+	case TypetagString:
+		return String(v), nil
+	case TypetagBlob:
+		b, err := BlobFromBase64(v)
+		if err != nil {
+			return nil, errors.Wrap(err, "parse blob")
+		}
+		return b, nil
+	default:
+		data, err := base64.StdEncoding.DecodeString(v)
+		if err != nil {
+			return nil, errors.Wrapf(err, "parse %q argument", string(tt))
+		}
+		arg, _, err := ReadArgument(tt, data)
+		return arg, err
+	}
+}
+
+// splitText splits a line into whitespace separated tokens.
+// Tokens that start with a double quote are unquoted like Go strings.
+func splitText(line string) ([]string, error) {
+	tokens := []string{}
+	for {
+		line = strings.TrimLeft(line, " \t")
+		if line == "" {
+			return tokens, nil
+		}
+		if line[0] == '"' {
+			quoted, err := strconv.QuotedPrefix(line)
+			if err != nil {
+				return nil, errors.Wrapf(ErrParse, "unterminated string %s", line)
+			}
+			s, err := strconv.Unquote(quoted)
+			if err != nil {
+				return nil, errors.Wrapf(ErrParse, "invalid string %s", quoted)
+			}
+			tokens = append(tokens, s)
+			line = line[len(quoted):]
+			continue
+		}
+		end := strings.IndexAny(line, " \t")
+		if end < 0 {
+			end = len(line)
+		}
+		tokens = append(tokens, line[:end])
+		line = line[end:]
+	}
+}
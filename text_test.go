@@ -0,0 +1,96 @@
+package osc
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/pkg/errors"
+)
+
+func TestParseText(t *testing.T) {
+	for _, testcase := range []struct {
+		Line     string
+		Expected Message
+	}{
+		{
+			Line:     "/synth/freq ,f 440",
+			Expected: Message{Address: "/synth/freq", Arguments: []Argument{Float(440)}},
+		},
+		{
+			Line:     "/foo",
+			Expected: Message{Address: "/foo"},
+		},
+		{
+			Line:     `/label ,is -7 "hello world"`,
+			Expected: Message{Address: "/label", Arguments: []Argument{Int(-7), String("hello world")}},
+		},
+		{
+			Line:     `/mixed ,TsFb "say \"hi\"" AQID`,
+			Expected: Message{Address: "/mixed", Arguments: []Argument{Bool(true), String(`say "hi"`), Bool(false), Blob{1, 2, 3}}},
+		},
+	} {
+		msg, err := ParseText(testcase.Line)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !testcase.Expected.Equal(msg) {
+			t.Fatalf("(%s) expected %v, got %v", testcase.Line, testcase.Expected, msg)
+		}
+		roundtrip, err := ParseText(msg.Text())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !msg.Equal(roundtrip) {
+			t.Fatalf("(%s) expected %v, got %v", msg.Text(), msg, roundtrip)
+		}
+	}
+}
+
+func TestParseTextErrors(t *testing.T) {
+	for _, line := range []string{
+		"",
+		"foo ,i 1",
+		"/foo i 1",
+		"/foo ,i",
+		"/foo ,i 1 2",
+		"/foo ,i x",
+		"/foo ,f x",
+		"/foo ,b !",
+		`/foo ,s "bar`,
+	} {
+		if _, err := ParseText(line); err == nil {
+			t.Fatalf("(%s) expected error, got nil", line)
+		}
+	}
+	if _, err := ParseText("/foo ,i 1 2"); errors.Cause(err) != ErrParse {
+		t.Fatalf("expected ErrParse, got %v", err)
+	}
+}
+
+func TestMessageText(t *testing.T) {
+	msg := Message{Address: "/synth/freq", Arguments: []Argument{Float(440.5), String("a b"), Bool(true)}}
+	if expected, got := `/synth/freq ,fsT 440.5 "a b"`, msg.Text(); expected != got {
+		t.Fatalf("expected %s, got %s", expected, got)
+	}
+}
+
+func TestReadText(t *testing.T) {
+	msgs, err := ReadText(strings.NewReader(`
+# A fixture.
+/synth/freq ,f 440
+
+/synth/gate ,i 1
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected, got := 2, len(msgs); expected != got {
+		t.Fatalf("expected %d messages, got %d", expected, got)
+	}
+	if expected, got := "/synth/gate", msgs[1].Address; expected != got {
+		t.Fatalf("expected %s, got %s", expected, got)
+	}
+	if _, err := ReadText(strings.NewReader("/foo ,i 1\n/bar ,i x\n")); err == nil || !strings.Contains(err.Error(), "line 2") {
+		t.Fatalf("expected error on line 2, got %v", err)
+	}
+}
@@ -3,6 +3,8 @@ package osc
 import (
 	"bytes"
 	"encoding/binary"
+	"fmt"
+	"io"
 	"time"
 
 	"github.com/pkg/errors"
@@ -20,6 +22,9 @@ const (
 	Immediately = Timetag(1)
 )
 
+// TypetagTimetag is the type tag of Timetag arguments.
+const TypetagTimetag byte = 't'
+
 // Timetag represents an OSC Time Tag.
 // An OSC Time Tag is defined as follows:
 // Time tags are represented by a 64 bit fixed point number. The first 32 bits
@@ -28,6 +33,7 @@ const (
 // 200 picoseconds. This is the representation used by Internet NTP timestamps.
 // The time tag value consisting of 63 zero bits followed by a one in the least
 // significant bit is a special case meaning "immediately."
+// Timetags are also OSC message arguments, see TypetagTimetag.
 type Timetag uint64
 
 // Bytes converts the timetag to a slice of bytes.
@@ -70,3 +76,50 @@ func ReadTimetag(data []byte) (Timetag, error) {
 	_ = binary.Read(bytes.NewReader(R), byteOrder, &nsecs) // Never fails
 	return Timetag((secs << 32) + nsecs), nil
 }
+
+// ReadTimetagFrom reads a timetag argument from a byte slice.
+func ReadTimetagFrom(data []byte) (Argument, int64, error) {
+	if len(data) < TimetagSize {
+		return nil, 0, errors.Wrap(io.ErrUnexpectedEOF, "read timetag argument")
+	}
+	return Timetag(byteOrder.Uint64(data)), TimetagSize, nil
+}
+
+// Equal returns true if the argument equals the other one, false otherwise.
+func (tt Timetag) Equal(other Argument) bool {
+	if other.Typetag() != TypetagTimetag {
+		return false
+	}
+	tt2 := other.(Timetag)
+	return tt == tt2
+}
+
+// ReadInt32 reads a 32-bit integer from the arg.
+func (tt Timetag) ReadInt32() (int32, error) { return 0, ErrInvalidTypeTag }
+
+// ReadInt64 reads a 64-bit integer from the arg.
+func (tt Timetag) ReadInt64() (int64, error) { return 0, ErrInvalidTypeTag }
+
+// ReadFloat32 reads a 32-bit float from the arg.
+func (tt Timetag) ReadFloat32() (float32, error) { return 0, ErrInvalidTypeTag }
+
+// ReadBool bool reads a boolean from the arg.
+func (tt Timetag) ReadBool() (bool, error) { return false, ErrInvalidTypeTag }
+
+// ReadString string reads a string from the arg.
+func (tt Timetag) ReadString() (string, error) { return "", ErrInvalidTypeTag }
+
+// ReadBlob reads a slice of bytes from the arg.
+func (tt Timetag) ReadBlob() ([]byte, error) { return nil, ErrInvalidTypeTag }
+
+// Typetag returns the argument's type tag.
+func (tt Timetag) Typetag() byte { return TypetagTimetag }
+
+// Size returns the number of bytes of the encoded arg.
+func (tt Timetag) Size() int { return TimetagSize }
+
+// WriteTo writes the arg to an io.Writer.
+func (tt Timetag) WriteTo(w io.Writer) (int64, error) {
+	written, err := fmt.Fprint(w, tt.String())
+	return int64(written), err
+}
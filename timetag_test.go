@@ -2,6 +2,7 @@ package osc
 
 import (
 	"bytes"
+	"io"
 	"testing"
 	"time"
 
@@ -92,3 +93,23 @@ func TestReadTimetag(t *testing.T) {
 		}
 	}
 }
+
+func TestTimetagArgument(t *testing.T) {
+	tt := FromTime(time.Unix(1, 0))
+	args, err := ReadArguments([]byte(",ti"), append(tt.Bytes(), Int(1).Bytes()...))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !tt.Equal(args[0]) || !Int(1).Equal(args[1]) {
+		t.Fatalf("expected [%s Int(1)], got %v", tt, args)
+	}
+	if expected, got := len(tt.Bytes()), tt.Size(); expected != got {
+		t.Fatalf("expected %d, got %d", expected, got)
+	}
+	if tt.Equal(Int(1)) {
+		t.Fatal("expected timetag to not equal int")
+	}
+	if _, err := ReadArguments([]byte(",t"), Int(1).Bytes()); errors.Cause(err) != io.ErrShortBuffer {
+		t.Fatalf("expected io.ErrShortBuffer, got %v", err)
+	}
+}
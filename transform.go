@@ -0,0 +1,55 @@
+package osc
+
+import (
+	"github.com/pkg/errors"
+)
+
+// Transformer transforms a message before it is dispatched.
+// It returns false to drop the message, in which case it is not dispatched.
+type Transformer func(msg Message) (Message, bool, error)
+
+// Chain returns a transformer that applies the transformers in order.
+// It stops at the first transformer that drops the message or returns an error.
+func Chain(transforms ...Transformer) Transformer {
+	return func(msg Message) (Message, bool, error) {
+		for _, t := range transforms {
+			var (
+				keep bool
+				err  error
+			)
+			if msg, keep, err = t(msg); err != nil || !keep {
+				return msg, keep, err
+			}
+		}
+		return msg, true, nil
+	}
+}
+
+// transformBundle applies the transformer to every message in the bundle,
+// including the messages in nested bundles.
+// Dropped messages are removed from the bundle.
+func transformBundle(b Bundle, t Transformer) (Bundle, error) {
+	packets := make([]Packet, 0, len(b.Packets))
+	for i, p := range b.Packets {
+		switch x := p.(type) {
+		case Message:
+			msg, keep, err := t(x)
+			if err != nil {
+				return Bundle{}, errors.Wrapf(err, "transform packet %d", i)
+			}
+			if keep {
+				packets = append(packets, msg)
+			}
+		case Bundle:
+			nested, err := transformBundle(x, t)
+			if err != nil {
+				return Bundle{}, errors.Wrapf(err, "transform packet %d", i)
+			}
+			packets = append(packets, nested)
+		default:
+			packets = append(packets, p)
+		}
+	}
+	b.Packets = packets
+	return b, nil
+}
@@ -0,0 +1,73 @@
+package osc
+
+import (
+	"testing"
+
+	"github.com/pkg/errors"
+)
+
+func TestChain(t *testing.T) {
+	var (
+		lower = Transformer(func(msg Message) (Message, bool, error) {
+			msg.Address = "/lower" + msg.Address
+			return msg, true, nil
+		})
+		drop = Transformer(func(msg Message) (Message, bool, error) {
+			return msg, len(msg.Arguments) > 0, nil
+		})
+		fail = Transformer(func(msg Message) (Message, bool, error) {
+			return msg, false, errors.New("oops")
+		})
+	)
+	msg, keep, err := Chain(lower, drop)(Message{Address: "/foo", Arguments: []Argument{Int(1)}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !keep {
+		t.Fatal("expected message to be kept")
+	}
+	if expected, got := "/lower/foo", msg.Address; expected != got {
+		t.Fatalf("expected %s, got %s", expected, got)
+	}
+	if _, keep, _ := Chain(drop, fail)(Message{Address: "/foo"}); keep {
+		t.Fatal("expected message to be dropped")
+	}
+	if _, _, err := Chain(lower, fail, drop)(Message{Address: "/foo"}); err == nil {
+		t.Fatal("expected error, got nil")
+	}
+	if _, keep, err := Chain()(Message{Address: "/foo"}); err != nil || !keep {
+		t.Fatalf("expected message to be kept, got %t %v", keep, err)
+	}
+}
+
+func TestTransformBundle(t *testing.T) {
+	drop := Transformer(func(msg Message) (Message, bool, error) {
+		return msg, msg.Address != "/drop", nil
+	})
+	b := Bundle{
+		Packets: []Packet{
+			Message{Address: "/keep"},
+			Message{Address: "/drop"},
+			Bundle{Packets: []Packet{Message{Address: "/drop"}, Message{Address: "/nested"}}},
+		},
+	}
+	got, err := transformBundle(b, drop)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := Bundle{
+		Packets: []Packet{
+			Message{Address: "/keep"},
+			Bundle{Packets: []Packet{Message{Address: "/nested"}}},
+		},
+	}
+	if !expected.Equal(got) {
+		t.Fatalf("expected %v, got %v", expected, got)
+	}
+	fail := Transformer(func(msg Message) (Message, bool, error) {
+		return msg, false, errors.New("oops")
+	})
+	if _, err := transformBundle(b, fail); err == nil {
+		t.Fatal("expected error, got nil")
+	}
+}
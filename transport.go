@@ -0,0 +1,199 @@
+package osc
+
+import (
+	"context"
+	"io"
+	"net"
+	"sync"
+)
+
+// Transport sends and receives packets, e.g. over UDP, TCP or in memory,
+// so the same dispatcher can be served over any of them, see ServeTransport.
+// UDPConn, UnixConn and StreamTransport are transports,
+// NewMemoryTransports returns a pair of transports for tests.
+type Transport interface {
+	// ReadPacket reads the next packet and returns the address of its sender.
+	// It returns io.EOF when there are no more packets, e.g. because the transport was closed.
+	ReadPacket() (Packet, net.Addr, error)
+
+	// WritePacket sends a packet to addr.
+	// Connected transports send to their peer if addr is nil.
+	WritePacket(Packet, net.Addr) error
+
+	Close() error
+}
+
+// ServeTransport reads packets from t and dispatches them until ReadPacket returns io.EOF,
+// then waits for the handlers of the packets it read.
+// Packets are dispatched one at a time, in the order they were read.
+// Any error reading or dispatching a packet is returned.
+func (d Dispatcher) ServeTransport(t Transport, opts MatchOptions) error {
+	return serve(context.Background(), t, &inflight{}, 1, serveOptions{match: opts}, d)
+}
+
+// packetReader reads datagrams, see readTransportPacket.
+type packetReader interface {
+	CloseChan() <-chan struct{}
+	read([]byte) (int, net.Addr, error)
+}
+
+// packetError is returned by ReadPacket for data that could not be parsed,
+// so serving can pass the data to a ParseErrorHandler.
+type packetError struct {
+	data   []byte
+	sender net.Addr
+	err    error
+}
+
+// Error returns the error message.
+func (pe *packetError) Error() string {
+	return "parse packet: " + pe.err.Error()
+}
+
+// Cause returns the parse error, see errors.Cause.
+func (pe *packetError) Cause() error {
+	return pe.err
+}
+
+// readTransportPacket reads a packet from a connection and parses it using opts.
+// It returns io.EOF if the connection was closed.
+func readTransportPacket(pr packetReader, opts ParseOptions) (Packet, net.Addr, error) {
+	data := make([]byte, bufSize)
+	n, sender, err := pr.read(data)
+	if err != nil {
+		select {
+		case <-pr.CloseChan():
+			return nil, nil, io.EOF
+		default:
+			return nil, nil, err
+		}
+	}
+	p, err := parsePacketWith(data[:n], sender, opts)
+	if err != nil {
+		return nil, nil, &packetError{data: data[:n], sender: sender, err: err}
+	}
+	return p, sender, nil
+}
+
+// StreamTransport is a transport over a stream-oriented connection, e.g. a *net.TCPConn.
+// Packets are framed like an Encoder does.
+type StreamTransport struct {
+	conn net.Conn
+	dec  *Decoder
+
+	mu  sync.Mutex
+	enc *Encoder
+}
+
+// NewStreamTransport returns a transport that sends and receives packets over conn.
+func NewStreamTransport(conn net.Conn) *StreamTransport {
+	dec := NewDecoder(conn)
+	dec.SetSender(conn.RemoteAddr())
+	return &StreamTransport{
+		conn: conn,
+		dec:  dec,
+		enc:  NewEncoder(conn),
+	}
+}
+
+// ReadPacket reads the next packet from the stream.
+// The sender is the remote address of the connection.
+// It returns io.EOF once the peer closed the connection.
+func (st *StreamTransport) ReadPacket() (Packet, net.Addr, error) {
+	p, err := st.dec.Decode()
+	if err != nil {
+		return nil, nil, err
+	}
+	return p, st.conn.RemoteAddr(), nil
+}
+
+// WritePacket writes a packet to the stream.
+// addr is ignored since a stream is always connected to its peer.
+func (st *StreamTransport) WritePacket(p Packet, addr net.Addr) error {
+	if p == nil {
+		return ErrNilPacket
+	}
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	return st.enc.Encode(p)
+}
+
+// Close closes the connection.
+func (st *StreamTransport) Close() error {
+	return st.conn.Close()
+}
+
+// MemoryTransport is an in-memory transport, see NewMemoryTransports.
+type MemoryTransport struct {
+	addr StringAddr
+	in   <-chan memoryPacket
+	out  chan<- memoryPacket
+	pipe *memoryPipe
+}
+
+// memoryPacket is a packet in flight between memory transports.
+type memoryPacket struct {
+	data   []byte
+	sender StringAddr
+}
+
+// memoryPipe is shared by a pair of memory transports,
+// closing either of them closes both.
+type memoryPipe struct {
+	done chan struct{}
+	once sync.Once
+}
+
+// NewMemoryTransports returns a pair of connected transports that do not use the network,
+// e.g. to test a dispatcher without opening sockets.
+// Packets are encoded by WritePacket and parsed by ReadPacket like they would be on the wire.
+// The sender of packets read from a is "b" and vice versa.
+func NewMemoryTransports() (a, b *MemoryTransport) {
+	var (
+		ab   = make(chan memoryPacket)
+		ba   = make(chan memoryPacket)
+		pipe = &memoryPipe{done: make(chan struct{})}
+	)
+	a = &MemoryTransport{addr: "a", in: ba, out: ab, pipe: pipe}
+	b = &MemoryTransport{addr: "b", in: ab, out: ba, pipe: pipe}
+	return a, b
+}
+
+// LocalAddr returns the address of the transport.
+func (mt *MemoryTransport) LocalAddr() net.Addr {
+	return mt.addr
+}
+
+// ReadPacket waits for the peer to write a packet.
+func (mt *MemoryTransport) ReadPacket() (Packet, net.Addr, error) {
+	select {
+	case <-mt.pipe.done:
+		return nil, nil, io.EOF
+	case mp := <-mt.in:
+		p, err := parsePacket(mp.data, mp.sender)
+		if err != nil {
+			return nil, nil, &packetError{data: mp.data, sender: mp.sender, err: err}
+		}
+		return p, mp.sender, nil
+	}
+}
+
+// WritePacket waits for the peer to read the packet.
+// addr is ignored since a memory transport is always connected to its peer.
+func (mt *MemoryTransport) WritePacket(p Packet, addr net.Addr) error {
+	if p == nil {
+		return ErrNilPacket
+	}
+	select {
+	case <-mt.pipe.done:
+		return io.ErrClosedPipe
+	case mt.out <- memoryPacket{data: p.Bytes(), sender: mt.addr}:
+		return nil
+	}
+}
+
+// Close closes the transport and its peer.
+func (mt *MemoryTransport) Close() error {
+	mt.pipe.once.Do(func() { close(mt.pipe.done) })
+	return nil
+}
@@ -0,0 +1,158 @@
+package osc
+
+import (
+	"io"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+func TestDispatcherServeTransport(t *testing.T) {
+	server, client := NewMemoryTransports()
+
+	received := make(chan Message, 2)
+	d := Dispatcher{
+		"/synth/1/freq": Method(func(msg Message) error {
+			received <- msg
+			return nil
+		}),
+	}
+	errChan := make(chan error)
+	go func() {
+		errChan <- d.ServeTransport(server, MatchOptions{})
+	}()
+
+	for _, p := range []Packet{
+		Message{Address: "/synth/*/freq", Arguments: []Argument{Float(440)}},
+		Bundle{
+			Timetag: Immediately,
+			Packets: []Packet{Message{Address: "/synth/1/freq", Arguments: []Argument{Float(880)}}},
+		},
+	} {
+		if err := client.WritePacket(p, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, expected := range []Float{440, 880} {
+		select {
+		case msg := <-received:
+			if !expected.Equal(msg.Arguments[0]) {
+				t.Fatalf("expected %s, got %s", expected, msg.Arguments[0])
+			}
+			if expected, got := "b", msg.Sender.String(); expected != got {
+				t.Fatalf("expected sender %s, got %s", expected, got)
+			}
+		case <-time.After(time.Second):
+			t.Fatal("timeout waiting for message")
+		}
+	}
+	if err := client.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := <-errChan; err != nil {
+		t.Fatal(err)
+	}
+	if err := client.WritePacket(Message{Address: "/foo"}, nil); err != io.ErrClosedPipe {
+		t.Fatalf("expected io.ErrClosedPipe, got %v", err)
+	}
+}
+
+func TestDispatcherServeTransportError(t *testing.T) {
+	server, client := NewMemoryTransports()
+
+	oops := errors.New("oops")
+	d := Dispatcher{
+		"/foo": Method(func(msg Message) error { return oops }),
+	}
+	errChan := make(chan error)
+	go func() {
+		errChan <- d.ServeTransport(server, MatchOptions{})
+	}()
+	if err := client.WritePacket(Message{Address: "/foo"}, nil); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case err := <-errChan:
+		if !strings.Contains(err.Error(), "oops") {
+			t.Fatalf("expected the handler's error, got %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("timeout waiting for error")
+	}
+	_ = client.Close() // Best effort.
+}
+
+func TestUDPConnTransport(t *testing.T) {
+	laddr, err := net.ResolveUDPAddr("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	server, err := ListenUDP("udp", laddr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	raddr, err := net.ResolveUDPAddr("udp", server.LocalAddr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	client, err := DialUDP("udp", nil, raddr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = client.Close() }() // Best effort.
+
+	var tr Transport = client
+	msg := Message{Address: "/foo", Arguments: []Argument{Int(1)}}
+	if err := tr.WritePacket(msg, nil); err != nil {
+		t.Fatal(err)
+	}
+	p, sender, err := server.ReadPacket()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !msg.Equal(p) {
+		t.Fatalf("expected %v, got %v", msg, p)
+	}
+	if expected, got := client.LocalAddr().String(), sender.String(); expected != got {
+		t.Fatalf("expected sender %s, got %s", expected, got)
+	}
+	if err := server.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := server.ReadPacket(); err != io.EOF {
+		t.Fatalf("expected io.EOF, got %v", err)
+	}
+}
+
+func TestStreamTransport(t *testing.T) {
+	c1, c2 := net.Pipe()
+	var (
+		a = NewStreamTransport(c1)
+		b = NewStreamTransport(c2)
+	)
+	defer func() { _ = a.Close() }() // Best effort.
+
+	msg := Message{Address: "/foo", Arguments: []Argument{String("bar")}}
+	go func() {
+		_ = a.WritePacket(msg, nil)
+	}()
+	p, _, err := b.ReadPacket()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !msg.Equal(p) {
+		t.Fatalf("expected %v, got %v", msg, p)
+	}
+	if err := a.WritePacket(nil, nil); err != ErrNilPacket {
+		t.Fatalf("expected ErrNilPacket, got %v", err)
+	}
+	if err := b.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := b.ReadPacket(); err == nil {
+		t.Fatal("expected error, got nil")
+	}
+}
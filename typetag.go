@@ -0,0 +1,36 @@
+package osc
+
+import (
+	"github.com/pkg/errors"
+)
+
+// ParseTypetags parses a type tag string like ",isf" into its type tags.
+// The string must start with a comma, and each type tag must be
+// either built in or registered with RegisterType.
+func ParseTypetags(s string) ([]byte, error) {
+	if len(s) == 0 || s[0] != TypetagPrefix {
+		return nil, errors.Wrapf(ErrInvalidTypeTag, "type tag string %q does not start with %q", s, string(TypetagPrefix))
+	}
+	tags := []byte(s[1:])
+	for i, tag := range tags {
+		if !isKnownTypeTag(tag) {
+			return nil, errors.Wrapf(ErrInvalidTypeTag, "typetag %q at index %d", string(tag), i)
+		}
+	}
+	return tags, nil
+}
+
+// FormatTypetags returns the type tag string for the given type tags.
+// It is the inverse of ParseTypetags.
+func FormatTypetags(tags []byte) string {
+	return string(TypetagPrefix) + string(tags)
+}
+
+// isKnownTypeTag returns true if the type tag is built in or registered.
+func isKnownTypeTag(tag byte) bool {
+	if isBuiltinTypeTag(tag) {
+		return true
+	}
+	_, ok := lookupType(tag)
+	return ok
+}
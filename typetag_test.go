@@ -0,0 +1,60 @@
+package osc
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/pkg/errors"
+)
+
+func TestParseTypetags(t *testing.T) {
+	for _, testcase := range []struct {
+		Input    string
+		Expected []byte
+	}{
+		{Input: ",", Expected: []byte{}},
+		{Input: ",isf", Expected: []byte("isf")},
+		{Input: ",bTF", Expected: []byte("bTF")},
+	} {
+		tags, err := ParseTypetags(testcase.Input)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if expected, got := testcase.Expected, tags; !bytes.Equal(expected, got) {
+			t.Fatalf("(%s) expected %q, got %q", testcase.Input, expected, got)
+		}
+		if expected, got := testcase.Input, FormatTypetags(tags); expected != got {
+			t.Fatalf("expected %s, got %s", expected, got)
+		}
+	}
+	for _, input := range []string{"", "isf", ",ixf"} {
+		if _, err := ParseTypetags(input); errors.Cause(err) != ErrInvalidTypeTag {
+			t.Fatalf("(%s) expected ErrInvalidTypeTag, got %v", input, err)
+		}
+	}
+}
+
+func TestParseTypetagsRegistered(t *testing.T) {
+	if err := RegisterType('x', decodeRGBA, nil); err != nil {
+		t.Fatal(err)
+	}
+	defer UnregisterType('x')
+
+	tags, err := ParseTypetags(",ix")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected, got := []byte("ix"), tags; !bytes.Equal(expected, got) {
+		t.Fatalf("expected %q, got %q", expected, got)
+	}
+}
+
+func TestMessageTypetagString(t *testing.T) {
+	msg := Message{Address: "/foo", Arguments: []Argument{Int(1), Float(2), String("bar")}}
+	if expected, got := ",ifs", msg.TypetagString(); expected != got {
+		t.Fatalf("expected %s, got %s", expected, got)
+	}
+	if expected, got := ",", (Message{Address: "/foo"}).TypetagString(); expected != got {
+		t.Fatalf("expected %s, got %s", expected, got)
+	}
+}
@@ -3,6 +3,7 @@ package osc
 import (
 	"context"
 	"net"
+	"time"
 
 	"github.com/pkg/errors"
 )
@@ -19,10 +20,12 @@ type udpConn interface {
 type UDPConn struct {
 	udpConn
 
-	closeChan  chan struct{}
-	ctx        context.Context
-	errChan    chan error
-	exactMatch bool
+	closeChan chan struct{}
+	ctx       context.Context
+	errChan   chan error
+	handlers  inflight
+
+	serveConfig
 }
 
 // DialUDP creates a new OSC connection over UDP.
@@ -97,33 +100,78 @@ func (conn *UDPConn) read(data []byte) (int, net.Addr, error) {
 
 // Send sends an OSC message over UDP.
 func (conn *UDPConn) Send(p Packet) error {
-	_, err := conn.Write(p.Bytes())
+	data, err := conn.Encode(p)
+	if err != nil {
+		return err
+	}
+	_, err = conn.Write(data)
 	return err
 }
 
+// Encode returns the bytes Send would transmit for the packet without sending them,
+// e.g. to assert on them in tests.
+func (conn *UDPConn) Encode(p Packet) ([]byte, error) {
+	if p == nil {
+		return nil, ErrNilPacket
+	}
+	return p.Bytes(), nil
+}
+
 // SendTo sends a packet to the given address.
 func (conn *UDPConn) SendTo(addr net.Addr, p Packet) error {
 	_, err := conn.WriteTo(p.Bytes(), addr)
 	return err
 }
 
+// ReadPacket reads the next packet and returns the address of its sender,
+// see Transport. It returns io.EOF once the conn is closed.
+// Messages with more arguments than allowed by SetMaxArguments are parse errors.
+func (conn *UDPConn) ReadPacket() (Packet, net.Addr, error) {
+	return readTransportPacket(conn, ParseOptions{MaxArguments: conn.maxArguments})
+}
+
+// WritePacket sends a packet to addr, or to the remote address of the conn if addr is nil,
+// see Transport.
+func (conn *UDPConn) WritePacket(p Packet, addr net.Addr) error {
+	if addr == nil {
+		return conn.Send(p)
+	}
+	if p == nil {
+		return ErrNilPacket
+	}
+	return conn.SendTo(addr, p)
+}
+
 // Serve starts dispatching OSC.
 // Any errors returned from a dispatched method will be returned.
 // Note that this means that errors returned from a dispatcher method will kill your server.
 // If context.Canceled or context.DeadlineExceeded are encountered they will be returned directly.
 func (conn *UDPConn) Serve(numWorkers int, dispatcher Dispatcher) error {
-	return serve(conn, numWorkers, conn.exactMatch, dispatcher)
+	return serve(conn.ctx, conn, &conn.handlers, numWorkers, conn.serveOptions(), dispatcher)
+}
+
+// Shutdown gracefully shuts down the connection.
+// It stops reading new packets, waits for the packets that are being
+// handled to be done, and then closes the connection.
+// If the context expires first the connection is closed anyway and
+// the context's error is returned.
+func (conn *UDPConn) Shutdown(ctx context.Context) error {
+	idle := conn.handlers.drain()
+
+	// Unblock the read loop.
+	if err := conn.SetReadDeadline(time.Now()); err != nil {
+		return errors.Wrap(err, "setting read deadline")
+	}
+	select {
+	case <-idle:
+		return conn.Close()
+	case <-ctx.Done():
+		_ = conn.Close() // Best effort.
+		return ctx.Err()
+	}
 }
 
 // SetContext sets the context associated with the conn.
 func (conn *UDPConn) SetContext(ctx context.Context) {
 	conn.ctx = ctx
 }
-
-// SetExactMatch changes the behavior of the Serve method so that
-// messages will only be dispatched to methods whose addresses
-// match the message's address exactly.
-// This should provide some performance improvement.
-func (conn *UDPConn) SetExactMatch(value bool) {
-	conn.exactMatch = value
-}
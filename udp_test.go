@@ -3,7 +3,10 @@ package osc
 import (
 	"bytes"
 	"context"
+	"log"
 	"net"
+	"os"
+	"strings"
 	"testing"
 	"time"
 
@@ -83,7 +86,10 @@ func TestDialUDPContext(t *testing.T) {
 // For clients that are interested in closing the server with an OSC
 // message, a method is automatically added to the provided dispatcher
 // at the "/server/close" address that closes the server.
-func testUDPServer(t *testing.T, dispatcher Dispatcher) (*UDPConn, *UDPConn, chan error) {
+// testUDPServer starts serving dispatcher on a new UDP conn and returns it
+// along with a conn that sends to it and a channel that receives the error from Serve.
+// configure, if not nil, is called with the server before it starts serving.
+func testUDPServer(t *testing.T, dispatcher Dispatcher, configure func(server *UDPConn)) (*UDPConn, *UDPConn, chan error) {
 	laddr, err := net.ResolveUDPAddr("udp", "127.0.0.1:0")
 	if err != nil {
 		t.Fatal(err)
@@ -98,6 +104,9 @@ func testUDPServer(t *testing.T, dispatcher Dispatcher) (*UDPConn, *UDPConn, cha
 	dispatcher["/server/close"] = Method(func(msg Message) error {
 		return server.Close()
 	})
+	if configure != nil {
+		configure(server)
+	}
 	errChan := make(chan error)
 
 	go func() {
@@ -119,8 +128,9 @@ func testUDPServer(t *testing.T, dispatcher Dispatcher) (*UDPConn, *UDPConn, cha
 }
 
 func TestUDPConnSend_OK(t *testing.T) {
-	server, conn, errChan := testUDPServer(t, nil)
-	server.SetExactMatch(true)
+	_, conn, errChan := testUDPServer(t, nil, func(server *UDPConn) {
+		server.SetExactMatch(true)
+	})
 	if err := conn.Send(Message{Address: "/server/close"}); err != nil {
 		t.Fatal(err)
 	}
@@ -132,6 +142,18 @@ func TestUDPConnSend_OK(t *testing.T) {
 func TestUDPConnSend_ExactMatch(t *testing.T) {
 }
 
+func TestUDPConnSend_CaseInsensitive(t *testing.T) {
+	_, conn, errChan := testUDPServer(t, nil, func(server *UDPConn) {
+		server.SetCaseInsensitive(true)
+	})
+	if err := conn.Send(Message{Address: "/Server/Close"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := <-errChan; err != nil {
+		t.Fatal(err)
+	}
+}
+
 // errUDPConn is an implementation of the udpConn interface that returns errors from all it's methods.
 type errUDPConn struct {
 	udpConn
@@ -145,6 +167,394 @@ func (e errUDPConn) SetWriteBuffer(bytes int) error {
 	return errors.New("derp")
 }
 
+func TestUDPConnServe_Transformer(t *testing.T) {
+	var (
+		dropped  = make(chan Message, 1)
+		received = make(chan Message, 1)
+	)
+	server, conn, _ := testUDPServer(t, Dispatcher{
+		"/drop":    Method(func(msg Message) error { dropped <- msg; return nil }),
+		"/v2/drop": Method(func(msg Message) error { dropped <- msg; return nil }),
+		"/v2/foo":  Method(func(msg Message) error { received <- msg; return nil }),
+	}, func(server *UDPConn) {
+		server.SetExactMatch(true)
+		server.AddTransformer(func(msg Message) (Message, bool, error) {
+			return msg, msg.Address != "/drop", nil
+		})
+		server.AddTransformer(func(msg Message) (Message, bool, error) {
+			msg.Address = "/v2" + msg.Address
+			return msg, true, nil
+		})
+	})
+	defer func() { _ = server.Close() }() // Best effort.
+	defer func() { _ = conn.Close() }()   // Best effort.
+
+	for _, addr := range []string{"/drop", "/foo"} {
+		if err := conn.Send(Message{Address: addr}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	select {
+	case msg := <-received:
+		if expected, got := "/v2/foo", msg.Address; expected != got {
+			t.Fatalf("expected %s, got %s", expected, got)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for message")
+	}
+	// With a single worker the dropped message would have been handled first.
+	select {
+	case msg := <-dropped:
+		t.Fatalf("expected message to be dropped, got %v", msg)
+	default:
+	}
+}
+
+func TestUDPConnServe_Interceptor(t *testing.T) {
+	received := make(chan Message, 2)
+	server, conn, _ := testUDPServer(t, Dispatcher{
+		"/foo": Method(func(msg Message) error { received <- msg; return nil }),
+	}, func(server *UDPConn) {
+		server.SetExactMatch(true)
+
+		// The interceptor runs before transformers that were added earlier.
+		server.AddTransformer(func(msg Message) (Message, bool, error) {
+			if len(msg.Arguments) > 0 {
+				return msg, false, errors.New("expected token to be stripped")
+			}
+			return msg, true, nil
+		})
+		server.SetInterceptor(func(msg Message) (Message, bool, error) {
+			if len(msg.Arguments) == 0 || !msg.Arguments[0].Equal(String("secret")) {
+				return msg, false, nil
+			}
+			msg.Arguments = msg.Arguments[1:]
+			return msg, true, nil
+		})
+	})
+	defer func() { _ = server.Close() }() // Best effort.
+	defer func() { _ = conn.Close() }()   // Best effort.
+
+	for _, token := range []string{"wrong", "secret"} {
+		if err := conn.Send(Message{Address: "/foo", Arguments: []Argument{String(token)}}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	select {
+	case msg := <-received:
+		if expected, got := 0, len(msg.Arguments); expected != got {
+			t.Fatalf("expected %d arguments, got %d", expected, got)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for message")
+	}
+	// With a single worker the message with the wrong token would have been handled first.
+	select {
+	case msg := <-received:
+		t.Fatalf("expected message to be dropped, got %v", msg)
+	default:
+	}
+}
+
+func TestUDPConnServe_Deduplication(t *testing.T) {
+	received := make(chan string, 3)
+	handler := Method(func(msg Message) error {
+		received <- msg.Address
+		return nil
+	})
+	server, conn, _ := testUDPServer(t, Dispatcher{"/foo": handler, "/bar": handler}, func(server *UDPConn) {
+		server.SetExactMatch(true)
+		server.SetDeduplication(time.Minute)
+	})
+	defer func() { _ = server.Close() }() // Best effort.
+	defer func() { _ = conn.Close() }()   // Best effort.
+
+	for _, addr := range []string{"/foo", "/foo", "/bar"} {
+		if err := conn.Send(Message{Address: addr}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, expected := range []string{"/foo", "/bar"} {
+		select {
+		case got := <-received:
+			if expected != got {
+				t.Fatalf("expected %s, got %s", expected, got)
+			}
+		case <-time.After(time.Second):
+			t.Fatal("timed out waiting for message")
+		}
+	}
+}
+
+func TestUDPConnServe_SenderState(t *testing.T) {
+	// The handler counts the messages per sender.
+	counts := make(chan int, 4)
+	server, conn1, _ := testUDPServer(t, Dispatcher{"/count": Method(func(msg Message) error {
+		var n int
+		msg.HandlerContext().Store().Update("count", func(v interface{}, ok bool) interface{} {
+			if ok {
+				n = v.(int)
+			}
+			n++
+			return n
+		})
+		counts <- n
+		return nil
+	})}, func(server *UDPConn) {
+		server.SetSenderState(time.Minute)
+	})
+	defer func() { _ = server.Close() }() // Best effort.
+	defer func() { _ = conn1.Close() }()  // Best effort.
+
+	raddr, err := net.ResolveUDPAddr("udp", server.LocalAddr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	conn2, err := DialUDP("udp", nil, raddr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = conn2.Close() }() // Best effort.
+
+	for i, c := range []struct {
+		conn     *UDPConn
+		expected int
+	}{
+		{conn: conn1, expected: 1},
+		{conn: conn1, expected: 2},
+		{conn: conn2, expected: 1},
+		{conn: conn1, expected: 3},
+	} {
+		if err := c.conn.Send(Message{Address: "/count"}); err != nil {
+			t.Fatal(err)
+		}
+		select {
+		case got := <-counts:
+			if c.expected != got {
+				t.Fatalf("(message %d) expected count %d, got %d", i, c.expected, got)
+			}
+		case <-time.After(time.Second):
+			t.Fatal("timed out waiting for message")
+		}
+	}
+}
+
+func TestUDPConnServe_HandlerTimeout(t *testing.T) {
+	type dispatchError struct {
+		p   Packet
+		err error
+	}
+	var (
+		dispatchErrors = make(chan dispatchError, 2)
+		received       = make(chan Message, 1)
+		canceled       = make(chan error, 1)
+		release        = make(chan struct{})
+	)
+	server, conn, _ := testUDPServer(t, Dispatcher{
+		"/slow": Method(func(msg Message) error {
+			<-msg.Context().Done()
+			canceled <- msg.Context().Err()
+			<-release
+			return nil
+		}),
+		"/fail": Method(func(msg Message) error { return errors.New("oops") }),
+		"/foo":  Method(func(msg Message) error { received <- msg; return nil }),
+	}, func(server *UDPConn) {
+		server.SetExactMatch(true)
+		server.SetHandlerTimeout(10 * time.Millisecond)
+		server.SetDispatchErrorHandler(func(p Packet, err error) {
+			dispatchErrors <- dispatchError{p: p, err: err}
+		})
+	})
+	defer func() { _ = conn.Close() }() // Best effort.
+
+	for _, addr := range []string{"/slow", "/fail", "/foo"} {
+		if err := conn.Send(Message{Address: addr}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, expected := range []struct {
+		address string
+		cause   error
+	}{
+		{address: "/slow", cause: ErrHandlerTimeout},
+		{address: "/fail"},
+	} {
+		select {
+		case de := <-dispatchErrors:
+			if got := de.p.(Message).Address; expected.address != got {
+				t.Fatalf("expected %s, got %s", expected.address, got)
+			}
+			if expected.cause != nil && errors.Cause(de.err) != expected.cause {
+				t.Fatalf("expected %v, got %v", expected.cause, de.err)
+			}
+			if expected.cause == nil && !strings.Contains(de.err.Error(), "oops") {
+				t.Fatalf("expected the handler's error, got %v", de.err)
+			}
+		case <-time.After(time.Second):
+			t.Fatal("timed out waiting for dispatch error")
+		}
+	}
+	// The slow handler's context is done once the timeout expired.
+	if err := <-canceled; err != context.DeadlineExceeded {
+		t.Fatalf("expected %v, got %v", context.DeadlineExceeded, err)
+	}
+	// Serving continues after the timeout, even though the slow handler is still running.
+	select {
+	case msg := <-received:
+		if expected, got := "/foo", msg.Address; expected != got {
+			t.Fatalf("expected %s, got %s", expected, got)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for message")
+	}
+	// Shutdown waits for the slow handler to return.
+	shutdown := make(chan error, 1)
+	go func() {
+		shutdown <- server.Shutdown(context.Background())
+	}()
+	select {
+	case err := <-shutdown:
+		t.Fatalf("expected Shutdown to wait for the slow handler, got %v", err)
+	case <-time.After(20 * time.Millisecond):
+	}
+	close(release)
+
+	if err := <-shutdown; err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestUDPConnServe_ErrorHandler(t *testing.T) {
+	type parseError struct {
+		data []byte
+		err  error
+	}
+	var (
+		parseErrors = make(chan parseError, 2)
+		received    = make(chan Message, 1)
+	)
+	server, conn, _ := testUDPServer(t, Dispatcher{
+		"/foo": Method(func(msg Message) error {
+			received <- msg
+			return nil
+		}),
+	}, func(server *UDPConn) {
+		server.SetErrorHandler(func(data []byte, sender net.Addr, err error) {
+			parseErrors <- parseError{data: data, err: err}
+		})
+	})
+	defer func() { _ = server.Close() }() // Best effort.
+	defer func() { _ = conn.Close() }()   // Best effort.
+
+	for _, packet := range []Packet{badPacket{}, Message{Address: "/foo"}} {
+		if err := conn.Send(packet); err != nil {
+			t.Fatal(err)
+		}
+	}
+	select {
+	case pe := <-parseErrors:
+		if pe.err == nil {
+			t.Fatal("expected error, got nil")
+		}
+		if expected, got := (badPacket{}).Bytes(), pe.data; !bytes.Equal(expected, got) {
+			t.Fatalf("expected %q, got %q", expected, got)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for parse error")
+	}
+	// The server keeps serving after a parse error.
+	select {
+	case <-received:
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for message")
+	}
+}
+
+func TestUDPConnServe_MaxArguments(t *testing.T) {
+	var (
+		parseErrors = make(chan error, 2)
+		received    = make(chan Message, 2)
+	)
+	server, conn, _ := testUDPServer(t, Dispatcher{
+		"/foo": Method(func(msg Message) error {
+			received <- msg
+			return nil
+		}),
+	}, func(server *UDPConn) {
+		server.SetMaxArguments(2)
+		server.SetErrorHandler(func(data []byte, sender net.Addr, err error) {
+			parseErrors <- err
+		})
+	})
+	defer func() { _ = server.Close() }() // Best effort.
+	defer func() { _ = conn.Close() }()   // Best effort.
+
+	var (
+		tooMany = Message{Address: "/foo", Arguments: []Argument{Int(1), Int(2), Int(3)}}
+		ok      = Message{Address: "/foo", Arguments: []Argument{Int(1), Int(2)}}
+	)
+	for _, packet := range []Packet{
+		tooMany,
+		Bundle{Timetag: Immediately, Packets: []Packet{tooMany}},
+		ok,
+	} {
+		if err := conn.Send(packet); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for i := 0; i < 2; i++ {
+		select {
+		case err := <-parseErrors:
+			if errors.Cause(err) != ErrTooManyArguments {
+				t.Fatalf("expected ErrTooManyArguments, got %v", err)
+			}
+		case <-time.After(time.Second):
+			t.Fatal("timed out waiting for parse error")
+		}
+	}
+	select {
+	case msg := <-received:
+		if !ok.Equal(msg) {
+			t.Fatalf("expected %v, got %v", ok, msg)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for message")
+	}
+}
+
+func TestUDPConnEncode(t *testing.T) {
+	raddr, err := net.ResolveUDPAddr("udp", "127.0.0.1:9")
+	if err != nil {
+		t.Fatal(err)
+	}
+	conn, err := DialUDP("udp", nil, raddr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = conn.Close() }() // Best effort.
+
+	msg := Message{Address: "/foo", Arguments: []Argument{Int(1), String("bar")}}
+	for _, p := range []Packet{
+		msg,
+		Bundle{Timetag: Immediately, Packets: []Packet{msg}},
+	} {
+		got, err := conn.Encode(p)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if expected := p.Bytes(); !bytes.Equal(expected, got) {
+			t.Fatalf("expected %x, got %x", expected, got)
+		}
+	}
+	if _, err := conn.Encode(nil); err != ErrNilPacket {
+		t.Fatalf("expected ErrNilPacket, got %v", err)
+	}
+	if err := conn.Send(nil); err != ErrNilPacket {
+		t.Fatalf("expected ErrNilPacket, got %v", err)
+	}
+}
+
 func TestUDPConnServe_ContextTimeout(t *testing.T) {
 	laddr, err := net.ResolveUDPAddr("udp", "127.0.0.1:0")
 	if err != nil {
@@ -233,24 +643,65 @@ func TestUDPConnServe_BadInboundAddr(t *testing.T) {
 		badPacket{},
 	} {
 		// Send a message with a bad address.
+		parseErrors := make(chan error, 1)
 		_, conn, errChan := testUDPServer(t, Dispatcher{
 			"/foo": Method(func(msg Message) error {
 				return nil
 			}),
+		}, func(server *UDPConn) {
+			server.SetErrorHandler(func(data []byte, sender net.Addr, err error) {
+				parseErrors <- err
+			})
 		})
 		if err := conn.Send(packet); err != nil {
 			t.Fatal(err)
 		}
 		t.Logf("sent message %s", string(packet.Bytes()))
 
-		if err := <-errChan; err == nil {
-			t.Fatalf("(packet %d) expected error, got nil", i)
+		select {
+		case err := <-errChan:
+			if err == nil {
+				t.Fatalf("(packet %d) expected error, got nil", i)
+			}
+		case err := <-parseErrors:
+			if err == nil {
+				t.Fatalf("(packet %d) expected error, got nil", i)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("(packet %d) timed out waiting for error", i)
+		}
+	}
+}
+
+func TestUDPConnServe_DefaultErrorHandler(t *testing.T) {
+	buf := &bytes.Buffer{}
+	log.SetOutput(buf)
+	defer log.SetOutput(os.Stderr)
+
+	_, conn, errChan := testUDPServer(t, nil, nil)
+	for _, packet := range []Packet{badPacket{}, Message{Address: "/server/close"}} {
+		if err := conn.Send(packet); err != nil {
+			t.Fatal(err)
 		}
 	}
+	// The bad packet is dropped and the server keeps serving.
+	if err := <-errChan; err != nil {
+		t.Fatal(err)
+	}
+	if expected, got := "WARN osc: dropping", buf.String(); !strings.Contains(got, expected) {
+		t.Fatalf("expected %q to contain %q", got, expected)
+	}
 }
 
 func TestUDPConnSendTo(t *testing.T) {
-	_, conn, errChan := testUDPServer(t, nil)
+	senders := make(chan net.Addr, 1)
+	server, conn, _ := testUDPServer(t, nil, func(server *UDPConn) {
+		server.SetErrorHandler(func(data []byte, sender net.Addr, err error) {
+			senders <- sender
+		})
+	})
+	defer func() { _ = server.Close() }() // Best effort.
+
 	laddr2, err := net.ResolveUDPAddr("udp", "127.0.0.1:0")
 	if err != nil {
 		t.Fatal(err)
@@ -259,11 +710,18 @@ func TestUDPConnSendTo(t *testing.T) {
 	if err != nil {
 		t.Fatal(err)
 	}
+	defer func() { _ = conn2.Close() }() // Best effort.
+
 	if err := conn2.SendTo(conn.RemoteAddr(), badPacket{}); err != nil {
 		t.Fatal(err)
 	}
-	if err := <-errChan; err == nil {
-		t.Fatal("expected error, got nil")
+	select {
+	case sender := <-senders:
+		if expected, got := conn2.LocalAddr().String(), sender.String(); expected != got {
+			t.Fatalf("expected sender %s, got %s", expected, got)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for packet")
 	}
 }
 
@@ -274,7 +732,7 @@ func TestUDPConnSendBundle(t *testing.T) {
 			Message{Address: "/server/close"},
 		},
 	}
-	_, conn, errChan := testUDPServer(t, nil)
+	_, conn, errChan := testUDPServer(t, nil, nil)
 	if err := conn.Send(b); err != nil {
 		t.Fatal(err)
 	}
@@ -284,15 +742,22 @@ func TestUDPConnSendBundle(t *testing.T) {
 }
 
 func TestUDPConnSendBundle_BadTypetag(t *testing.T) {
-	_, conn, errChan := testUDPServer(t, nil)
+	parseErrors := make(chan error, 1)
+	server, conn, _ := testUDPServer(t, nil, func(server *UDPConn) {
+		server.SetErrorHandler(func(data []byte, sender net.Addr, err error) {
+			parseErrors <- err
+		})
+	})
+	defer func() { _ = server.Close() }() // Best effort.
+
 	if err := conn.Send(badBundle{}); err != nil {
 		t.Fatal(err)
 	}
-	err := <-errChan
+	err := <-parseErrors
 	if err == nil {
 		t.Fatalf("expected error, got nil")
 	}
-	expected, got := `error serving udp: read packets: read packet: parse message from packet: parse message: read argument 0: typetag "Q": invalid type tag`, err.Error()
+	expected, got := `read packets: read packet: parse message from packet: parsing argument 0 (typetag 'Q'): invalid type tag`, err.Error()
 	if expected != got {
 		t.Fatal(err)
 	}
@@ -309,7 +774,7 @@ func TestUDPConnSendBundle_DispatchError(t *testing.T) {
 		"/foo": Method(func(msg Message) error {
 			return errors.New("oops")
 		}),
-	})
+	}, nil)
 	if err := conn.Send(b); err != nil {
 		t.Fatal(err)
 	}
@@ -323,6 +788,98 @@ func TestUDPConnSendBundle_DispatchError(t *testing.T) {
 	}
 }
 
+func TestUDPConnSeq(t *testing.T) {
+	seqs := make(chan uint64, 3)
+
+	_, conn, errChan := testUDPServer(t, Dispatcher{
+		"/foo": Method(func(msg Message) error {
+			seqs <- msg.Seq
+			return nil
+		}),
+	}, nil)
+
+	var prev uint64
+	for i := 0; i < 3; i++ {
+		if err := conn.Send(Message{Address: "/foo"}); err != nil {
+			t.Fatal(err)
+		}
+		select {
+		case seq := <-seqs:
+			if seq <= prev {
+				t.Fatalf("expected sequence number greater than %d, got %d", prev, seq)
+			}
+			prev = seq
+		case <-time.After(time.Second):
+			t.Fatal("timeout")
+		}
+	}
+	if err := conn.Send(Message{Address: "/server/close"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := <-errChan; err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestUDPConnShutdown(t *testing.T) {
+	var (
+		started  = make(chan struct{})
+		finished = make(chan struct{})
+	)
+	server, conn, errChan := testUDPServer(t, Dispatcher{
+		"/slow": Method(func(msg Message) error {
+			close(started)
+			time.Sleep(50 * time.Millisecond)
+			close(finished)
+			return nil
+		}),
+	}, nil)
+	if err := conn.Send(Message{Address: "/slow"}); err != nil {
+		t.Fatal(err)
+	}
+	<-started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	if err := server.Shutdown(ctx); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-finished:
+	default:
+		t.Fatal("expected Shutdown to wait for the handler")
+	}
+	if err := <-errChan; err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestUDPConnShutdown_ContextExpired(t *testing.T) {
+	var (
+		started = make(chan struct{})
+		release = make(chan struct{})
+	)
+	defer close(release)
+
+	server, conn, _ := testUDPServer(t, Dispatcher{
+		"/stuck": Method(func(msg Message) error {
+			close(started)
+			<-release
+			return nil
+		}),
+	}, nil)
+	if err := conn.Send(Message{Address: "/stuck"}); err != nil {
+		t.Fatal(err)
+	}
+	<-started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := server.Shutdown(ctx); err != context.DeadlineExceeded {
+		t.Fatalf("expected context.DeadlineExceeded, got %+v", err)
+	}
+}
+
 // badPacket is a Packet that returns an OSC message with typetag 'Q'
 type badPacket struct{}
 
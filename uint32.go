@@ -0,0 +1,101 @@
+package osc
+
+import (
+	"fmt"
+	"io"
+	"math"
+
+	"github.com/pkg/errors"
+)
+
+// TypetagUInt32 is the type tag of UInt32 arguments.
+// It is not part of the OSC specification, so some receivers may reject messages that use it.
+const TypetagUInt32 byte = 'u'
+
+// UInt32 represents an unsigned 32-bit integer, e.g. a DMX or color value.
+// It is a non-standard extension, see TypetagUInt32.
+// Use Int.UInt32 for devices that send unsigned values as ints.
+type UInt32 uint32
+
+// ReadUInt32From reads an unsigned 32-bit integer from a byte slice.
+func ReadUInt32From(data []byte) (Argument, int64, error) {
+	if len(data) < 4 {
+		return nil, 0, errors.Wrap(io.ErrUnexpectedEOF, "read uint32 argument")
+	}
+	return UInt32(byteOrder.Uint32(data)), 4, nil
+}
+
+// Bytes converts the arg to a byte slice suitable for adding to the binary representation of an OSC message.
+func (u UInt32) Bytes() []byte {
+	b := make([]byte, 4)
+	byteOrder.PutUint32(b, uint32(u))
+	return b
+}
+
+// Equal returns true if the argument equals the other one, false otherwise.
+func (u UInt32) Equal(other Argument) bool {
+	if other.Typetag() != TypetagUInt32 {
+		return false
+	}
+	u2 := other.(UInt32)
+	return u == u2
+}
+
+// ReadInt32 reads a 32-bit integer from the arg.
+// Values greater than math.MaxInt32 return an error wrapping ErrOutOfRange.
+func (u UInt32) ReadInt32() (int32, error) {
+	if u > math.MaxInt32 {
+		return 0, errors.Wrapf(ErrOutOfRange, "%d does not fit in an int32", u)
+	}
+	return int32(u), nil
+}
+
+// ReadUInt32 reads an unsigned 32-bit integer from the arg.
+func (u UInt32) ReadUInt32() (uint32, error) { return uint32(u), nil }
+
+// ReadInt64 reads a 64-bit integer from the arg.
+func (u UInt32) ReadInt64() (int64, error) { return 0, ErrInvalidTypeTag }
+
+// ReadFloat32 reads a 32-bit float from the arg.
+func (u UInt32) ReadFloat32() (float32, error) { return 0, ErrInvalidTypeTag }
+
+// ReadBool bool reads a boolean from the arg.
+func (u UInt32) ReadBool() (bool, error) { return false, ErrInvalidTypeTag }
+
+// ReadString string reads a string from the arg.
+func (u UInt32) ReadString() (string, error) { return "", ErrInvalidTypeTag }
+
+// ReadBlob reads a slice of bytes from the arg.
+func (u UInt32) ReadBlob() ([]byte, error) { return nil, ErrInvalidTypeTag }
+
+// String converts the arg to a string.
+func (u UInt32) String() string { return fmt.Sprintf("UInt32(%d)", u) }
+
+// Typetag returns the argument's type tag.
+func (u UInt32) Typetag() byte { return TypetagUInt32 }
+
+// Size returns the number of bytes of the encoded arg.
+func (u UInt32) Size() int { return 4 }
+
+// WriteTo writes the arg to an io.Writer.
+func (u UInt32) WriteTo(w io.Writer) (int64, error) {
+	written, err := fmt.Fprintf(w, "%d", u)
+	return int64(written), err
+}
+
+// UInt32 returns the int's bits as an unsigned integer.
+// This is for devices that send unsigned values in [0, 4294967295] as ints,
+// which are read as negative numbers when they are greater than math.MaxInt32.
+func (i Int) UInt32() UInt32 {
+	return UInt32(uint32(i))
+}
+
+// ReadUInt32 reads an unsigned 32-bit integer from the arg.
+// Negative values return an error wrapping ErrOutOfRange, see UInt32
+// for reading the int's bits as an unsigned integer.
+func (i Int) ReadUInt32() (uint32, error) {
+	if i < 0 {
+		return 0, errors.Wrapf(ErrOutOfRange, "%d does not fit in a uint32", i)
+	}
+	return uint32(i), nil
+}
@@ -0,0 +1,72 @@
+package osc
+
+import (
+	"bytes"
+	"math"
+	"testing"
+
+	"github.com/pkg/errors"
+)
+
+func TestUInt32(t *testing.T) {
+	msg := Message{Address: "/dmx", Arguments: []Argument{UInt32(math.MaxUint32), UInt32(7)}}
+	data := msg.Bytes()
+
+	if expected, got := ",uu", string(bytes.TrimRight(data[8:12], "\x00")); expected != got {
+		t.Fatalf("expected type tags %q, got %q", expected, got)
+	}
+	got, err := ParseMessage(data, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !msg.Equal(got) {
+		t.Fatalf("expected %v, got %v", msg, got)
+	}
+	buf := make([]byte, msg.EncodedLen())
+	if _, err := msg.MarshalTo(buf); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(data, buf) {
+		t.Fatalf("expected %x, got %x", data, buf)
+	}
+
+	u, err := got.Arguments[0].(UInt32).ReadUInt32()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected, got := uint32(math.MaxUint32), u; expected != got {
+		t.Fatalf("expected %d, got %d", expected, got)
+	}
+	if _, err := got.Arguments[0].ReadInt32(); errors.Cause(err) != ErrOutOfRange {
+		t.Fatalf("expected ErrOutOfRange, got %v", err)
+	}
+	i, err := got.Arguments[1].ReadInt32()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected, got := int32(7), i; expected != got {
+		t.Fatalf("expected %d, got %d", expected, got)
+	}
+	if _, _, err := ReadUInt32From([]byte{1, 2}); err == nil {
+		t.Fatal("expected error, got nil")
+	}
+	if UInt32(1).Equal(Int(1)) {
+		t.Fatal("expected UInt32 and Int to differ")
+	}
+}
+
+func TestIntUInt32(t *testing.T) {
+	if expected, got := UInt32(math.MaxUint32), Int(-1).UInt32(); expected != got {
+		t.Fatalf("expected %d, got %d", expected, got)
+	}
+	if _, err := Int(-1).ReadUInt32(); errors.Cause(err) != ErrOutOfRange {
+		t.Fatalf("expected ErrOutOfRange, got %v", err)
+	}
+	u, err := Int(255).ReadUInt32()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected, got := uint32(255), u; expected != got {
+		t.Fatalf("expected %d, got %d", expected, got)
+	}
+}
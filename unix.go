@@ -5,6 +5,7 @@ import (
 	"net"
 	"os"
 	"path/filepath"
+	"time"
 
 	ulid "github.com/imdario/go-ulid"
 	"github.com/pkg/errors"
@@ -21,10 +22,12 @@ type unixConn interface {
 type UnixConn struct {
 	unixConn
 
-	closeChan  chan struct{}
-	ctx        context.Context
-	errChan    chan error
-	exactMatch bool
+	closeChan chan struct{}
+	ctx       context.Context
+	errChan   chan error
+	handlers  inflight
+
+	serveConfig
 }
 
 // DialUnix opens a unix socket for OSC communication.
@@ -97,33 +100,78 @@ func (conn *UnixConn) read(data []byte) (int, net.Addr, error) {
 
 // Send sends a Packet.
 func (conn *UnixConn) Send(p Packet) error {
-	_, err := conn.Write(p.Bytes())
+	data, err := conn.Encode(p)
+	if err != nil {
+		return err
+	}
+	_, err = conn.Write(data)
 	return err
 }
 
+// Encode returns the bytes Send would transmit for the packet without sending them,
+// e.g. to assert on them in tests.
+func (conn *UnixConn) Encode(p Packet) ([]byte, error) {
+	if p == nil {
+		return nil, ErrNilPacket
+	}
+	return p.Bytes(), nil
+}
+
 // SendTo sends a Packet to the provided net.Addr.
 func (conn *UnixConn) SendTo(addr net.Addr, p Packet) error {
 	_, err := conn.WriteTo(p.Bytes(), addr)
 	return err
 }
 
+// ReadPacket reads the next packet and returns the address of its sender,
+// see Transport. It returns io.EOF once the conn is closed.
+// Messages with more arguments than allowed by SetMaxArguments are parse errors.
+func (conn *UnixConn) ReadPacket() (Packet, net.Addr, error) {
+	return readTransportPacket(conn, ParseOptions{MaxArguments: conn.maxArguments})
+}
+
+// WritePacket sends a packet to addr, or to the remote address of the conn if addr is nil,
+// see Transport.
+func (conn *UnixConn) WritePacket(p Packet, addr net.Addr) error {
+	if addr == nil {
+		return conn.Send(p)
+	}
+	if p == nil {
+		return ErrNilPacket
+	}
+	return conn.SendTo(addr, p)
+}
+
 // Serve starts dispatching OSC.
 // Any errors returned from a dispatched method will be returned.
 // Note that this means that errors returned from a dispatcher method will kill your server.
 // If context.Canceled or context.DeadlineExceeded are encountered they will be returned directly.
 func (conn *UnixConn) Serve(numWorkers int, dispatcher Dispatcher) error {
-	return serve(conn, numWorkers, conn.exactMatch, dispatcher)
+	return serve(conn.ctx, conn, &conn.handlers, numWorkers, conn.serveOptions(), dispatcher)
+}
+
+// Shutdown gracefully shuts down the connection.
+// It stops reading new packets, waits for the packets that are being
+// handled to be done, and then closes the connection.
+// If the context expires first the connection is closed anyway and
+// the context's error is returned.
+func (conn *UnixConn) Shutdown(ctx context.Context) error {
+	idle := conn.handlers.drain()
+
+	// Unblock the read loop.
+	if err := conn.SetReadDeadline(time.Now()); err != nil {
+		return errors.Wrap(err, "setting read deadline")
+	}
+	select {
+	case <-idle:
+		return conn.Close()
+	case <-ctx.Done():
+		_ = conn.Close() // Best effort.
+		return ctx.Err()
+	}
 }
 
 // TempSocket creates an absolute path to a temporary socket file.
 func TempSocket() string {
 	return filepath.Join(os.TempDir(), ulid.New().String()) + ".sock"
 }
-
-// SetExactMatch changes the behavior of the Serve method so that
-// messages will only be dispatched to methods whose addresses
-// match the message's address exactly.
-// This should provide some performance improvement.
-func (conn *UnixConn) SetExactMatch(value bool) {
-	conn.exactMatch = value
-}
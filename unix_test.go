@@ -1,6 +1,7 @@
 package osc
 
 import (
+	"context"
 	"net"
 	"testing"
 	"time"
@@ -144,3 +145,44 @@ func TestUnixSendTo(t *testing.T) {
 		t.Fatal(err)
 	}
 }
+
+func TestUnixShutdown(t *testing.T) {
+	var (
+		started  = make(chan struct{})
+		finished = make(chan struct{})
+	)
+	server, errChan := tmpListener(t, Dispatcher{
+		"/slow": Method(func(m Message) error {
+			close(started)
+			time.Sleep(50 * time.Millisecond)
+			close(finished)
+			return nil
+		}),
+	})
+	addr, err := net.ResolveUnixAddr("unixgram", server.LocalAddr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	conn, err := DialUnix("unixgram", nil, addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := conn.Send(Message{Address: "/slow"}); err != nil {
+		t.Fatal(err)
+	}
+	<-started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	if err := server.Shutdown(ctx); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-finished:
+	default:
+		t.Fatal("expected Shutdown to wait for the handler")
+	}
+	if err := <-errChan; err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,91 @@
+package osc
+
+import (
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// URIScheme is the scheme of OSC URIs, e.g. osc://localhost:9000/synth/freq.
+const URIScheme = "osc"
+
+// ParseOSCURI parses an OSC URI of the form osc://host:port/address.
+// The address is empty if the URI has no path.
+func ParseOSCURI(uri string) (host string, port int, address string, err error) {
+	u, err := url.Parse(uri)
+	if err != nil {
+		return "", 0, "", errors.Wrap(err, "parse uri")
+	}
+	if u.Scheme != URIScheme {
+		return "", 0, "", errors.Errorf("expected scheme %s, got %q", URIScheme, u.Scheme)
+	}
+	if host = u.Hostname(); host == "" {
+		return "", 0, "", errors.Errorf("uri %s has no host", uri)
+	}
+	if port, err = parsePort(u.Port()); err != nil {
+		return "", 0, "", err
+	}
+	if err := ValidateAddress(u.Path); err != nil {
+		return "", 0, "", errors.Wrapf(err, "uri %s", uri)
+	}
+	return host, port, u.Path, nil
+}
+
+// FormatOSCURI returns the OSC URI for the given host, port and address.
+// The address may be empty, otherwise it must start with '/'.
+func FormatOSCURI(host string, port int, address string) (string, error) {
+	if host == "" {
+		return "", errors.New("host must not be empty")
+	}
+	if port < 1 || port > 65535 {
+		return "", errors.Errorf("invalid port %d", port)
+	}
+	if address != "" && !strings.HasPrefix(address, string(MessageChar)) {
+		return "", errors.Wrapf(ErrInvalidAddress, "address %s", address)
+	}
+	if err := ValidateAddress(address); err != nil {
+		return "", errors.Wrapf(err, "address %s", address)
+	}
+	u := url.URL{
+		Scheme: URIScheme,
+		Host:   net.JoinHostPort(host, strconv.Itoa(port)),
+		Path:   address,
+	}
+	return u.String(), nil
+}
+
+// DialURI dials the host and port of an OSC URI over UDP.
+// The address is returned so it can be used for the messages sent with the connection.
+func DialURI(uri string) (*UDPConn, string, error) {
+	host, port, address, err := ParseOSCURI(uri)
+	if err != nil {
+		return nil, "", err
+	}
+	raddr, err := net.ResolveUDPAddr("udp", net.JoinHostPort(host, strconv.Itoa(port)))
+	if err != nil {
+		return nil, "", errors.Wrap(err, "resolve udp address")
+	}
+	conn, err := DialUDP("udp", nil, raddr)
+	if err != nil {
+		return nil, "", err
+	}
+	return conn, address, nil
+}
+
+// parsePort parses a port number from a URI.
+func parsePort(s string) (int, error) {
+	if s == "" {
+		return 0, errors.New("uri has no port")
+	}
+	port, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, errors.Wrap(err, "parse port")
+	}
+	if port < 1 || port > 65535 {
+		return 0, errors.Errorf("invalid port %d", port)
+	}
+	return port, nil
+}
@@ -0,0 +1,81 @@
+package osc
+
+import (
+	"testing"
+)
+
+func TestParseOSCURI(t *testing.T) {
+	for _, testcase := range []struct {
+		URI     string
+		Host    string
+		Port    int
+		Address string
+	}{
+		{URI: "osc://localhost:9000/synth/freq", Host: "localhost", Port: 9000, Address: "/synth/freq"},
+		{URI: "osc://127.0.0.1:57120", Host: "127.0.0.1", Port: 57120, Address: ""},
+		{URI: "osc://[::1]:9000/foo", Host: "::1", Port: 9000, Address: "/foo"},
+	} {
+		host, port, address, err := ParseOSCURI(testcase.URI)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if host != testcase.Host || port != testcase.Port || address != testcase.Address {
+			t.Fatalf("(%s) expected %s %d %s, got %s %d %s", testcase.URI, testcase.Host, testcase.Port, testcase.Address, host, port, address)
+		}
+		uri, err := FormatOSCURI(host, port, address)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if expected, got := testcase.URI, uri; expected != got {
+			t.Fatalf("expected %s, got %s", expected, got)
+		}
+	}
+	for _, uri := range []string{
+		"http://localhost:9000/foo",
+		"osc://localhost/foo",
+		"osc://:9000/foo",
+		"osc://localhost:0/foo",
+		"osc://localhost:99999/foo",
+		"osc://localhost:9000/foo bar",
+		"osc://%zz",
+	} {
+		if _, _, _, err := ParseOSCURI(uri); err == nil {
+			t.Fatalf("(%s) expected error, got nil", uri)
+		}
+	}
+}
+
+func TestFormatOSCURI(t *testing.T) {
+	for _, testcase := range []struct {
+		Host    string
+		Port    int
+		Address string
+	}{
+		{Host: "", Port: 9000, Address: "/foo"},
+		{Host: "localhost", Port: 0, Address: "/foo"},
+		{Host: "localhost", Port: 9000, Address: "foo"},
+		{Host: "localhost", Port: 9000, Address: "/foo bar"},
+	} {
+		if _, err := FormatOSCURI(testcase.Host, testcase.Port, testcase.Address); err == nil {
+			t.Fatalf("(%v) expected error, got nil", testcase)
+		}
+	}
+}
+
+func TestDialURI(t *testing.T) {
+	conn, address, err := DialURI("osc://127.0.0.1:9000/synth/freq")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = conn.Close() }() // Best effort.
+
+	if expected, got := "/synth/freq", address; expected != got {
+		t.Fatalf("expected %s, got %s", expected, got)
+	}
+	if expected, got := "127.0.0.1:9000", conn.RemoteAddr().String(); expected != got {
+		t.Fatalf("expected %s, got %s", expected, got)
+	}
+	if _, _, err := DialURI("osc://127.0.0.1/synth/freq"); err == nil {
+		t.Fatal("expected error, got nil")
+	}
+}
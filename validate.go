@@ -0,0 +1,127 @@
+package osc
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Common errors.
+var (
+	ErrInvalidArgument = errors.New("invalid argument")
+	ErrInvalidTimetag  = errors.New("invalid timetag")
+)
+
+// ValidationError is returned when a packet is invalid.
+// Path locates the invalid part of the packet, e.g. "packet[2].arg[1]".
+type ValidationError struct {
+	Path string
+	Err  error
+}
+
+// Error returns the error message.
+func (e ValidationError) Error() string {
+	if e.Path == "" {
+		return e.Err.Error()
+	}
+	return e.Path + ": " + e.Err.Error()
+}
+
+// Cause returns the underlying error, so errors.Cause works with validation errors.
+func (e ValidationError) Cause() error {
+	return e.Err
+}
+
+// Validate returns an error if the message can not be sent.
+// The address must be a valid address pattern, and every argument must be
+// non-nil, have a known type tag, and be encodable.
+func (msg Message) Validate() error {
+	if err := validateAddressPattern(msg.Address); err != nil {
+		return ValidationError{Path: "address", Err: err}
+	}
+	for i, a := range msg.Arguments {
+		if err := validateArgument(a); err != nil {
+			return ValidationError{Path: fmt.Sprintf("arg[%d]", i), Err: err}
+		}
+	}
+	return nil
+}
+
+// Validate returns an error if the bundle can not be sent.
+// Every message in the bundle must be valid, see Message.Validate,
+// and the timetag of every nested bundle must not be earlier than
+// the timetag of the bundle that contains it.
+func (b Bundle) Validate() error {
+	for i, p := range b.Packets {
+		path := fmt.Sprintf("packet[%d]", i)
+
+		var err error
+		switch x := p.(type) {
+		case Message:
+			err = x.Validate()
+		case Bundle:
+			if x.Timetag < b.Timetag {
+				return ValidationError{
+					Path: path,
+					Err:  errors.Wrapf(ErrInvalidTimetag, "%s is earlier than the enclosing bundle's %s", x.Timetag, b.Timetag),
+				}
+			}
+			err = x.Validate()
+		case nil:
+			return ValidationError{Path: path, Err: errors.New("packet is nil")}
+		}
+		if err != nil {
+			return prefixValidationError(path, err)
+		}
+	}
+	return nil
+}
+
+// prefixValidationError adds a prefix to the path of a validation error.
+func prefixValidationError(prefix string, err error) error {
+	ve, ok := err.(ValidationError)
+	if !ok {
+		return ValidationError{Path: prefix, Err: err}
+	}
+	if ve.Path != "" {
+		prefix += "." + ve.Path
+	}
+	return ValidationError{Path: prefix, Err: ve.Err}
+}
+
+// validateAddressPattern returns an error if address is not a valid address pattern.
+func validateAddressPattern(address string) error {
+	if len(address) == 0 || address[0] != MessageChar {
+		return errors.Wrapf(ErrInvalidAddress, "address %q must start with %q", address, string(MessageChar))
+	}
+	if strings.ContainsAny(address, " #\x00") {
+		return errors.Wrapf(ErrInvalidAddress, "address %q contains an invalid character", address)
+	}
+	if _, err := GetRegex(address); err != nil {
+		return errors.Wrapf(ErrInvalidAddress, "address %q: %s", address, err)
+	}
+	return nil
+}
+
+// validateArgument returns an error if the argument can not be encoded.
+func validateArgument(a Argument) error {
+	if a == nil {
+		return errors.Wrap(ErrInvalidArgument, "argument is nil")
+	}
+	switch x := a.(type) {
+	case String:
+		if strings.IndexByte(string(x), 0) >= 0 {
+			return errors.Wrap(ErrInvalidArgument, "string contains a null byte")
+		}
+	case Blob:
+		if int64(len(x)) > 1<<31-1 {
+			return errors.Wrap(ErrInvalidArgument, "blob is too long")
+		}
+	default:
+		if tt := a.Typetag(); !isKnownTypeTag(tt) {
+			return errors.Wrapf(ErrInvalidTypeTag, "typetag %q", string(tt))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+package osc
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+func TestMessageValidate(t *testing.T) {
+	for _, msg := range []Message{
+		{Address: "/foo"},
+		{Address: "/synth/*/freq", Arguments: []Argument{Float(440), String("sine"), Bool(true), Blob{1}}},
+	} {
+		if err := msg.Validate(); err != nil {
+			t.Fatalf("(%v) %s", msg, err)
+		}
+	}
+	for _, testcase := range []struct {
+		Msg      Message
+		Path     string
+		Expected error
+	}{
+		{Msg: Message{Address: ""}, Path: "address", Expected: ErrInvalidAddress},
+		{Msg: Message{Address: "foo"}, Path: "address", Expected: ErrInvalidAddress},
+		{Msg: Message{Address: "/foo bar"}, Path: "address", Expected: ErrInvalidAddress},
+		{Msg: Message{Address: "/foo/[a"}, Path: "address", Expected: ErrInvalidAddress},
+		{Msg: Message{Address: "/foo", Arguments: []Argument{Int(1), nil}}, Path: "arg[1]", Expected: ErrInvalidArgument},
+		{Msg: Message{Address: "/foo", Arguments: []Argument{String("a\x00b")}}, Path: "arg[0]", Expected: ErrInvalidArgument},
+		{Msg: Message{Address: "/foo", Arguments: []Argument{rgba(0)}}, Path: "arg[0]", Expected: ErrInvalidTypeTag},
+	} {
+		err := testcase.Msg.Validate()
+		if errors.Cause(err) != testcase.Expected {
+			t.Fatalf("(%v) expected %v, got %v", testcase.Msg, testcase.Expected, err)
+		}
+		if expected, got := testcase.Path, err.(ValidationError).Path; expected != got {
+			t.Fatalf("expected %s, got %s", expected, got)
+		}
+	}
+}
+
+func TestBundleValidate(t *testing.T) {
+	now := FromTime(time.Now())
+
+	valid := Bundle{
+		Timetag: now,
+		Packets: []Packet{
+			Message{Address: "/foo", Arguments: []Argument{Int(1)}},
+			Bundle{Timetag: now + 1, Packets: []Packet{Message{Address: "/bar"}}},
+		},
+	}
+	if err := valid.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	invalid := Bundle{
+		Timetag: now,
+		Packets: []Packet{
+			Message{Address: "/foo"},
+			Message{Address: "/bar"},
+			Bundle{
+				Timetag: now,
+				Packets: []Packet{
+					Message{Address: "/baz", Arguments: []Argument{Int(1), nil}},
+				},
+			},
+		},
+	}
+	err := invalid.Validate()
+	if errors.Cause(err) != ErrInvalidArgument {
+		t.Fatalf("expected ErrInvalidArgument, got %v", err)
+	}
+	if expected, got := "packet[2].packet[0].arg[1]", err.(ValidationError).Path; expected != got {
+		t.Fatalf("expected %s, got %s", expected, got)
+	}
+	early := Bundle{
+		Timetag: now,
+		Packets: []Packet{Bundle{Timetag: now - 1}},
+	}
+	if err := early.Validate(); errors.Cause(err) != ErrInvalidTimetag {
+		t.Fatalf("expected ErrInvalidTimetag, got %v", err)
+	}
+}
@@ -0,0 +1,121 @@
+package osc
+
+import (
+	"context"
+	"reflect"
+	"regexp"
+	"sync"
+	"sync/atomic"
+)
+
+// waiter is a goroutine waiting in WaitFor.
+type waiter struct {
+	re *regexp.Regexp
+	c  chan Message
+}
+
+// waiters holds the goroutines waiting in WaitFor by dispatcher.
+// It is kept outside of the dispatchers so that waiting does not change them,
+// which makes it safe to call WaitFor while a dispatcher is being served.
+var waiters = struct {
+	sync.Mutex
+
+	// n is the number of waiters, read atomically so
+	// invoking a message does not lock when nobody is waiting.
+	n int32
+	m map[uintptr]map[*waiter]struct{}
+}{m: map[uintptr]map[*waiter]struct{}{}}
+
+// dispatcherKey identifies a dispatcher in waiters.
+func dispatcherKey(d Dispatcher) uintptr {
+	return reflect.ValueOf(d).Pointer()
+}
+
+// addWaiter registers a waiter for the messages invoked on d.
+func addWaiter(d Dispatcher, w *waiter) {
+	waiters.Lock()
+	defer waiters.Unlock()
+
+	key := dispatcherKey(d)
+	if waiters.m[key] == nil {
+		waiters.m[key] = map[*waiter]struct{}{}
+	}
+	waiters.m[key][w] = struct{}{}
+	atomic.AddInt32(&waiters.n, 1)
+}
+
+// removeWaiter deregisters a waiter.
+// It returns false if the waiter was already removed by notifyWaiters.
+func removeWaiter(d Dispatcher, w *waiter) bool {
+	waiters.Lock()
+	defer waiters.Unlock()
+
+	key := dispatcherKey(d)
+	if _, ok := waiters.m[key][w]; !ok {
+		return false
+	}
+	delete(waiters.m[key], w)
+	if len(waiters.m[key]) == 0 {
+		delete(waiters.m, key)
+	}
+	atomic.AddInt32(&waiters.n, -1)
+	return true
+}
+
+// notifyWaiters hands msg to the goroutines waiting for it on d
+// and deregisters them.
+func notifyWaiters(d Dispatcher, msg Message) {
+	if atomic.LoadInt32(&waiters.n) == 0 {
+		return
+	}
+	waiters.Lock()
+	defer waiters.Unlock()
+
+	key := dispatcherKey(d)
+	for w := range waiters.m[key] {
+		if !w.re.MatchString(msg.Address) {
+			continue
+		}
+		// Every waiter gets its own copy of the arguments.
+		cp := msg
+		cp.Arguments = append([]Argument(nil), msg.Arguments...)
+		w.c <- cp
+		delete(waiters.m[key], w)
+		atomic.AddInt32(&waiters.n, -1)
+	}
+	if len(waiters.m[key]) == 0 {
+		delete(waiters.m, key)
+	}
+}
+
+// WaitFor blocks until the dispatcher is invoked with a message whose address
+// matches pattern and returns a copy of the message, e.g. to wait for the response to a request.
+// pattern may contain OSC wildcards, see GetRegex.
+// If the context is done first, WaitFor returns the context's error.
+// Any number of goroutines can wait at the same time,
+// each of them receives the next matching message.
+//
+// WaitFor does not change the dispatcher, so it can be called while the dispatcher
+// is being served. The message is also handed to the dispatcher's methods as usual.
+func (d Dispatcher) WaitFor(ctx context.Context, pattern string) (Message, error) {
+	re, err := GetRegex(pattern)
+	if err != nil {
+		return Message{}, err
+	}
+	w := &waiter{
+		re: re,
+		c:  make(chan Message, 1), // Buffered so notifyWaiters does not block.
+	}
+	addWaiter(d, w)
+
+	select {
+	case msg := <-w.c:
+		return msg, nil
+	case <-ctx.Done():
+		if removeWaiter(d, w) {
+			return Message{}, ctx.Err()
+		}
+		// A message arrived while the context was done.
+		return <-w.c, nil
+	}
+}
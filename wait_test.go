@@ -0,0 +1,168 @@
+package osc
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+)
+
+// waitForWaiters waits until n goroutines are waiting on d.
+func waitForWaiters(d Dispatcher, n int) {
+	for {
+		waiters.Lock()
+		got := len(waiters.m[dispatcherKey(d)])
+		waiters.Unlock()
+		if got == n {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestDispatcherWaitFor(t *testing.T) {
+	var (
+		d       = Dispatcher{}
+		prev    = make(chan Message, 1)
+		results = make(chan Message, 3)
+	)
+	d["/foo"] = Method(func(msg Message) error {
+		prev <- msg
+		return nil
+	})
+	for i := 0; i < cap(results); i++ {
+		go func() {
+			msg, err := d.WaitFor(context.Background(), "/foo")
+			if err != nil {
+				t.Error(err)
+			}
+			results <- msg
+		}()
+	}
+	waitForWaiters(d, cap(results))
+
+	if expected, got := 1, len(d); expected != got {
+		t.Fatalf("expected WaitFor not to change the dispatcher, got %d handlers", got)
+	}
+	expected := Message{Address: "/foo", Arguments: []Argument{Int(1)}}
+	if err := d.Invoke(expected, true); err != nil {
+		t.Fatal(err)
+	}
+	var got []Message
+	for i := 0; i < cap(results); i++ {
+		msg := <-results
+		if !expected.Equal(msg) {
+			t.Fatalf("expected %v, got %v", expected, msg)
+		}
+		got = append(got, msg)
+	}
+	// Every waiter gets its own copy.
+	got[0].Arguments[0] = Int(2)
+	if !expected.Equal(got[1]) {
+		t.Fatalf("expected %v, got %v", expected, got[1])
+	}
+	// The method is still called.
+	if msg := <-prev; !expected.Equal(msg) {
+		t.Fatalf("expected %v, got %v", expected, msg)
+	}
+	// The waiters removed themselves.
+	waitForWaiters(d, 0)
+}
+
+func TestDispatcherWaitForPattern(t *testing.T) {
+	var (
+		d      = Dispatcher{}
+		result = make(chan Message, 1)
+	)
+	go func() {
+		msg, err := d.WaitFor(context.Background(), "/synth/*/freq")
+		if err != nil {
+			t.Error(err)
+		}
+		result <- msg
+	}()
+	waitForWaiters(d, 1)
+
+	for _, addr := range []string{"/synth/1/gain", "/synth/1/freq", "/synth/2/freq"} {
+		if err := d.Invoke(Message{Address: addr}, false); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if expected, got := "/synth/1/freq", (<-result).Address; expected != got {
+		t.Fatalf("expected %s, got %s", expected, got)
+	}
+	select {
+	case msg := <-result:
+		t.Fatalf("expected one message, got %v", msg)
+	default:
+	}
+	if _, err := d.WaitFor(context.Background(), "/foo/[a-"); err == nil {
+		t.Fatal("expected error, got nil")
+	}
+}
+
+func TestDispatcherWaitForCanceled(t *testing.T) {
+	d := Dispatcher{}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	if _, err := d.WaitFor(ctx, "/foo"); err != context.DeadlineExceeded {
+		t.Fatalf("expected %v, got %v", context.DeadlineExceeded, err)
+	}
+	waiters.Lock()
+	n := len(waiters.m[dispatcherKey(d)])
+	waiters.Unlock()
+	if n != 0 {
+		t.Fatalf("expected the waiter to be deregistered, got %d waiters", n)
+	}
+	if expected, got := 0, len(d); expected != got {
+		t.Fatalf("expected WaitFor not to change the dispatcher, got %d handlers", got)
+	}
+}
+
+func TestUDPConnServe_WaitFor(t *testing.T) {
+	laddr, err := net.ResolveUDPAddr("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	server, err := ListenUDP("udp", laddr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = server.Close() }() // Best effort.
+
+	d := Dispatcher{}
+	go func() {
+		_ = server.Serve(1, d)
+	}()
+
+	raddr, err := net.ResolveUDPAddr("udp", server.LocalAddr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	client, err := DialUDP("udp", nil, raddr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = client.Close() }() // Best effort.
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	expected := Message{Address: "/reply", Arguments: []Argument{String("ok")}}
+	go func() {
+		// Keep sending until the waiter has registered.
+		for ctx.Err() == nil {
+			_ = client.Send(expected) // Best effort.
+			time.Sleep(10 * time.Millisecond)
+		}
+	}()
+	got, err := d.WaitFor(ctx, "/reply")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !expected.Equal(got) {
+		t.Fatalf("expected %v, got %v", expected, got)
+	}
+}
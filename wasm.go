@@ -0,0 +1,36 @@
+//go:build js && wasm
+// +build js,wasm
+
+package osc
+
+import (
+	"syscall/js"
+)
+
+// ParseMessageWASM parses a message and returns it as a JSON object with the fields
+// address, typetags and arguments, or only an error field if the message can not be parsed.
+// Blob arguments are base64-encoded.
+func ParseMessageWASM(data []byte) []byte {
+	return parseMessageJSON(data, StringAddr(""))
+}
+
+// RegisterWASM exposes ParseMessageWASM to JavaScript as the global function
+// oscParseMessage(data, sender), which takes a Uint8Array and an optional sender string,
+// and returns the JSON as a string.
+func RegisterWASM() {
+	js.Global().Set("oscParseMessage", js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+		if len(args) == 0 {
+			return `{"error":"missing data"}`
+		}
+		var (
+			data   = make([]byte, args[0].Get("length").Int())
+			sender StringAddr
+		)
+		js.CopyBytesToGo(data, args[0])
+
+		if len(args) > 1 && args[1].Type() == js.TypeString {
+			sender = StringAddr(args[1].String())
+		}
+		return string(parseMessageJSON(data, sender))
+	}))
+}
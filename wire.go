@@ -0,0 +1,60 @@
+package osc
+
+import (
+	"encoding/hex"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// wireSeparator separates the fields of the wire form of a message.
+const wireSeparator = "|"
+
+// Wire returns a one-line canonical form of the message for logging and diffing captured traffic:
+// the address, the type tag string and the hex encoded arguments, separated by '|',
+// e.g. "/foo|,if|000000013f800000". Unlike Text, Wire is lossless, see ParseWire.
+func (msg Message) Wire() string {
+	var args strings.Builder
+	for _, a := range msg.Arguments {
+		args.WriteString(hex.EncodeToString(a.Bytes()))
+	}
+	return msg.Address + wireSeparator + msg.TypetagString() + wireSeparator + args.String()
+}
+
+// ParseWire parses a message from the form returned by Wire.
+func ParseWire(s string) (Message, error) {
+	// Addresses may contain the separator, type tags and hex digits do not.
+	i := strings.LastIndex(s, wireSeparator)
+	if i == -1 {
+		return Message{}, errors.Wrapf(ErrParse, "missing arguments in %q", s)
+	}
+	rest, args := s[:i], s[i+1:]
+
+	i = strings.LastIndex(rest, wireSeparator)
+	if i == -1 {
+		return Message{}, errors.Wrapf(ErrParse, "missing type tags in %q", s)
+	}
+	address, typetags := rest[:i], rest[i+1:]
+
+	if err := validateAddressPattern(address); err != nil {
+		return Message{}, err
+	}
+	tags, err := ParseTypetags(typetags)
+	if err != nil {
+		return Message{}, err
+	}
+	data, err := hex.DecodeString(args)
+	if err != nil {
+		return Message{}, errors.Wrapf(ErrParse, "arguments: %s", err)
+	}
+	// Wire is lossless, so none of the default limits apply.
+	opts := ParseOptions{MaxStringLen: -1, MaxArguments: -1}
+	arguments, n, err := appendArguments([]Argument{}, tags, data, opts, 0)
+	if err != nil {
+		return Message{}, err
+	}
+	if int(n) != len(data) {
+		return Message{}, errors.Wrapf(ErrParse, "%d trailing bytes after arguments", len(data)-int(n))
+	}
+	return Message{Address: address, Arguments: arguments}, nil
+}
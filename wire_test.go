@@ -0,0 +1,69 @@
+package osc
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/pkg/errors"
+)
+
+func TestMessageWire(t *testing.T) {
+	msg := Message{Address: "/foo", Arguments: []Argument{Int(1), Float(1), Bool(true), String("bar"), Blob{1, 2}}}
+	if expected, got := "/foo|,ifTsb|000000013f800000626172000000000201020000", msg.Wire(); expected != got {
+		t.Fatalf("expected %s, got %s", expected, got)
+	}
+}
+
+func TestParseWire(t *testing.T) {
+	for i, msg := range []Message{
+		{Address: "/foo"},
+		{Address: "/synth/*/freq", Arguments: []Argument{Float(440.125)}},
+		{Address: "/a|b", Arguments: []Argument{String("x|y")}},
+		{Address: "/floats", Arguments: []Argument{Float(float32(math.Inf(-1))), Float(math.SmallestNonzeroFloat32), Float(-0.1)}},
+		{Address: "/blobs", Arguments: []Argument{Blob{}, Blob{1}, Blob{1, 2, 3, 4, 5}, Int(-1)}},
+		{Address: "/bools", Arguments: []Argument{Bool(false), Bool(true), String("")}},
+	} {
+		got, err := ParseWire(msg.Wire())
+		if err != nil {
+			t.Fatalf("(message %d) %s", i, err)
+		}
+		if !msg.Equal(got) {
+			t.Fatalf("(message %d) expected %v, got %v", i, msg, got)
+		}
+	}
+}
+
+func TestParseWireUnlimited(t *testing.T) {
+	msg := Message{Address: "/big", Arguments: []Argument{String(strings.Repeat("x", DefaultMaxStringLen+1))}}
+	for i := 0; i < DefaultMaxArguments; i++ {
+		msg.Arguments = append(msg.Arguments, Int(i))
+	}
+	got, err := ParseWire(msg.Wire())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !msg.Equal(got) {
+		t.Fatal("expected the message to survive the round trip")
+	}
+}
+
+func TestParseWireError(t *testing.T) {
+	for _, s := range []string{
+		"",
+		"/foo",
+		"/foo|,i",
+		"foo|,|",
+		"/foo|i|00000001",
+		"/foo|,i|0000000x",
+		"/foo|,i|000000",
+		"/foo|,i|0000000100",
+	} {
+		if _, err := ParseWire(s); err == nil {
+			t.Fatalf("(%q) expected error, got nil", s)
+		}
+	}
+	if _, err := ParseWire("/foo|,i|0000000100"); errors.Cause(err) != ErrParse {
+		t.Fatalf("expected ErrParse, got %v", err)
+	}
+}
@@ -1,9 +1,17 @@
 package osc
 
 import (
+	"context"
+	"time"
+
 	"github.com/pkg/errors"
 )
 
+// Common errors.
+var (
+	ErrHandlerTimeout = errors.New("handler timed out")
+)
+
 // Worker is a worker who can process OSC messages.
 type Worker struct {
 	DataChan   chan Incoming
@@ -11,6 +19,30 @@ type Worker struct {
 	ErrChan    chan error
 	Ready      chan<- Worker
 	ExactMatch bool
+
+	// CaseInsensitive makes the worker ignore case when matching addresses.
+	CaseInsensitive bool
+
+	// Transform, if not nil, is applied to every message before it is dispatched.
+	Transform Transformer
+
+	// ParseOptions control how packets are parsed.
+	ParseOptions ParseOptions
+
+	// OnParseError, if not nil, is called for packets that can not be parsed.
+	// Otherwise parse errors are sent to ErrChan.
+	OnParseError ParseErrorHandler
+
+	// HandlerTimeout, if positive, limits how long the worker waits for the handlers
+	// of a packet. The handlers are passed a context that is done after HandlerTimeout,
+	// see Message.Context. Handlers that take longer keep running in the background
+	// while the worker moves on with an error wrapping ErrHandlerTimeout,
+	// and the packet counts as being handled until they return.
+	HandlerTimeout time.Duration
+
+	// OnDispatchError, if not nil, is called for errors returned by handlers
+	// and for handler timeouts. Otherwise these errors are sent to ErrChan.
+	OnDispatchError DispatchErrorHandler
 }
 
 // Run runs the worker.
@@ -18,29 +50,113 @@ func (w Worker) Run() {
 	w.Ready <- w
 
 	for incoming := range w.DataChan {
-		data := incoming.Data
+		w.handle(incoming)
+		w.Ready <- w
+	}
+}
 
-		switch data[0] {
-		case BundleTag[0]:
-			bundle, err := ParseBundle(data, incoming.Sender)
-			if err != nil {
-				w.ErrChan <- err
-			}
-			if err := w.Dispatcher.Dispatch(bundle, w.ExactMatch); err != nil {
-				w.ErrChan <- errors.Wrap(err, "dispatch bundle")
+// handle parses and dispatches the incoming data.
+// The data is marked as handled once its handlers returned, see dispatch.
+func (w Worker) handle(incoming Incoming) {
+	p := incoming.packet
+	if p == nil {
+		var err error
+		if p, err = parsePacketWith(incoming.Data, incoming.Sender, w.ParseOptions); err != nil {
+			w.parseError(incoming, err)
+			incoming.finish()
+			return
+		}
+	}
+	switch x := p.(type) {
+	case Bundle:
+		bundle := x.setSeq(incoming.Seq)
+		if w.Transform != nil {
+			var err error
+			if bundle, err = transformBundle(bundle, w.Transform); err != nil {
+				w.ErrChan <- errors.Wrap(err, "transform bundle")
+				break
 			}
-		case MessageChar:
-			msg, err := ParseMessage(data, incoming.Sender)
-			if err != nil {
-				w.ErrChan <- err
+		}
+		w.dispatch(bundle, incoming, func(ctx context.Context) error {
+			return errors.Wrap(w.Dispatcher.DispatchWith(bundle.withContext(ctx), w.matchOptions()), "dispatch bundle")
+		})
+		return
+	case Message:
+		msg := x
+		msg.Seq = incoming.Seq
+		if w.Transform != nil {
+			var (
+				keep bool
+				err  error
+			)
+			if msg, keep, err = w.Transform(msg); err != nil {
+				w.ErrChan <- errors.Wrap(err, "transform message")
+				break
 			}
-			if err := w.Dispatcher.Invoke(msg, w.ExactMatch); err != nil {
-				w.ErrChan <- errors.Wrap(err, "dispatch message")
+			if !keep {
+				break
 			}
-		default:
-			w.ErrChan <- ErrParse
 		}
-		// Announce the worker is ready again.
-		w.Ready <- w
+		w.dispatch(msg, incoming, func(ctx context.Context) error {
+			return errors.Wrap(w.Dispatcher.InvokeWith(msg.WithContext(ctx), w.matchOptions()), "dispatch message")
+		})
+		return
+	}
+	incoming.finish()
+}
+
+// dispatch calls fn to dispatch the packet and reports the error if there is one.
+// If HandlerTimeout is positive, fn is passed a context that is done after HandlerTimeout,
+// and the worker stops waiting for fn then with an error wrapping ErrHandlerTimeout.
+// Either way the incoming data is marked as handled only once fn returned,
+// so shutting down waits for handlers that are still running.
+func (w Worker) dispatch(p Packet, incoming Incoming, fn func(ctx context.Context) error) {
+	if w.HandlerTimeout <= 0 {
+		w.dispatchError(p, fn(context.Background()))
+		incoming.finish()
+		return
+	}
+	var (
+		ctx, cancel = context.WithTimeout(context.Background(), w.HandlerTimeout)
+		done        = make(chan error, 1) // Buffered so fn can return after the timeout.
+	)
+	defer cancel()
+
+	go func() {
+		defer incoming.finish()
+		done <- fn(ctx)
+	}()
+
+	select {
+	case err := <-done:
+		w.dispatchError(p, err)
+	case <-ctx.Done():
+		w.dispatchError(p, errors.Wrapf(ErrHandlerTimeout, "after %s", w.HandlerTimeout))
+	}
+}
+
+// dispatchError reports an error dispatching the packet if err is not nil.
+func (w Worker) dispatchError(p Packet, err error) {
+	if err == nil {
+		return
+	}
+	if w.OnDispatchError != nil {
+		w.OnDispatchError(p, err)
+		return
+	}
+	w.ErrChan <- err
+}
+
+// parseError reports that the incoming data could not be parsed.
+func (w Worker) parseError(incoming Incoming, err error) {
+	if w.OnParseError != nil {
+		w.OnParseError(incoming.Data, incoming.Sender, err)
+		return
 	}
+	w.ErrChan <- err
+}
+
+// matchOptions returns the options the worker uses to match addresses.
+func (w Worker) matchOptions() MatchOptions {
+	return MatchOptions{Exact: w.ExactMatch, CaseInsensitive: w.CaseInsensitive}
 }